@@ -239,9 +239,10 @@ func getFrames(w http.ResponseWriter, r *http.Request, ps httprouter.Params, c p
 		limit = 100
 	}
 	req := &pb.ListGameFramesRequest{
-		ID:     id,
-		Offset: int32(offset),
-		Limit:  int32(limit),
+		ID:      id,
+		Offset:  int32(offset),
+		Limit:   int32(limit),
+		Reverse: r.URL.Query().Get("order") == "desc",
 	}
 	// TODO: use a context with timeout
 	resp, err := c.ListGameFrames(r.Context(), req)
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/battlesnakeio/engine/controller"
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// storeHandle is a function that handles an http route and accepts a Store
+// in addition to the normal httprouter.Handle parameters.
+type storeHandle func(http.ResponseWriter, *http.Request, httprouter.Params, controller.Store)
+
+// NewStoreHandler returns a read only http.Handler that serves games and
+// frames straight from a store, without going through the controller. It
+// serves:
+//
+//	GET /games/:id                        the game
+//	GET /games/:id/frames?offset=&limit=  the frames of the game
+func NewStoreHandler(s controller.Store) http.Handler {
+	router := httprouter.New()
+	router.GET("/games/:id", logging(newStoreHandle(s, storeGetGame)))
+	router.GET("/games/:id/frames", logging(newStoreHandle(s, storeGetFrames)))
+	return router
+}
+
+func newStoreHandle(s controller.Store, innerHandle storeHandle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		innerHandle(w, r, p, s)
+	}
+}
+
+func storeGetGame(w http.ResponseWriter, r *http.Request, ps httprouter.Params, s controller.Store) {
+	id := ps.ByName("id")
+	game, err := s.GetGame(r.Context(), id)
+	if err != nil {
+		writeError(w, err, storeErrorStatus(err), "Error while reading game", log.Fields{
+			"id": id,
+		})
+		return
+	}
+	writeJSON(w, game)
+}
+
+func storeGetFrames(w http.ResponseWriter, r *http.Request, ps httprouter.Params, s controller.Store) {
+	id := ps.ByName("id")
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest, "Invalid offset", log.Fields{"id": id})
+		return
+	}
+	if offset < 0 {
+		err = fmt.Errorf("offset must not be negative")
+		writeError(w, err, http.StatusBadRequest, "Invalid offset", log.Fields{"id": id})
+		return
+	}
+	limit, err := queryInt(r, "limit", controller.MaxTicks)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest, "Invalid limit", log.Fields{"id": id})
+		return
+	}
+	if limit <= 0 || limit > controller.MaxTicks {
+		err = fmt.Errorf("limit must be between 1 and %d", controller.MaxTicks)
+		writeError(w, err, http.StatusBadRequest, "Invalid limit", log.Fields{"id": id})
+		return
+	}
+
+	frames, err := s.ListGameFrames(r.Context(), id, limit, offset)
+	if err != nil {
+		writeError(w, err, storeErrorStatus(err), "Error while reading frames", log.Fields{
+			"id": id,
+		})
+		return
+	}
+	writeJSON(w, &pb.ListGameFramesResponse{
+		Frames: frames,
+		Count:  int32(len(frames)),
+	})
+}
+
+// queryInt parses an integer query parameter, returning def when it is not
+// set.
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	i, err := strconv.ParseInt(v, 10, 32)
+	return int(i), err
+}
+
+// storeErrorStatus maps a store error to an http status code.
+func storeErrorStatus(err error) int {
+	if err == controller.ErrNotFound {
+		return http.StatusNotFound
+	}
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, msg proto.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	m := jsonpb.Marshaler{EmitDefaults: true}
+	err := m.Marshal(w, msg)
+	if err != nil {
+		log.WithError(err).Error("Unable to write response to stream")
+	}
+}
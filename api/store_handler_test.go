@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller"
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/stretchr/testify/require"
+)
+
+func createStoreHandler(t *testing.T) http.Handler {
+	s := controller.InMemStore()
+	err := s.CreateGame(context.Background(), &pb.Game{ID: "abc", Width: 11}, []*pb.GameFrame{
+		{Turn: 0}, {Turn: 1}, {Turn: 2},
+	})
+	require.NoError(t, err)
+	return NewStoreHandler(s)
+}
+
+func storeRequest(h http.Handler, url string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", url, nil)
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestStoreHandlerGetGame(t *testing.T) {
+	rr := storeRequest(createStoreHandler(t), "/games/abc")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	game := &pb.Game{}
+	require.NoError(t, jsonpb.Unmarshal(rr.Body, game))
+	require.Equal(t, "abc", game.ID)
+	require.Equal(t, int32(11), game.Width)
+}
+
+func TestStoreHandlerGetGameNotFound(t *testing.T) {
+	rr := storeRequest(createStoreHandler(t), "/games/missing")
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestStoreHandlerGetFrames(t *testing.T) {
+	rr := storeRequest(createStoreHandler(t), "/games/abc/frames?offset=1&limit=1")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	resp := &pb.ListGameFramesResponse{}
+	require.NoError(t, jsonpb.Unmarshal(rr.Body, resp))
+	require.Len(t, resp.Frames, 1)
+	require.Equal(t, int32(1), resp.Frames[0].Turn)
+}
+
+func TestStoreHandlerGetFramesDefaults(t *testing.T) {
+	rr := storeRequest(createStoreHandler(t), "/games/abc/frames")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	resp := &pb.ListGameFramesResponse{}
+	require.NoError(t, jsonpb.Unmarshal(rr.Body, resp))
+	require.Equal(t, int32(3), resp.Count)
+}
+
+func TestStoreHandlerGetFramesNotFound(t *testing.T) {
+	rr := storeRequest(createStoreHandler(t), "/games/missing/frames")
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestStoreHandlerGetFramesInvalidQuery(t *testing.T) {
+	h := createStoreHandler(t)
+	for _, url := range []string{
+		"/games/abc/frames?offset=x",
+		"/games/abc/frames?offset=-1",
+		"/games/abc/frames?limit=x",
+		"/games/abc/frames?limit=-1",
+		"/games/abc/frames?limit=1000",
+	} {
+		rr := storeRequest(h, url)
+		require.Equal(t, http.StatusBadRequest, rr.Code, url)
+	}
+}
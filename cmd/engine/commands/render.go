@@ -59,10 +59,23 @@ func render(game *pb.Game, frame *pb.GameFrame) error {
 	return termbox.Flush()
 }
 
+// headGlyphs point the way a snake is facing, see pb.Snake.NeckDirection.
+var headGlyphs = map[pb.Move]rune{
+	pb.MoveUp:    '▲',
+	pb.MoveDown:  '▼',
+	pb.MoveLeft:  '◀',
+	pb.MoveRight: '▶',
+}
+
 func renderSnake(left, top int, s *pb.Snake) {
 	for _, b := range s.Body {
 		termbox.SetCell(left+int(b.X), top+int(b.Y)+1, ' ', snakeColor, snakeColor)
 	}
+	if head := s.Head(); head != nil {
+		if glyph, ok := headGlyphs[pb.Move(s.NeckDirection())]; ok {
+			termbox.SetCell(left+int(head.X), top+int(head.Y)+1, glyph, defaultColor, snakeColor)
+		}
+	}
 }
 
 func renderFood(left, top int, food []*pb.Point) {
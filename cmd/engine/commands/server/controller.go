@@ -3,6 +3,7 @@ package server
 import (
 	"io"
 	"os"
+	"time"
 
 	"github.com/battlesnakeio/engine/controller"
 	"github.com/battlesnakeio/engine/controller/filestore"
@@ -15,12 +16,31 @@ var (
 	controllerListen      = ":3004"
 	controllerBackend     = "inmem"
 	controllerBackendArgs = ""
+
+	redisMaxRetries      = 0
+	redisMinRetryBackoff = 8 * time.Millisecond
+	redisMaxRetryBackoff = 512 * time.Millisecond
+	redisPopMaxBlock     = time.Duration(0)
+	redisPopPollInterval = redis.DefaultPopPollInterval
+	redisVerifyChecksums = false
+	redisFramesByTurn    = false
+	redisMaxFrameBytes   = 0
+	redisMaxFrameSnakes  = 0
 )
 
 func init() {
 	controllerCmd.Flags().StringVarP(&controllerListen, "listen", "l", controllerListen, "address for the controller to bind to")
 	controllerCmd.Flags().StringVarP(&controllerBackend, "backend", "b", controllerBackend, "controller backend, as one of: [inmem, file, redis]")
 	controllerCmd.Flags().StringVarP(&controllerBackendArgs, "backend-args", "a", controllerBackendArgs, "options to pass to the backend being used")
+	controllerCmd.Flags().IntVar(&redisMaxRetries, "redis-max-retries", redisMaxRetries, "times the redis backend retries a command that failed on a network error")
+	controllerCmd.Flags().DurationVar(&redisMinRetryBackoff, "redis-min-retry-backoff", redisMinRetryBackoff, "first backoff between redis retries")
+	controllerCmd.Flags().DurationVar(&redisMaxRetryBackoff, "redis-max-retry-backoff", redisMaxRetryBackoff, "longest backoff between redis retries")
+	controllerCmd.Flags().DurationVar(&redisPopMaxBlock, "redis-pop-max-block", redisPopMaxBlock, "longest time the redis backend waits for a game to pop, 0 returns immediately")
+	controllerCmd.Flags().DurationVar(&redisPopPollInterval, "redis-pop-poll-interval", redisPopPollInterval, "how often the redis backend looks for a game while waiting to pop")
+	controllerCmd.Flags().BoolVar(&redisVerifyChecksums, "redis-verify-checksums", redisVerifyChecksums, "check frames read from the redis backend against their checksum")
+	controllerCmd.Flags().BoolVar(&redisFramesByTurn, "redis-frames-by-turn", redisFramesByTurn, "store frames in a hash keyed by turn instead of a list, do not change for existing data")
+	controllerCmd.Flags().IntVar(&redisMaxFrameBytes, "redis-max-frame-bytes", redisMaxFrameBytes, "largest frame the redis backend accepts, in bytes, 0 for no limit")
+	controllerCmd.Flags().IntVar(&redisMaxFrameSnakes, "redis-max-frame-snakes", redisMaxFrameSnakes, "most snakes in a frame the redis backend accepts, 0 for no limit")
 	RootCmd.Flags().AddFlagSet(controllerCmd.Flags())
 }
 
@@ -36,7 +56,18 @@ var controllerCmd = &cobra.Command{
 		case "file":
 			store = filestore.NewFileStore(controllerBackendArgs)
 		case "redis":
-			store, err = redis.NewStore(controllerBackendArgs)
+			opts := []redis.Option{
+				redis.WithRetries(redisMaxRetries, redisMinRetryBackoff, redisMaxRetryBackoff),
+				redis.WithBlockingPop(redisPopMaxBlock, redisPopPollInterval),
+				redis.WithFrameLimits(redisMaxFrameBytes, redisMaxFrameSnakes),
+			}
+			if redisVerifyChecksums {
+				opts = append(opts, redis.WithChecksumVerification())
+			}
+			if redisFramesByTurn {
+				opts = append(opts, redis.WithFramesByTurn())
+			}
+			store, err = redis.NewStore(controllerBackendArgs, opts...)
 		default:
 			log.WithField("backend", controllerBackend).Fatal("invalid backend")
 		}
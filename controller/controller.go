@@ -56,14 +56,15 @@ func (s *Server) ValidateSnake(ctx context.Context, req *pb.ValidateSnakeRequest
 
 // Pop should pop a game that is unlocked and unfinished from the queue, lock
 // the game and return it to the worker to begin processing. This call will
-// be polled by the workers.
-func (s *Server) Pop(ctx context.Context, _ *pb.PopRequest) (*pb.PopResponse, error) {
+// be polled by the workers. The worker id of the request is recorded in the
+// lock token.
+func (s *Server) Pop(ctx context.Context, req *pb.PopRequest) (*pb.PopResponse, error) {
 	id, err := s.Store.PopGameID(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	token, err := s.Store.Lock(ctx, id, "")
+	token, err := s.Store.Lock(ctx, id, NewLockToken(req.WorkerID))
 	if err != nil {
 		return nil, err
 	}
@@ -73,18 +74,10 @@ func (s *Server) Pop(ctx context.Context, _ *pb.PopRequest) (*pb.PopResponse, er
 
 // Status retrieves the game state including the last processed game frame.
 func (s *Server) Status(ctx context.Context, req *pb.StatusRequest) (*pb.StatusResponse, error) {
-	game, err := s.Store.GetGame(ctx, req.ID)
-	if err != nil {
-		return nil, err
-	}
-	var lastFrame *pb.GameFrame
-	frames, err := s.Store.ListGameFrames(ctx, req.ID, 1, -1)
+	game, lastFrame, err := s.Store.GetGameAndLastFrame(ctx, req.ID)
 	if err != nil {
 		return nil, err
 	}
-	if len(frames) > 0 {
-		lastFrame = frames[0]
-	}
 	return &pb.StatusResponse{Game: game, LastFrame: lastFrame}, nil
 }
 
@@ -113,8 +106,40 @@ func (s *Server) Create(ctx context.Context, req *pb.CreateRequest) (*pb.CreateR
 	}, nil
 }
 
+// CreateGameFromFrame creates a new game that starts from initial, see
+// rules.CreateGameFromFrame. Like Create it does not start running frames.
+func (s *Server) CreateGameFromFrame(ctx context.Context, game *pb.Game, initial *pb.GameFrame) (string, error) {
+	forked, frames, err := rules.CreateGameFromFrame(game, initial)
+	if err != nil {
+		return "", err
+	}
+	if err := s.Store.CreateGame(ctx, forked, frames); err != nil {
+		return "", err
+	}
+	return forked.ID, nil
+}
+
+// GameSnakeIDs returns the IDs of every snake that played the game, in the
+// order they joined. Snakes only join when a game is created and stay in
+// every frame once they die, so only the first frame is read.
+func (s *Server) GameSnakeIDs(ctx context.Context, id string) ([]string, error) {
+	frames, err := s.Store.ListGameFrames(ctx, id, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	if len(frames) == 0 {
+		return ids, nil
+	}
+	for _, snake := range frames[0].Snakes {
+		ids = append(ids, snake.ID)
+	}
+	return ids, nil
+}
+
 // AddGameFrame adds a new game frame to the game. A lock must be held for this
-// call to succeed.
+// call to succeed. Only the status of the game is read, the game of the
+// response has its ID and status set.
 func (s *Server) AddGameFrame(ctx context.Context, req *pb.AddGameFrameRequest) (*pb.AddGameFrameResponse, error) {
 	token := pb.ContextGetLockToken(ctx)
 
@@ -122,31 +147,54 @@ func (s *Server) AddGameFrame(ctx context.Context, req *pb.AddGameFrameRequest)
 		return nil, status.Error(codes.InvalidArgument, "controller: game frame must not be nil")
 	}
 
-	// Lock the game again, if this fails, the lock is not valid.
-	_, err := s.Store.Lock(ctx, req.ID, token)
+	// Lock the game again, if this fails, the lock is not valid. The frame is
+	// written with the token of the lock, so a request without one is fenced
+	// too.
+	token, err := s.Store.Lock(ctx, req.ID, token)
 	if err != nil {
 		return nil, err
 	}
 
-	err = s.Store.PushGameFrame(ctx, req.ID, req.GameFrame)
+	// A paused game keeps its frames as they are until it is resumed.
+	gameStatus, err := s.Store.GetGameStatus(ctx, req.ID)
 	if err != nil {
 		return nil, err
 	}
-	game, err := s.Store.GetGame(ctx, req.ID)
+	if gameStatus == string(rules.GameStatusPaused) {
+		// Release the lock, so the game is free to be popped once resumed.
+		if err := s.Store.Unlock(ctx, req.ID, token); err != nil {
+			return nil, err
+		}
+		return nil, ErrIsPaused
+	}
+	// A worker that was still running an aborted game took the lock again.
+	if gameStatus == string(rules.GameStatusError) {
+		if err := s.Store.Unlock(ctx, req.ID, token); err != nil {
+			return nil, err
+		}
+		return nil, ErrIsAborted
+	}
+
+	err = s.Store.PushGameFrame(ctx, req.ID, token, req.GameFrame)
 	if err != nil {
 		return nil, err
 	}
 	return &pb.AddGameFrameResponse{
-		Game: game,
+		Game: &pb.Game{ID: req.ID, Status: gameStatus},
 	}, nil
 }
 
-// ListGameFrames will list all game frames given a limit and offset.
+// ListGameFrames will list all game frames given a limit and offset. Frames
+// are listed newest first when the request asks for them in reverse.
 func (s *Server) ListGameFrames(ctx context.Context, req *pb.ListGameFramesRequest) (*pb.ListGameFramesResponse, error) {
 	if req.Limit == 0 || req.Limit >= MaxTicks {
 		req.Limit = MaxTicks
 	}
-	frames, err := s.Store.ListGameFrames(ctx, req.ID, int(req.Limit), int(req.Offset))
+	list := s.Store.ListGameFrames
+	if req.Reverse {
+		list = s.Store.ListGameFramesReverse
+	}
+	frames, err := list(ctx, req.ID, int(req.Limit), int(req.Offset))
 	if err != nil {
 		return nil, err
 	}
@@ -156,6 +204,73 @@ func (s *Server) ListGameFrames(ctx context.Context, req *pb.ListGameFramesReque
 	}, nil
 }
 
+// PauseGame stops workers from producing frames for a running game, without
+// ending it. The worker holding the game releases its lock on its next frame.
+func (s *Server) PauseGame(ctx context.Context, req *pb.PauseGameRequest) (*pb.PauseGameResponse, error) {
+	err := s.Store.SetGameStatus(ctx, req.ID, rules.GameStatusPaused)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PauseGameResponse{}, nil
+}
+
+// ResumeGame makes a paused game ready to be picked up by a worker again.
+// Only paused games can be resumed, use Start for a stopped game.
+func (s *Server) ResumeGame(ctx context.Context, req *pb.ResumeGameRequest) (*pb.ResumeGameResponse, error) {
+	game, err := s.Store.GetGame(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Status != string(rules.GameStatusPaused) {
+		return nil, ErrInvalidTransition
+	}
+	err = s.Store.SetGameStatus(ctx, req.ID, rules.GameStatusRunning)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ResumeGameResponse{}, nil
+}
+
+// AbortGame ends a game with the error status, for operators to stop a game
+// that misbehaves. The lock of the worker running the game is taken over, so
+// the worker's next frame is refused and it stops. The game ends on
+// rules.AbortFrame with the reason, written together with the status. Complete
+// and errored games can not be aborted, ErrInvalidTransition is returned.
+func (s *Server) AbortGame(ctx context.Context, req *pb.AbortGameRequest) (*pb.AbortGameResponse, error) {
+	if err := s.Store.ForceUnlock(ctx, req.ID); err != nil && err != ErrNotFound {
+		return nil, err
+	}
+	token, err := s.Store.Lock(ctx, req.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	err = s.abortGame(ctx, req.ID, token, req.Reason)
+	if unlockErr := s.Store.Unlock(ctx, req.ID, token); err == nil {
+		err = unlockErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AbortGameResponse{}, nil
+}
+
+func (s *Server) abortGame(ctx context.Context, id, token, reason string) error {
+	game, last, err := s.Store.GetGameAndLastFrame(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !rules.CanTransition(rules.GameStatus(game.Status), rules.GameStatusError) || game.Status == string(rules.GameStatusError) {
+		return ErrInvalidTransition
+	}
+	return s.Store.WithTx(ctx, func(tx *StoreTx) error {
+		if last != nil {
+			tx.PushGameFrame(id, token, rules.AbortFrame(last, reason))
+		}
+		tx.SetGameStatus(id, rules.GameStatusError)
+		return nil
+	})
+}
+
 // EndGame sets the game status to complete. A lock must be held for this call
 // to succeed.
 func (s *Server) EndGame(ctx context.Context, req *pb.EndGameRequest) (*pb.EndGameResponse, error) {
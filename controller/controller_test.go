@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -46,6 +47,12 @@ func TestController_GameCRUD(t *testing.T) {
 		)
 	})
 
+	t.Run("Create_InvalidTimeout", func(t *testing.T) {
+		_, err := client.Create(ctx, &pb.CreateRequest{SnakeTimeout: -1})
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), "invalid snake timeout")
+	})
+
 	t.Run("Create", func(t *testing.T) {
 		resp, err := client.Create(ctx, &pb.CreateRequest{})
 		require.Nil(t, err)
@@ -77,8 +84,8 @@ func TestController_GameCRUD(t *testing.T) {
 		game, err := client.Status(ctx, &pb.StatusRequest{ID: gameID})
 		require.Nil(t, err)
 		require.Equal(t, "running", game.Game.Status)
-		require.Equal(t, int32(1000), game.Game.SnakeTimeout)
-		require.Equal(t, "multi-player", game.Game.Mode)
+		require.Equal(t, int32(1000), game.Game.Ruleset.SnakeTimeout)
+		require.Equal(t, "multi-player", game.Game.Ruleset.Mode)
 		require.NotNil(t, game.LastFrame)
 		require.Equal(t, int32(0), game.LastFrame.Turn)
 	})
@@ -186,9 +193,61 @@ func TestController_Frames(t *testing.T) {
 				})
 			require.Nil(t, err)
 			require.Equal(t, gameID, resp.Game.ID)
+			require.Equal(t, string(rules.GameStatusRunning), resp.Game.Status)
 		}
 	})
 
+	t.Run("AddGameFrame_Paused", func(t *testing.T) {
+		err := store.SetGameStatus(ctx, gameID, rules.GameStatusPaused)
+		require.Nil(t, err)
+		defer func() {
+			err := store.SetGameStatus(ctx, gameID, rules.GameStatusRunning)
+			require.Nil(t, err)
+			// Take back the lock the paused game released.
+			_, err = store.Lock(ctx, gameID, token)
+			require.Nil(t, err)
+		}()
+
+		_, err = client.AddGameFrame(
+			pb.ContextWithLockToken(ctx, token), &pb.AddGameFrameRequest{
+				ID:        gameID,
+				GameFrame: &pb.GameFrame{Turn: 101},
+			})
+		require.NotNil(t, err)
+		require.Equal(t,
+			"rpc error: code = FailedPrecondition desc = controller: game is paused",
+			err.Error(),
+		)
+
+		// The lock is released, so the game can be popped once resumed.
+		other, err := store.Lock(ctx, gameID, "")
+		require.Nil(t, err)
+		require.Nil(t, store.Unlock(ctx, gameID, other))
+	})
+
+	t.Run("ResumeGame_NotPaused", func(t *testing.T) {
+		_, err := client.ResumeGame(ctx, &pb.ResumeGameRequest{ID: gameID})
+		require.NotNil(t, err)
+		require.Equal(t,
+			"rpc error: code = FailedPrecondition desc = controller: invalid game status transition",
+			err.Error(),
+		)
+	})
+
+	t.Run("PauseResumeGame", func(t *testing.T) {
+		_, err := client.PauseGame(ctx, &pb.PauseGameRequest{ID: gameID})
+		require.Nil(t, err)
+		game, err := client.Status(ctx, &pb.StatusRequest{ID: gameID})
+		require.Nil(t, err)
+		require.Equal(t, string(rules.GameStatusPaused), game.Game.Status)
+
+		_, err = client.ResumeGame(ctx, &pb.ResumeGameRequest{ID: gameID})
+		require.Nil(t, err)
+		game, err = client.Status(ctx, &pb.StatusRequest{ID: gameID})
+		require.Nil(t, err)
+		require.Equal(t, string(rules.GameStatusRunning), game.Game.Status)
+	})
+
 	t.Run("ListGameFrames_NoGame", func(t *testing.T) {
 		_, err := client.ListGameFrames(ctx, &pb.ListGameFramesRequest{ID: "foo"})
 		require.NotNil(t, err)
@@ -212,6 +271,22 @@ func TestController_Frames(t *testing.T) {
 		require.Equal(t, MaxTicks, int(resp.Count))
 	})
 
+	t.Run("ListGameFramesReverse", func(t *testing.T) {
+		resp, err := client.ListGameFrames(ctx, &pb.ListGameFramesRequest{
+			ID:      gameID,
+			Limit:   3,
+			Offset:  1,
+			Reverse: true,
+		})
+		require.Nil(t, err)
+		require.Equal(t, 3, len(resp.Frames))
+		last := resp.Frames[0].Turn
+		for _, f := range resp.Frames[1:] {
+			require.Equal(t, last-1, f.Turn)
+			last = f.Turn
+		}
+	})
+
 	t.Run("ListGameFramesAtMaxTicks", func(t *testing.T) {
 		resp, err := client.ListGameFrames(ctx, &pb.ListGameFramesRequest{
 			ID:    gameID,
@@ -254,6 +329,120 @@ func TestController_PopConcurrent(t *testing.T) {
 	require.Equal(t, uint32(1), ok)
 }
 
+func TestController_CreateGameFromFrame(t *testing.T) {
+	ctx := context.Background()
+	ctrl := New(InMemStore())
+	game := &pb.Game{ID: "original", Width: 5, Height: 5, Status: string(rules.GameStatusComplete), RulesetVersion: rules.CurrentRulesetVersion}
+	frame := &pb.GameFrame{
+		Turn: 12,
+		Food: []*pb.Point{{X: 0, Y: 0}},
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 80, Body: []*pb.Point{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}}},
+		},
+	}
+
+	id, err := ctrl.CreateGameFromFrame(ctx, game, frame)
+	require.Nil(t, err)
+	require.NotEqual(t, "original", id)
+	forked, last, err := ctrl.Store.GetGameAndLastFrame(ctx, id)
+	require.Nil(t, err)
+	require.Equal(t, string(rules.GameStatusStopped), forked.Status)
+	require.Equal(t, int32(0), last.Turn)
+	require.Equal(t, frame.Snakes, last.Snakes)
+	require.Equal(t, int32(12), frame.Turn, "the frame passed in is not changed")
+
+	frame.Snakes[0].Body[2].X = 3
+	_, err = ctrl.CreateGameFromFrame(ctx, game, frame)
+	require.True(t, errors.Is(err, rules.ErrInvalidBoard))
+	require.Contains(t, err.Error(), "segment 2 at (3,2) is not next to (1,2)")
+}
+
+func TestController_GameSnakeIDs(t *testing.T) {
+	ctx := context.Background()
+	ctrl := New(InMemStore())
+	snakes := func(dead ...string) []*pb.Snake {
+		snakes := []*pb.Snake{{ID: "zed"}, {ID: "amy"}, {ID: "bob"}}
+		for _, s := range snakes {
+			for _, id := range dead {
+				if s.ID == id {
+					s.Death = &pb.Death{Cause: rules.DeathCauseWallCollision, Turn: 1}
+				}
+			}
+		}
+		return snakes
+	}
+	game := &pb.Game{ID: "played", Status: string(rules.GameStatusComplete)}
+	frames := []*pb.GameFrame{
+		{Turn: 0, Snakes: snakes()},
+		{Turn: 1, Snakes: snakes("amy")},
+		{Turn: 2, Snakes: snakes("amy", "zed")},
+	}
+	require.Nil(t, ctrl.Store.CreateGame(ctx, game, frames))
+
+	ids, err := ctrl.GameSnakeIDs(ctx, game.ID)
+	require.Nil(t, err)
+	require.Equal(t, []string{"zed", "amy", "bob"}, ids)
+
+	require.Nil(t, ctrl.Store.CreateGame(ctx, &pb.Game{ID: "empty"}, nil))
+	ids, err = ctrl.GameSnakeIDs(ctx, "empty")
+	require.Nil(t, err)
+	require.Empty(t, ids)
+
+	_, err = ctrl.GameSnakeIDs(ctx, "missing")
+	require.Equal(t, ErrNotFound, err)
+}
+
+func TestController_AbortGame(t *testing.T) {
+	ctx := context.Background()
+	game := &pb.Game{ID: "aborted", Width: 5, Height: 5, Status: string(rules.GameStatusRunning)}
+	frame := &pb.GameFrame{
+		Snakes: []*pb.Snake{{ID: "1", Health: 100, Body: []*pb.Point{{X: 1, Y: 1}}}},
+	}
+	require.Nil(t, store.CreateGame(ctx, game, []*pb.GameFrame{frame}))
+
+	// A worker is running the game.
+	token, err := store.Lock(ctx, game.ID, "")
+	require.Nil(t, err)
+	_, err = client.AddGameFrame(pb.ContextWithLockToken(ctx, token), &pb.AddGameFrameRequest{
+		ID:        game.ID,
+		GameFrame: &pb.GameFrame{Turn: 1, Snakes: frame.Snakes},
+	})
+	require.Nil(t, err)
+
+	_, err = client.AbortGame(ctx, &pb.AbortGameRequest{ID: game.ID, Reason: "snake server misbehaving"})
+	require.Nil(t, err)
+
+	status, err := store.GetGameStatus(ctx, game.ID)
+	require.Nil(t, err)
+	require.Equal(t, string(rules.GameStatusError), status)
+	_, last, err := store.GetGameAndLastFrame(ctx, game.ID)
+	require.Nil(t, err)
+	require.Equal(t, int32(2), last.Turn)
+	require.True(t, rules.IsAbortFrame(last))
+	require.Equal(t, "snake server misbehaving", last.Events[0].Cause)
+
+	// The worker's lock was taken over and released.
+	other, err := store.Lock(ctx, game.ID, "")
+	require.Nil(t, err)
+	require.Nil(t, store.Unlock(ctx, game.ID, other))
+
+	// The worker can not add any more frames.
+	_, err = client.AddGameFrame(pb.ContextWithLockToken(ctx, token), &pb.AddGameFrameRequest{
+		ID:        game.ID,
+		GameFrame: &pb.GameFrame{Turn: 2},
+	})
+	require.Equal(t, ErrIsAborted, err)
+	frames, err := store.ListGameFrames(ctx, game.ID, 10, 0)
+	require.Nil(t, err)
+	require.Len(t, frames, 3)
+
+	// An aborted game is final.
+	_, err = client.AbortGame(ctx, &pb.AbortGameRequest{ID: game.ID})
+	require.Equal(t, ErrInvalidTransition, err)
+	_, err = client.AbortGame(ctx, &pb.AbortGameRequest{ID: "missing"})
+	require.Equal(t, ErrNotFound, err)
+}
+
 func TestController_Ping(t *testing.T) {
 	ctx := context.Background()
 
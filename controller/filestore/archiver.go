@@ -0,0 +1,51 @@
+package filestore
+
+import (
+	"context"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	log "github.com/sirupsen/logrus"
+)
+
+// FileArchiver archives finished games to a directory, one file per game in
+// the same format the file store uses. Archived games can be read back with
+// ReadGameInfo and ReadGameFrames.
+type FileArchiver struct {
+	directory string
+}
+
+// NewFileArchiver returns an archiver writing to directory.
+func NewFileArchiver(directory string) *FileArchiver {
+	if directory == "" {
+		directory = defaultDir()
+	}
+	return &FileArchiver{directory: directory}
+}
+
+// Archive writes the game and its frames to the archive of the game, replacing
+// the archive if the game was archived before.
+func (a *FileArchiver) Archive(ctx context.Context, gameID string, game *pb.Game, frames []*pb.GameFrame) error {
+	w, err := openFileRewriter(a.directory, gameID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := w.Close(); err != nil {
+			log.WithError(err).Error("Error while closing file writer")
+		}
+	}()
+
+	var snakes []*pb.Snake
+	if len(frames) > 0 {
+		snakes = frames[0].Snakes
+	}
+	if err := writeGameInfo(w, game, snakes); err != nil {
+		return err
+	}
+	for _, f := range frames {
+		if err := writeFrame(w, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package filestore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileArchiver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	openFileRewriter = truncatingFileWriter
+	openFileReader = fsOpenFileReader
+
+	a := NewFileArchiver(dir)
+	err = a.Archive(context.Background(), "myid", basicGame(), basicFrames())
+	require.NoError(t, err)
+
+	game, err := ReadGameInfo(dir, "myid")
+	require.NoError(t, err)
+	require.Equal(t, basicGame(), game)
+	frames, err := ReadGameFrames(dir, "myid")
+	require.NoError(t, err)
+	require.Equal(t, basicFrames(), frames)
+
+	// Archiving again replaces the archive.
+	err = a.Archive(context.Background(), "myid", basicGame(), basicFrames()[:1])
+	require.NoError(t, err)
+	frames, err = ReadGameFrames(dir, "myid")
+	require.NoError(t, err)
+	require.Equal(t, basicFrames()[:1], frames)
+}
@@ -137,6 +137,47 @@ func readArchive(dir string, id string) (gameArchive, error) {
 	}, nil
 }
 
+// archiveIsCurrent reports whether every line of an archive is encoded the way
+// it would be written now, so rewriting it would not change anything.
+func archiveIsCurrent(dir string, id string) (bool, error) {
+	r, err := openFileReader(dir, id)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		err = r.Close()
+		if err != nil {
+			log.WithError(err).Error("Error while closing reader")
+		}
+	}()
+
+	var out interface{} = &pb.Game{}
+	for {
+		line, err := r.ReadBytes('\n')
+		eof := err == io.EOF
+		if err != nil && !eof {
+			return false, err
+		}
+		if containsJSONObject(string(line)) {
+			if err := json.Unmarshal(line, out); err != nil {
+				return false, err
+			}
+			encoded, err := encodeLine(out)
+			if err != nil {
+				return false, err
+			}
+			if encoded != string(line) {
+				return false, nil
+			}
+			// Every line after the header is a frame.
+			out = &pb.GameFrame{}
+		}
+		if eof {
+			return true, nil
+		}
+	}
+}
+
 // ReadGameFrames loads all the game frames stored in given file.
 func ReadGameFrames(dir string, id string) ([]*pb.GameFrame, error) {
 	archive, err := readArchive(dir, id)
@@ -110,6 +110,26 @@ func (fs *fileStore) isLocked(key string) bool {
 	return ok && l.expires.After(time.Now())
 }
 
+func (fs *fileStore) holdsLock(key, token string) bool {
+	l, ok := fs.locks[key]
+	return ok && l.token == token && l.expires.After(time.Now())
+}
+
+// fence returns the error a write to key with token is rejected with, see
+// controller.Store.PushGameFrame.
+func (fs *fileStore) fence(key, token string) error {
+	if token != "" {
+		if !fs.holdsLock(key, token) {
+			return controller.ErrLockExpired
+		}
+		return nil
+	}
+	if fs.isLocked(key) {
+		return controller.ErrIsLocked
+	}
+	return nil
+}
+
 func (fs *fileStore) Unlock(ctx context.Context, key, token string) error {
 	fs.lock.Lock()
 	defer fs.lock.Unlock()
@@ -131,8 +151,36 @@ func (fs *fileStore) Unlock(ctx context.Context, key, token string) error {
 	return controller.ErrIsLocked
 }
 
+// ForceUnlock removes the lock on a game regardless of its token.
+func (fs *fileStore) ForceUnlock(ctx context.Context, key string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	l, ok := fs.locks[key]
+	if !ok {
+		return controller.ErrNotFound
+	}
+	delete(fs.locks, key)
+	// An expired lock no longer holds the game.
+	if l.expires.Before(time.Now()) {
+		return controller.ErrNotFound
+	}
+	return nil
+}
+
 // PopGameID gives the next running game. Since running games should always be
 // cached in memory it is not necessary to scan file system.
+func (fs *fileStore) LockInfo(ctx context.Context, key string) (*controller.LockInfo, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	if !fs.isLocked(key) {
+		return nil, controller.ErrNotFound
+	}
+	l := fs.locks[key]
+	return controller.NewLockInfo(l.token, l.expires), nil
+}
+
 func (fs *fileStore) PopGameID(ctx context.Context) (string, error) {
 	fs.lock.Lock()
 	defer fs.lock.Unlock()
@@ -147,6 +195,22 @@ func (fs *fileStore) PopGameID(ctx context.Context) (string, error) {
 	return "", controller.ErrNotFound
 }
 
+func (fs *fileStore) PeekRunningGames(ctx context.Context, count int) ([]string, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	ids := []string{}
+	for id, g := range fs.games {
+		if len(ids) >= count {
+			break
+		}
+		if !fs.isLocked(id) && g.Status == string(rules.GameStatusRunning) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
 func (fs *fileStore) CreateGame(ctx context.Context, g *pb.Game, frames []*pb.GameFrame) error {
 	fs.lock.Lock()
 	defer fs.lock.Unlock()
@@ -168,21 +232,126 @@ func (fs *fileStore) SetGameStatus(ctx context.Context, id string, status rules.
 		return err
 	}
 
+	if !rules.CanTransition(rules.GameStatus(game.Status), status) {
+		return controller.ErrInvalidTransition
+	}
+
 	game.Status = string(status)
-	if status != rules.GameStatusRunning {
+	// Paused games stay cached so they can be resumed.
+	if status != rules.GameStatusRunning && status != rules.GameStatusPaused {
 		fs.closeGame(id)
 	}
 	return nil
 }
 
-func (fs *fileStore) PushGameFrame(ctx context.Context, id string, g *pb.GameFrame) error {
+func (fs *fileStore) PushGameFrame(ctx context.Context, id, token string, g *pb.GameFrame) error {
 	fs.lock.Lock()
 	defer fs.lock.Unlock()
 
+	if err := fs.fence(id, token); err != nil {
+		return err
+	}
+
+	if frames := fs.frames[id]; len(frames) > 0 {
+		if dup, err := controller.DuplicateFrame(frames[len(frames)-1], g); dup || err != nil {
+			return err
+		}
+	}
 	return fs.appendFrame(id, g)
 }
 
+// PushGameFrames checks the whole batch before the first frame is appended to
+// the game file.
+func (fs *fileStore) PushGameFrames(ctx context.Context, id, token string, frames []*pb.GameFrame) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	if err := fs.fence(id, token); err != nil {
+		return err
+	}
+	if _, err := fs.requireGame(id); err != nil {
+		return err
+	}
+	next := int32(0)
+	if existing := fs.frames[id]; len(existing) > 0 {
+		next = existing[len(existing)-1].Turn + 1
+	}
+	if err := controller.CheckFrameSequence(next, frames); err != nil {
+		return err
+	}
+	return fs.appendFrames(id, frames)
+}
+
+// WithTx checks every write of fn before any of them is made. The frames are
+// appended to the game files first and the statuses are set once they are all
+// written, as a status change can close a game.
+func (fs *fileStore) WithTx(ctx context.Context, fn func(tx *controller.StoreTx) error) error {
+	ops, err := controller.CollectTx(fn)
+	if err != nil {
+		return err
+	}
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	last := map[string]*pb.GameFrame{}
+	games := map[string]*pb.Game{}
+	statuses := map[string]rules.GameStatus{}
+	var pushes []controller.TxOp
+	for _, op := range ops {
+		if op.Frame != nil {
+			if err := fs.fence(op.GameID, op.Token); err != nil {
+				return err
+			}
+			prev, ok := last[op.GameID]
+			if frames := fs.frames[op.GameID]; !ok && len(frames) > 0 {
+				prev = frames[len(frames)-1]
+			}
+			if prev != nil {
+				dup, err := controller.DuplicateFrame(prev, op.Frame)
+				if err != nil {
+					return err
+				}
+				if dup {
+					continue
+				}
+			}
+			last[op.GameID] = op.Frame
+			pushes = append(pushes, op)
+			continue
+		}
+		current, ok := statuses[op.GameID]
+		if !ok {
+			game, err := fs.requireGame(op.GameID)
+			if err != nil {
+				return err
+			}
+			games[op.GameID] = game
+			current = rules.GameStatus(game.Status)
+		}
+		if !rules.CanTransition(current, op.Status) {
+			return controller.ErrInvalidTransition
+		}
+		statuses[op.GameID] = op.Status
+	}
+
+	for _, op := range pushes {
+		if err := fs.appendFrame(op.GameID, op.Frame); err != nil {
+			return err
+		}
+	}
+	for id, status := range statuses {
+		games[id].Status = string(status)
+		if status != rules.GameStatusRunning && status != rules.GameStatusPaused {
+			fs.closeGame(id)
+		}
+	}
+	return nil
+}
+
 func (fs *fileStore) ListGameFrames(ctx context.Context, id string, limit, offset int) ([]*pb.GameFrame, error) {
+	if err := controller.ValidateLimit(limit); err != nil {
+		return nil, err
+	}
 	fs.lock.Lock()
 	defer fs.lock.Unlock()
 
@@ -207,6 +376,143 @@ func (fs *fileStore) ListGameFrames(ctx context.Context, id string, limit, offse
 	return frames[offset : offset+limit], nil
 }
 
+func (fs *fileStore) ListGameFramesReverse(ctx context.Context, id string, limit, offset int) ([]*pb.GameFrame, error) {
+	if err := controller.ValidateLimit(limit); err != nil {
+		return nil, err
+	}
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	if _, err := fs.requireGame(id); err != nil {
+		return nil, err
+	}
+	frames, err := fs.requireFrames(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// end is the index after the newest frame to return.
+	end := len(frames) - offset
+	if offset < 0 {
+		end = -offset
+	}
+	start := end - limit
+	if start < 0 {
+		start = 0
+	}
+	if end > len(frames) {
+		end = len(frames)
+	}
+	if end <= start {
+		return nil, nil
+	}
+	reversed := make([]*pb.GameFrame, 0, end-start)
+	for i := end - 1; i >= start; i-- {
+		reversed = append(reversed, frames[i])
+	}
+	return reversed, nil
+}
+
+func (fs *fileStore) ListGameFramesSince(ctx context.Context, id string, afterTurn int) ([]*pb.GameFrame, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	if _, err := fs.requireGame(id); err != nil {
+		return nil, err
+	}
+	frames, err := fs.requireFrames(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, f := range frames {
+		if int(f.Turn) > afterTurn {
+			return frames[i:], nil
+		}
+	}
+	return nil, nil
+}
+
+// RewindGame trims the frames back to toTurn. Archives are append only, so
+// the archive is rewritten with the remaining frames.
+func (fs *fileStore) RewindGame(ctx context.Context, id string, toTurn int) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	game, err := fs.requireGame(id)
+	if err != nil {
+		return err
+	}
+	frames, err := fs.requireFrames(id)
+	if err != nil {
+		return err
+	}
+	last := -1
+	for i, f := range frames {
+		if int(f.Turn) == toTurn {
+			last = i
+			break
+		}
+	}
+	if last < 0 {
+		return controller.ErrInvalidTurn
+	}
+
+	if game.Status == string(rules.GameStatusComplete) {
+		game.Status = string(rules.GameStatusRunning)
+	}
+	return fs.rewriteArchive(game, frames[:last+1])
+}
+
+// CompactGame rewrites the archive of a game when any of its lines was not
+// written in the current format. The store lock is held throughout, so reads
+// through the store never see a partly rewritten archive.
+func (fs *fileStore) CompactGame(ctx context.Context, id string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	game, err := fs.requireGame(id)
+	if err != nil {
+		return err
+	}
+	frames, err := fs.requireFrames(id)
+	if err != nil {
+		return err
+	}
+	current, err := archiveIsCurrent(fs.directory, id)
+	if err != nil || current {
+		return err
+	}
+	return fs.rewriteArchive(game, frames)
+}
+
+// UpdateGame replaces the cached game. When the initial frame was written
+// already, the archive is rewritten so its header holds the updated game.
+func (fs *fileStore) UpdateGame(ctx context.Context, game *pb.Game) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	g, err := fs.requireGame(game.ID)
+	if err != nil {
+		return err
+	}
+	frames, err := fs.requireFrames(game.ID)
+	if err != nil {
+		return err
+	}
+	if len(frames) > 1 {
+		return controller.ErrInProgress
+	}
+
+	updated := proto.Clone(game).(*pb.Game)
+	updated.Status = g.Status
+	if len(frames) == 0 {
+		fs.games[game.ID] = updated
+		return nil
+	}
+	return fs.rewriteArchive(updated, frames)
+}
+
 func (fs *fileStore) GetGame(ctx context.Context, id string) (*pb.Game, error) {
 	fs.lock.Lock()
 	defer fs.lock.Unlock()
@@ -222,6 +528,37 @@ func (fs *fileStore) GetGame(ctx context.Context, id string) (*pb.Game, error) {
 	return clone, nil
 }
 
+func (fs *fileStore) GetGameStatus(ctx context.Context, id string) (string, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	g, err := fs.requireGame(id)
+	if err != nil {
+		return "", err
+	}
+	return g.Status, nil
+}
+
+func (fs *fileStore) GetGameAndLastFrame(ctx context.Context, id string) (*pb.Game, *pb.GameFrame, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	g, err := fs.requireGame(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	frames, err := fs.requireFrames(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var lastFrame *pb.GameFrame
+	if len(frames) > 0 {
+		lastFrame = frames[len(frames)-1]
+	}
+	return proto.Clone(g).(*pb.Game), lastFrame, nil
+}
+
 func (fs *fileStore) requireHandle(id string, mustBeNew bool) (writer, error) {
 	if w, ok := fs.writers[id]; ok {
 		return w, nil
@@ -296,6 +633,36 @@ func (fs *fileStore) appendFrame(id string, f *pb.GameFrame) error {
 	return writeFrame(handle, f)
 }
 
+func (fs *fileStore) rewriteArchive(game *pb.Game, frames []*pb.GameFrame) error {
+	if w, ok := fs.writers[game.ID]; ok {
+		if err := w.Close(); err != nil {
+			log.WithError(err).Error("Error while closing file writer")
+		}
+		delete(fs.writers, game.ID)
+	}
+
+	handle, err := openFileRewriter(fs.directory, game.ID)
+	if err != nil {
+		return err
+	}
+	fs.writers[game.ID] = handle
+	fs.games[game.ID] = game
+	fs.frames[game.ID] = frames
+
+	if len(frames) == 0 {
+		return nil
+	}
+	if err := writeGameInfo(handle, game, frames[0].Snakes); err != nil {
+		return err
+	}
+	for _, f := range frames {
+		if err := writeFrame(handle, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (fs *fileStore) appendFrames(gameID string, frames []*pb.GameFrame) error {
 	for _, f := range frames {
 		if err := fs.appendFrame(gameID, f); err != nil {
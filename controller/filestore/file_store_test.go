@@ -3,6 +3,7 @@ package filestore
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,7 +23,7 @@ func TestFileStore(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, basicGame(), game)
 
-	err = fs.PushGameFrame(context.Background(), "myid", basicFrames()[1])
+	err = fs.PushGameFrame(context.Background(), "myid", "", basicFrames()[1])
 	require.NoError(t, err)
 
 	newFrames, err := fs.ListGameFrames(context.Background(), "myid", 5, 0)
@@ -35,6 +36,22 @@ func TestFileStore(t *testing.T) {
 	require.True(t, w.closed)
 }
 
+func TestFileStoreRulesetRoundTrip(t *testing.T) {
+	fs, w := testFileStore()
+	game := basicGame()
+	game.Ruleset = &pb.Ruleset{Name: "custom", MaxHealth: 50}
+	err := fs.CreateGame(context.Background(), game, basicFrames())
+	require.NoError(t, err)
+
+	// Read the game back from what was written to the archive.
+	openFileReader = func(dir string, id string) (reader, error) {
+		return newMockReader(w.text), nil
+	}
+	imported, err := NewFileStore("").GetGame(context.Background(), "myid")
+	require.NoError(t, err)
+	require.Equal(t, game.Ruleset, imported.Ruleset)
+}
+
 func TestCreateGameHandlesWriteError(t *testing.T) {
 	fs, w := testFileStore()
 	w.err = errors.New("fail")
@@ -66,10 +83,87 @@ func TestCreateGetGameFound(t *testing.T) {
 func TestPushGameFrameInvalidGame(t *testing.T) {
 	fs, _ := testFileStore()
 
-	err := fs.PushGameFrame(context.Background(), "notfound", basicFrames()[1])
+	err := fs.PushGameFrame(context.Background(), "notfound", "", basicFrames()[1])
 	require.NotNil(t, err)
 }
 
+func TestPushGameFrameStaleWriter(t *testing.T) {
+	fs, _ := testFileStore()
+	err := fs.CreateGame(context.Background(), basicGame(), basicFrames()[:1])
+	require.NoError(t, err)
+
+	stale, err := fs.Lock(context.Background(), "myid", "")
+	require.NoError(t, err)
+	require.NoError(t, fs.ForceUnlock(context.Background(), "myid"))
+	current, err := fs.Lock(context.Background(), "myid", "")
+	require.NoError(t, err)
+
+	err = fs.PushGameFrame(context.Background(), "myid", stale, basicFrames()[1])
+	require.Equal(t, controller.ErrLockExpired, err)
+	err = fs.PushGameFrame(context.Background(), "myid", "", basicFrames()[1])
+	require.Equal(t, controller.ErrIsLocked, err)
+	err = fs.PushGameFrame(context.Background(), "myid", current, basicFrames()[1])
+	require.NoError(t, err)
+}
+
+func TestWithTx(t *testing.T) {
+	fs, w := testFileStore()
+	err := fs.CreateGame(context.Background(), basicGame(), basicFrames()[:1])
+	require.NoError(t, err)
+	token, err := fs.Lock(context.Background(), "myid", "")
+	require.NoError(t, err)
+	written := w.text
+
+	failure := errors.New("failed")
+	err = fs.WithTx(context.Background(), func(tx *controller.StoreTx) error {
+		tx.PushGameFrame("myid", token, basicFrames()[1])
+		tx.SetGameStatus("myid", rules.GameStatusComplete)
+		return failure
+	})
+	require.Equal(t, failure, err)
+	require.Equal(t, written, w.text)
+
+	err = fs.WithTx(context.Background(), func(tx *controller.StoreTx) error {
+		tx.PushGameFrame("myid", token, basicFrames()[1])
+		tx.SetGameStatus("myid", rules.GameStatusComplete)
+		tx.SetGameStatus("myid", rules.GameStatusRunning)
+		return nil
+	})
+	require.Equal(t, controller.ErrInvalidTransition, err)
+	require.Equal(t, written, w.text)
+	require.False(t, w.closed)
+
+	err = fs.WithTx(context.Background(), func(tx *controller.StoreTx) error {
+		tx.PushGameFrame("myid", token, basicFrames()[1])
+		tx.SetGameStatus("myid", rules.GameStatusComplete)
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, written, w.text)
+	require.True(t, w.closed)
+}
+
+func TestPushGameFrameDuplicate(t *testing.T) {
+	fs, w := testFileStore()
+	err := fs.CreateGame(context.Background(), basicGame(), basicFrames())
+	require.NoError(t, err)
+	written := w.text
+
+	err = fs.PushGameFrame(context.Background(), "myid", "", basicFrames()[1])
+	require.NoError(t, err)
+	require.Equal(t, written, w.text)
+
+	conflict := basicFrames()[1]
+	conflict.Food = append(conflict.Food, &pb.Point{X: 9, Y: 9})
+	err = fs.PushGameFrame(context.Background(), "myid", "", conflict)
+	require.Equal(t, controller.ErrFrameConflict, err)
+	require.Equal(t, written, w.text)
+
+	err = fs.PushGameFrame(context.Background(), "myid", "", basicFrames()[0])
+	require.Equal(t, controller.ErrInvalidSequence, err)
+	require.Equal(t, written, w.text)
+}
+
 func TestListGameFramesInvalidGame(t *testing.T) {
 	fs, _ := testFileStore()
 
@@ -111,6 +205,270 @@ func TestListGameFramesEmpty(t *testing.T) {
 	require.Nil(t, newFrames)
 }
 
+func TestUpdateGame(t *testing.T) {
+	fs, w := testFileStore()
+	err := fs.CreateGame(context.Background(), basicGame(), basicFrames()[:1])
+	require.NoError(t, err)
+
+	updated := basicGame()
+	updated.Width = 7
+	err = fs.UpdateGame(context.Background(), updated)
+	require.NoError(t, err)
+	g, err := fs.GetGame(context.Background(), "myid")
+	require.NoError(t, err)
+	require.Equal(t, updated, g)
+
+	// The archive header holds the updated game.
+	lines := strings.Split(strings.TrimSpace(w.text), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], `"Width":7`)
+
+	err = fs.PushGameFrame(context.Background(), "myid", "", basicFrames()[1])
+	require.NoError(t, err)
+	err = fs.UpdateGame(context.Background(), basicGame())
+	require.Equal(t, controller.ErrInProgress, err)
+}
+
+func TestListGameFramesReverse(t *testing.T) {
+	fs, _ := testFileStore()
+	err := fs.CreateGame(context.Background(), basicGame(), basicFrames())
+	require.NoError(t, err)
+
+	frames, err := fs.ListGameFramesReverse(context.Background(), "myid", 5, 0)
+	require.NoError(t, err)
+	require.Equal(t, []*pb.GameFrame{basicFrames()[1], basicFrames()[0]}, frames)
+
+	frames, err = fs.ListGameFramesReverse(context.Background(), "myid", 5, 1)
+	require.NoError(t, err)
+	require.Equal(t, []*pb.GameFrame{basicFrames()[0]}, frames)
+
+	frames, err = fs.ListGameFramesReverse(context.Background(), "myid", 5, 2)
+	require.NoError(t, err)
+	require.Empty(t, frames)
+}
+
+func TestListGameFramesSince(t *testing.T) {
+	fs, _ := testFileStore()
+	frames := []*pb.GameFrame{basicFrames()[0], basicFrames()[1]}
+	err := fs.CreateGame(context.Background(), basicGame(), frames)
+	require.NoError(t, err)
+
+	newFrames, err := fs.ListGameFramesSince(context.Background(), "myid", 1)
+	require.NoError(t, err)
+	require.Len(t, newFrames, 1)
+	require.Equal(t, frames[1], newFrames[0])
+
+	newFrames, err = fs.ListGameFramesSince(context.Background(), "myid", 2)
+	require.NoError(t, err)
+	require.Nil(t, newFrames)
+}
+
+func TestListGameFramesSinceInvalidGame(t *testing.T) {
+	fs, _ := testFileStore()
+
+	_, err := fs.ListGameFramesSince(context.Background(), "notfound", 0)
+	require.NotNil(t, err)
+}
+
+func TestRewindGame(t *testing.T) {
+	fs, w := testFileStore()
+	game := basicGame()
+	game.Status = string(rules.GameStatusComplete)
+	err := fs.CreateGame(context.Background(), game, basicFrames())
+	require.NoError(t, err)
+
+	err = fs.RewindGame(context.Background(), "myid", 5)
+	require.Equal(t, controller.ErrInvalidTurn, err)
+
+	err = fs.RewindGame(context.Background(), "myid", 1)
+	require.NoError(t, err)
+	frames, err := fs.ListGameFrames(context.Background(), "myid", 5, 0)
+	require.NoError(t, err)
+	require.Equal(t, basicFrames()[:1], frames)
+
+	// The archive only holds the header and the remaining frame.
+	lines := strings.Split(strings.TrimSpace(w.text), "\n")
+	require.Len(t, lines, 2)
+	checkBasicFrameJSON(t, lines[1], 1)
+
+	g, err := fs.GetGame(context.Background(), "myid")
+	require.NoError(t, err)
+	require.Equal(t, string(rules.GameStatusRunning), g.Status)
+}
+
+func TestRewindGameInvalidGame(t *testing.T) {
+	fs, _ := testFileStore()
+	openFileReader = func(dir string, id string) (reader, error) {
+		return nil, errors.New("fail")
+	}
+	err := fs.RewindGame(context.Background(), "fakeid", 0)
+	require.NotNil(t, err)
+}
+
+func TestCompactGame(t *testing.T) {
+	fs, w := testFileStore()
+	err := fs.CreateGame(context.Background(), basicGame(), basicFrames())
+	require.NoError(t, err)
+
+	rewritten := false
+	openFileRewriter = func(dir string, id string) (writer, error) {
+		rewritten = true
+		w.text = ""
+		return w, nil
+	}
+
+	// The archive was just written, so it is already current.
+	err = fs.CompactGame(context.Background(), "myid")
+	require.NoError(t, err)
+	require.False(t, rewritten)
+
+	// An archive written with a different layout is rewritten.
+	current := w.text
+	w.text = strings.Replace(current, ",", ", ", -1)
+	err = fs.CompactGame(context.Background(), "myid")
+	require.NoError(t, err)
+	require.True(t, rewritten)
+	require.Equal(t, current, w.text)
+
+	frames, err := fs.ListGameFrames(context.Background(), "myid", 5, 0)
+	require.NoError(t, err)
+	require.Equal(t, basicFrames(), frames)
+}
+
+func TestCompactGameInvalidGame(t *testing.T) {
+	fs, _ := testFileStore()
+	openFileReader = func(dir string, id string) (reader, error) {
+		return nil, errors.New("fail")
+	}
+	err := fs.CompactGame(context.Background(), "fakeid")
+	require.NotNil(t, err)
+}
+
+func TestPauseResumeGame(t *testing.T) {
+	fs, w := testFileStore()
+	game := basicGame()
+	game.Status = string(rules.GameStatusRunning)
+	err := fs.CreateGame(context.Background(), game, basicFrames())
+	require.NoError(t, err)
+
+	err = fs.SetGameStatus(context.Background(), "myid", rules.GameStatusPaused)
+	require.NoError(t, err)
+	require.False(t, w.closed)
+	_, err = fs.PopGameID(context.Background())
+	require.Equal(t, controller.ErrNotFound, err)
+
+	err = fs.SetGameStatus(context.Background(), "myid", rules.GameStatusRunning)
+	require.NoError(t, err)
+	id, err := fs.PopGameID(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "myid", id)
+}
+
+func TestPushGameFrames(t *testing.T) {
+	fs, _ := testFileStore()
+	ctx := context.Background()
+	err := fs.CreateGame(ctx, basicGame(), basicFrames())
+	require.NoError(t, err)
+	count := len(basicFrames())
+	// The basic frames end on turn 2.
+	next := func(turns ...int32) []*pb.GameFrame {
+		frames := []*pb.GameFrame{}
+		for _, t := range turns {
+			frames = append(frames, &pb.GameFrame{Turn: 3 + t})
+		}
+		return frames
+	}
+
+	// A batch with a gap is rejected entirely.
+	err = fs.PushGameFrames(ctx, "myid", "", next(0, 2))
+	require.Equal(t, controller.ErrInvalidSequence, err)
+	frames, err := fs.ListGameFrames(ctx, "myid", 100, 0)
+	require.NoError(t, err)
+	require.Len(t, frames, count)
+
+	err = fs.PushGameFrames(ctx, "myid", "", next(0, 1, 2))
+	require.NoError(t, err)
+	frames, err = fs.ListGameFrames(ctx, "myid", 100, 0)
+	require.NoError(t, err)
+	require.Len(t, frames, count+3)
+	require.Equal(t, int32(5), frames[count+2].Turn)
+}
+
+func TestListGameFramesLimit(t *testing.T) {
+	fs, _ := testFileStore()
+	err := fs.CreateGame(context.Background(), basicGame(), basicFrames())
+	require.NoError(t, err)
+
+	_, err = fs.ListGameFrames(context.Background(), "myid", 0, 0)
+	require.Equal(t, controller.ErrZeroLimit, err)
+	_, err = fs.ListGameFrames(context.Background(), "myid", -5, 0)
+	require.Equal(t, controller.ErrNegativeLimit, err)
+	_, err = fs.ListGameFramesReverse(context.Background(), "myid", 0, 0)
+	require.Equal(t, controller.ErrZeroLimit, err)
+	_, err = fs.ListGameFramesReverse(context.Background(), "myid", -5, 0)
+	require.Equal(t, controller.ErrNegativeLimit, err)
+}
+
+func TestGetGameStatus(t *testing.T) {
+	fs, _ := testFileStore()
+	err := fs.CreateGame(context.Background(), basicGame(), basicFrames())
+	require.NoError(t, err)
+
+	err = fs.SetGameStatus(context.Background(), "myid", rules.GameStatusPaused)
+	require.NoError(t, err)
+	status, err := fs.GetGameStatus(context.Background(), "myid")
+	require.NoError(t, err)
+	require.Equal(t, string(rules.GameStatusPaused), status)
+
+	err = fs.SetGameStatus(context.Background(), "myid", rules.GameStatusRunning)
+	require.NoError(t, err)
+	status, err = fs.GetGameStatus(context.Background(), "myid")
+	require.NoError(t, err)
+	require.Equal(t, string(rules.GameStatusRunning), status)
+}
+
+func TestSetGameStatusInvalidTransition(t *testing.T) {
+	fs, _ := testFileStore()
+	game := basicGame()
+	game.Status = string(rules.GameStatusComplete)
+	err := fs.CreateGame(context.Background(), game, basicFrames())
+	require.NoError(t, err)
+
+	err = fs.SetGameStatus(context.Background(), "myid", rules.GameStatusRunning)
+	require.Equal(t, controller.ErrInvalidTransition, err)
+}
+
+func TestGetGameAndLastFrame(t *testing.T) {
+	fs, _ := testFileStore()
+	err := fs.CreateGame(context.Background(), basicGame(), basicFrames())
+	require.NoError(t, err)
+
+	g, f, err := fs.GetGameAndLastFrame(context.Background(), "myid")
+	require.NoError(t, err)
+	require.Equal(t, basicGame(), g)
+	require.Equal(t, basicFrames()[1], f)
+}
+
+func TestGetGameAndLastFrameNoFrames(t *testing.T) {
+	fs, _ := testFileStore()
+	err := fs.CreateGame(context.Background(), basicGame(), nil)
+	require.NoError(t, err)
+
+	g, f, err := fs.GetGameAndLastFrame(context.Background(), "myid")
+	require.NoError(t, err)
+	require.Equal(t, basicGame(), g)
+	require.Nil(t, f)
+}
+
+func TestGetGameAndLastFrameInvalidGame(t *testing.T) {
+	fs, _ := testFileStore()
+	openFileReader = func(dir string, id string) (reader, error) {
+		return nil, errors.New("fail")
+	}
+	_, _, err := fs.GetGameAndLastFrame(context.Background(), "fakeid")
+	require.NotNil(t, err)
+}
+
 func TestSetGameStatusInvalidGame(t *testing.T) {
 	fs, _ := testFileStore()
 
@@ -128,6 +486,19 @@ func TestLockUnlock(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLockInfo(t *testing.T) {
+	fs, _ := testFileStore()
+	_, err := fs.LockInfo(context.Background(), "asdf")
+	require.Equal(t, controller.ErrNotFound, err)
+
+	token, err := fs.Lock(context.Background(), "asdf", controller.NewLockToken("host-1"))
+	require.NoError(t, err)
+	info, err := fs.LockInfo(context.Background(), "asdf")
+	require.NoError(t, err)
+	require.Equal(t, token, info.Token)
+	require.Equal(t, "host-1", info.WorkerID)
+}
+
 func TestLockExpired(t *testing.T) {
 	fs, _ := testFileStore()
 	tempExpr := controller.LockExpiry
@@ -156,6 +527,19 @@ func TestUnlockNothing(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestForceUnlock(t *testing.T) {
+	fs, _ := testFileStore()
+	err := fs.ForceUnlock(context.Background(), "aaa")
+	require.Equal(t, controller.ErrNotFound, err)
+
+	_, err = fs.Lock(context.Background(), "aaa", "")
+	require.NoError(t, err)
+	err = fs.ForceUnlock(context.Background(), "aaa")
+	require.NoError(t, err)
+	_, err = fs.Lock(context.Background(), "aaa", "")
+	require.NoError(t, err)
+}
+
 func TestUnlockBadToken(t *testing.T) {
 	fs, _ := testFileStore()
 	_, err := fs.Lock(context.Background(), "aaa", "")
@@ -173,6 +557,23 @@ func TestPopGameID(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPeekRunningGames(t *testing.T) {
+	fs, _ := testFileStore()
+	ids, err := fs.PeekRunningGames(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{}, ids)
+
+	err = fs.CreateGame(context.Background(), basicGame(), basicFrames())
+	require.NoError(t, err)
+	ids, err = fs.PeekRunningGames(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"myid"}, ids)
+
+	id, err := fs.PopGameID(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "myid", id)
+}
+
 func TestPopGameIDNotFound(t *testing.T) {
 	fs, _ := testFileStore()
 	_, err := fs.PopGameID(context.Background())
@@ -189,5 +590,9 @@ func testFileStore() (controller.Store, *mockWriter) {
 	openFileReader = func(dir string, id string) (reader, error) {
 		return newMockReader(w.text), nil
 	}
+	openFileRewriter = func(dir string, id string) (writer, error) {
+		w.text = ""
+		return w, nil
+	}
 	return NewFileStore(""), w
 }
@@ -8,6 +8,7 @@ import (
 )
 
 var openFileWriter = appendOnlyFileWriter
+var openFileRewriter = truncatingFileWriter
 
 type writer interface {
 	WriteString(s string) (int, error)
@@ -19,14 +20,23 @@ func requireSaveDir(dir string) error {
 }
 
 func writeLine(w writer, data interface{}) error {
-	j, err := json.Marshal(data)
+	line, err := encodeLine(data)
 	if err != nil {
 		return err
 	}
-	_, err = w.WriteString(string(j) + "\n")
+	_, err = w.WriteString(line)
 	return err
 }
 
+// encodeLine encodes data the way a line of an archive is written.
+func encodeLine(data interface{}) (string, error) {
+	j, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(j) + "\n", nil
+}
+
 func writeFrame(w writer, f *pb.GameFrame) error {
 	return writeLine(w, f)
 }
@@ -47,3 +57,12 @@ func appendOnlyFileWriter(dir string, id string, mustCreate bool) (writer, error
 	}
 	return os.OpenFile(path, flags, 0600)
 }
+
+func truncatingFileWriter(dir string, id string) (writer, error) {
+	if err := requireSaveDir(dir); err != nil {
+		return nil, err
+	}
+
+	path := getFilePath(dir, id)
+	return os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+}
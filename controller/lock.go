@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// lockTokenSeparator separates the worker id from the nonce in a lock token.
+const lockTokenSeparator = ":"
+
+// LockInfo describes who holds the lock of a game.
+type LockInfo struct {
+	// Token is the token the lock is held with.
+	Token string
+	// WorkerID is the id of the worker holding the lock, taken from the
+	// token. It is empty when the lock was taken without one.
+	WorkerID string
+	// Expires is when the lock expires unless it is renewed.
+	Expires time.Time
+}
+
+// NewLockToken returns a new lock token that records workerID, so LockInfo
+// can report which worker holds a lock. Tokens are still compared as a whole,
+// the worker id does not make a token any less unique.
+func NewLockToken(workerID string) string {
+	nonce := uuid.NewV4().String()
+	if workerID == "" {
+		return nonce
+	}
+	return workerID + lockTokenSeparator + nonce
+}
+
+// LockWorkerID returns the worker id recorded in a token made by
+// NewLockToken, or an empty string for a token without one.
+func LockWorkerID(token string) string {
+	i := strings.LastIndex(token, lockTokenSeparator)
+	if i < 0 {
+		return ""
+	}
+	return token[:i]
+}
+
+// NewLockInfo returns the LockInfo of a lock held with token until expires.
+func NewLockInfo(token string, expires time.Time) *LockInfo {
+	return &LockInfo{Token: token, WorkerID: LockWorkerID(token), Expires: expires}
+}
@@ -0,0 +1,14 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockWorkerID(t *testing.T) {
+	require.Equal(t, "host-1", LockWorkerID(NewLockToken("host-1")))
+	require.Equal(t, "a:b", LockWorkerID(NewLockToken("a:b")))
+	require.Equal(t, "", LockWorkerID(NewLockToken("")))
+	require.NotEqual(t, NewLockToken("host-1"), NewLockToken("host-1"))
+}
@@ -26,11 +26,19 @@ It has these top-level messages:
 	ListGameFramesResponse
 	EndGameRequest
 	EndGameResponse
+	PauseGameRequest
+	PauseGameResponse
+	ResumeGameRequest
+	ResumeGameResponse
+	AbortGameRequest
+	AbortGameResponse
 	PingRequest
 	PingResponse
 	SnakeOptions
 	Game
+	Ruleset
 	GameFrame
+	Event
 	Point
 	Snake
 	Death
@@ -185,6 +193,7 @@ func (m *Score) GetChecksFailed() int32 {
 }
 
 type PopRequest struct {
+	WorkerID string `protobuf:"bytes,1,opt,name=WorkerID,proto3" json:"WorkerID,omitempty"`
 }
 
 func (m *PopRequest) Reset()                    { *m = PopRequest{} }
@@ -192,6 +201,13 @@ func (m *PopRequest) String() string            { return proto.CompactTextString
 func (*PopRequest) ProtoMessage()               {}
 func (*PopRequest) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{4} }
 
+func (m *PopRequest) GetWorkerID() string {
+	if m != nil {
+		return m.WorkerID
+	}
+	return ""
+}
+
 type PopResponse struct {
 	ID    string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
 	Token string `protobuf:"bytes,2,opt,name=Token,proto3" json:"Token,omitempty"`
@@ -281,10 +297,16 @@ func (*StartResponse) ProtoMessage()               {}
 func (*StartResponse) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{9} }
 
 type CreateRequest struct {
-	Width  int32           `protobuf:"varint,1,opt,name=Width,proto3" json:"Width,omitempty"`
-	Height int32           `protobuf:"varint,2,opt,name=Height,proto3" json:"Height,omitempty"`
-	Food   int32           `protobuf:"varint,3,opt,name=Food,proto3" json:"Food,omitempty"`
-	Snakes []*SnakeOptions `protobuf:"bytes,4,rep,name=Snakes" json:"Snakes,omitempty"`
+	Width        int32           `protobuf:"varint,1,opt,name=Width,proto3" json:"Width,omitempty"`
+	Height       int32           `protobuf:"varint,2,opt,name=Height,proto3" json:"Height,omitempty"`
+	Food         int32           `protobuf:"varint,3,opt,name=Food,proto3" json:"Food,omitempty"`
+	Snakes       []*SnakeOptions `protobuf:"bytes,4,rep,name=Snakes" json:"Snakes,omitempty"`
+	Ruleset      *Ruleset        `protobuf:"bytes,5,opt,name=Ruleset" json:"Ruleset,omitempty"`
+	Seed         int64           `protobuf:"varint,6,opt,name=Seed,proto3" json:"Seed,omitempty"`
+	Hazards      []*Point        `protobuf:"bytes,7,rep,name=Hazards" json:"Hazards,omitempty"`
+	SnakeTimeout int32           `protobuf:"varint,8,opt,name=SnakeTimeout,proto3" json:"SnakeTimeout,omitempty"`
+	Obstacles    []*Point        `protobuf:"bytes,9,rep,name=Obstacles" json:"Obstacles,omitempty"`
+	Name         string          `protobuf:"bytes,10,opt,name=Name,proto3" json:"Name,omitempty"`
 }
 
 func (m *CreateRequest) Reset()                    { *m = CreateRequest{} }
@@ -320,6 +342,48 @@ func (m *CreateRequest) GetSnakes() []*SnakeOptions {
 	return nil
 }
 
+func (m *CreateRequest) GetRuleset() *Ruleset {
+	if m != nil {
+		return m.Ruleset
+	}
+	return nil
+}
+
+func (m *CreateRequest) GetSeed() int64 {
+	if m != nil {
+		return m.Seed
+	}
+	return 0
+}
+
+func (m *CreateRequest) GetHazards() []*Point {
+	if m != nil {
+		return m.Hazards
+	}
+	return nil
+}
+
+func (m *CreateRequest) GetSnakeTimeout() int32 {
+	if m != nil {
+		return m.SnakeTimeout
+	}
+	return 0
+}
+
+func (m *CreateRequest) GetObstacles() []*Point {
+	if m != nil {
+		return m.Obstacles
+	}
+	return nil
+}
+
+func (m *CreateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
 type CreateResponse struct {
 	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
 }
@@ -377,9 +441,10 @@ func (m *AddGameFrameResponse) GetGame() *Game {
 }
 
 type ListGameFramesRequest struct {
-	ID     string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Limit  int32  `protobuf:"varint,2,opt,name=Limit,proto3" json:"Limit,omitempty"`
-	Offset int32  `protobuf:"varint,3,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	ID      string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Limit   int32  `protobuf:"varint,2,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	Offset  int32  `protobuf:"varint,3,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	Reverse bool   `protobuf:"varint,4,opt,name=Reverse,proto3" json:"Reverse,omitempty"`
 }
 
 func (m *ListGameFramesRequest) Reset()                    { *m = ListGameFramesRequest{} }
@@ -408,6 +473,13 @@ func (m *ListGameFramesRequest) GetOffset() int32 {
 	return 0
 }
 
+func (m *ListGameFramesRequest) GetReverse() bool {
+	if m != nil {
+		return m.Reverse
+	}
+	return false
+}
+
 type ListGameFramesResponse struct {
 	Frames []*GameFrame `protobuf:"bytes,1,rep,name=Frames" json:"Frames,omitempty"`
 	Count  int32        `protobuf:"varint,2,opt,name=Count,proto3" json:"Count,omitempty"`
@@ -458,13 +530,93 @@ func (m *EndGameResponse) String() string            { return proto.CompactTextS
 func (*EndGameResponse) ProtoMessage()               {}
 func (*EndGameResponse) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{17} }
 
+type PauseGameRequest struct {
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+}
+
+func (m *PauseGameRequest) Reset()                    { *m = PauseGameRequest{} }
+func (m *PauseGameRequest) String() string            { return proto.CompactTextString(m) }
+func (*PauseGameRequest) ProtoMessage()               {}
+func (*PauseGameRequest) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{18} }
+
+func (m *PauseGameRequest) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+type PauseGameResponse struct {
+}
+
+func (m *PauseGameResponse) Reset()                    { *m = PauseGameResponse{} }
+func (m *PauseGameResponse) String() string            { return proto.CompactTextString(m) }
+func (*PauseGameResponse) ProtoMessage()               {}
+func (*PauseGameResponse) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{19} }
+
+type ResumeGameRequest struct {
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+}
+
+func (m *ResumeGameRequest) Reset()                    { *m = ResumeGameRequest{} }
+func (m *ResumeGameRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResumeGameRequest) ProtoMessage()               {}
+func (*ResumeGameRequest) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{20} }
+
+func (m *ResumeGameRequest) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+type ResumeGameResponse struct {
+}
+
+func (m *ResumeGameResponse) Reset()                    { *m = ResumeGameResponse{} }
+func (m *ResumeGameResponse) String() string            { return proto.CompactTextString(m) }
+func (*ResumeGameResponse) ProtoMessage()               {}
+func (*ResumeGameResponse) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{21} }
+
+type AbortGameRequest struct {
+	ID     string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=Reason,proto3" json:"Reason,omitempty"`
+}
+
+func (m *AbortGameRequest) Reset()                    { *m = AbortGameRequest{} }
+func (m *AbortGameRequest) String() string            { return proto.CompactTextString(m) }
+func (*AbortGameRequest) ProtoMessage()               {}
+func (*AbortGameRequest) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{22} }
+
+func (m *AbortGameRequest) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *AbortGameRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type AbortGameResponse struct {
+}
+
+func (m *AbortGameResponse) Reset()                    { *m = AbortGameResponse{} }
+func (m *AbortGameResponse) String() string            { return proto.CompactTextString(m) }
+func (*AbortGameResponse) ProtoMessage()               {}
+func (*AbortGameResponse) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{23} }
+
 type PingRequest struct {
 }
 
 func (m *PingRequest) Reset()                    { *m = PingRequest{} }
 func (m *PingRequest) String() string            { return proto.CompactTextString(m) }
 func (*PingRequest) ProtoMessage()               {}
-func (*PingRequest) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{18} }
+func (*PingRequest) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{24} }
 
 type PingResponse struct {
 	Version string `protobuf:"bytes,1,opt,name=Version,proto3" json:"Version,omitempty"`
@@ -473,7 +625,7 @@ type PingResponse struct {
 func (m *PingResponse) Reset()                    { *m = PingResponse{} }
 func (m *PingResponse) String() string            { return proto.CompactTextString(m) }
 func (*PingResponse) ProtoMessage()               {}
-func (*PingResponse) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{19} }
+func (*PingResponse) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{25} }
 
 func (m *PingResponse) GetVersion() string {
 	if m != nil {
@@ -483,15 +635,16 @@ func (m *PingResponse) GetVersion() string {
 }
 
 type SnakeOptions struct {
-	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
-	URL  string `protobuf:"bytes,2,opt,name=URL,proto3" json:"URL,omitempty"`
-	ID   string `protobuf:"bytes,3,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name  string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	URL   string `protobuf:"bytes,2,opt,name=URL,proto3" json:"URL,omitempty"`
+	ID    string `protobuf:"bytes,3,opt,name=ID,proto3" json:"ID,omitempty"`
+	Squad string `protobuf:"bytes,4,opt,name=Squad,proto3" json:"Squad,omitempty"`
 }
 
 func (m *SnakeOptions) Reset()                    { *m = SnakeOptions{} }
 func (m *SnakeOptions) String() string            { return proto.CompactTextString(m) }
 func (*SnakeOptions) ProtoMessage()               {}
-func (*SnakeOptions) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{20} }
+func (*SnakeOptions) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{26} }
 
 func (m *SnakeOptions) GetName() string {
 	if m != nil {
@@ -514,20 +667,33 @@ func (m *SnakeOptions) GetID() string {
 	return ""
 }
 
+func (m *SnakeOptions) GetSquad() string {
+	if m != nil {
+		return m.Squad
+	}
+	return ""
+}
+
 type Game struct {
-	ID           string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Status       string `protobuf:"bytes,2,opt,name=Status,proto3" json:"Status,omitempty"`
-	Width        int32  `protobuf:"varint,3,opt,name=Width,proto3" json:"Width,omitempty"`
-	Height       int32  `protobuf:"varint,4,opt,name=Height,proto3" json:"Height,omitempty"`
-	SnakeTimeout int32  `protobuf:"varint,6,opt,name=SnakeTimeout,proto3" json:"SnakeTimeout,omitempty"`
-	TurnTimeout  int32  `protobuf:"varint,7,opt,name=TurnTimeout,proto3" json:"TurnTimeout,omitempty"`
-	Mode         string `protobuf:"bytes,8,opt,name=Mode,proto3" json:"Mode,omitempty"`
+	ID             string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Status         string   `protobuf:"bytes,2,opt,name=Status,proto3" json:"Status,omitempty"`
+	Width          int32    `protobuf:"varint,3,opt,name=Width,proto3" json:"Width,omitempty"`
+	Height         int32    `protobuf:"varint,4,opt,name=Height,proto3" json:"Height,omitempty"`
+	SnakeTimeout   int32    `protobuf:"varint,6,opt,name=SnakeTimeout,proto3" json:"SnakeTimeout,omitempty"`
+	TurnTimeout    int32    `protobuf:"varint,7,opt,name=TurnTimeout,proto3" json:"TurnTimeout,omitempty"`
+	Mode           string   `protobuf:"bytes,8,opt,name=Mode,proto3" json:"Mode,omitempty"`
+	Ruleset        *Ruleset `protobuf:"bytes,9,opt,name=Ruleset" json:"Ruleset,omitempty"`
+	RulesetVersion string   `protobuf:"bytes,10,opt,name=RulesetVersion,proto3" json:"RulesetVersion,omitempty"`
+	Seed           int64    `protobuf:"varint,11,opt,name=Seed,proto3" json:"Seed,omitempty"`
+	FoodTarget     int32    `protobuf:"varint,12,opt,name=FoodTarget,proto3" json:"FoodTarget,omitempty"`
+	Obstacles      []*Point `protobuf:"bytes,13,rep,name=Obstacles" json:"Obstacles,omitempty"`
+	Name           string   `protobuf:"bytes,14,opt,name=Name,proto3" json:"Name,omitempty"`
 }
 
 func (m *Game) Reset()                    { *m = Game{} }
 func (m *Game) String() string            { return proto.CompactTextString(m) }
 func (*Game) ProtoMessage()               {}
-func (*Game) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{21} }
+func (*Game) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{27} }
 
 func (m *Game) GetID() string {
 	if m != nil {
@@ -578,16 +744,326 @@ func (m *Game) GetMode() string {
 	return ""
 }
 
+func (m *Game) GetRuleset() *Ruleset {
+	if m != nil {
+		return m.Ruleset
+	}
+	return nil
+}
+
+func (m *Game) GetRulesetVersion() string {
+	if m != nil {
+		return m.RulesetVersion
+	}
+	return ""
+}
+
+func (m *Game) GetSeed() int64 {
+	if m != nil {
+		return m.Seed
+	}
+	return 0
+}
+
+func (m *Game) GetFoodTarget() int32 {
+	if m != nil {
+		return m.FoodTarget
+	}
+	return 0
+}
+
+func (m *Game) GetObstacles() []*Point {
+	if m != nil {
+		return m.Obstacles
+	}
+	return nil
+}
+
+func (m *Game) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// Ruleset describes the rules a game is played with. It is stored with the
+// game so any worker picking the game up runs it the same way.
+type Ruleset struct {
+	Name                   string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	MaxHealth              int32  `protobuf:"varint,2,opt,name=MaxHealth,proto3" json:"MaxHealth,omitempty"`
+	EliminateUnresponsive  bool   `protobuf:"varint,3,opt,name=EliminateUnresponsive,proto3" json:"EliminateUnresponsive,omitempty"`
+	MaxConsecutiveFailures int32  `protobuf:"varint,4,opt,name=MaxConsecutiveFailures,proto3" json:"MaxConsecutiveFailures,omitempty"`
+	EqualHeadToHead        string `protobuf:"bytes,5,opt,name=EqualHeadToHead,proto3" json:"EqualHeadToHead,omitempty"`
+	FoodSpawnStartTurn     int32  `protobuf:"varint,6,opt,name=FoodSpawnStartTurn,proto3" json:"FoodSpawnStartTurn,omitempty"`
+	SquadMode              bool   `protobuf:"varint,7,opt,name=SquadMode,proto3" json:"SquadMode,omitempty"`
+	AllowBodyCollisions    bool   `protobuf:"varint,8,opt,name=AllowBodyCollisions,proto3" json:"AllowBodyCollisions,omitempty"`
+	StarvationStartTurn    int32  `protobuf:"varint,9,opt,name=StarvationStartTurn,proto3" json:"StarvationStartTurn,omitempty"`
+	RespawnAfterTurns      int32  `protobuf:"varint,10,opt,name=RespawnAfterTurns,proto3" json:"RespawnAfterTurns,omitempty"`
+	UnresponsiveAfterMoves int32  `protobuf:"varint,11,opt,name=UnresponsiveAfterMoves,proto3" json:"UnresponsiveAfterMoves,omitempty"`
+	StartingHealth         int32  `protobuf:"varint,12,opt,name=StartingHealth,proto3" json:"StartingHealth,omitempty"`
+	WrapHorizontal         bool   `protobuf:"varint,13,opt,name=WrapHorizontal,proto3" json:"WrapHorizontal,omitempty"`
+	WrapVertical           bool   `protobuf:"varint,14,opt,name=WrapVertical,proto3" json:"WrapVertical,omitempty"`
+	ShortCircuitGameOver   bool   `protobuf:"varint,15,opt,name=ShortCircuitGameOver,proto3" json:"ShortCircuitGameOver,omitempty"`
+	HazardFoodPlacement    string `protobuf:"bytes,16,opt,name=HazardFoodPlacement,proto3" json:"HazardFoodPlacement,omitempty"`
+	MaxFoodSpawnPerTurn    int32  `protobuf:"varint,17,opt,name=MaxFoodSpawnPerTurn,proto3" json:"MaxFoodSpawnPerTurn,omitempty"`
+	SquadWipe              bool   `protobuf:"varint,18,opt,name=SquadWipe,proto3" json:"SquadWipe,omitempty"`
+	DisableFood            bool   `protobuf:"varint,19,opt,name=DisableFood,proto3" json:"DisableFood,omitempty"`
+	MaxConcurrentMoves     int32  `protobuf:"varint,20,opt,name=MaxConcurrentMoves,proto3" json:"MaxConcurrentMoves,omitempty"`
+	HazardBorder           int32  `protobuf:"varint,21,opt,name=HazardBorder,proto3" json:"HazardBorder,omitempty"`
+	HazardDamagePerTurn    int32  `protobuf:"varint,22,opt,name=HazardDamagePerTurn,proto3" json:"HazardDamagePerTurn,omitempty"`
+	DisableFoodReplacement bool   `protobuf:"varint,23,opt,name=DisableFoodReplacement,proto3" json:"DisableFoodReplacement,omitempty"`
+	MinimumFood            int32  `protobuf:"varint,24,opt,name=MinimumFood,proto3" json:"MinimumFood,omitempty"`
+	StartingDirection      string `protobuf:"bytes,25,opt,name=StartingDirection,proto3" json:"StartingDirection,omitempty"`
+	RespawnLength          int32  `protobuf:"varint,26,opt,name=RespawnLength,proto3" json:"RespawnLength,omitempty"`
+	HazardHealRate         int32  `protobuf:"varint,27,opt,name=HazardHealRate,proto3" json:"HazardHealRate,omitempty"`
+	DebugMoveRequests      bool   `protobuf:"varint,28,opt,name=DebugMoveRequests,proto3" json:"DebugMoveRequests,omitempty"`
+	MaxSnakeLength         int32  `protobuf:"varint,29,opt,name=MaxSnakeLength,proto3" json:"MaxSnakeLength,omitempty"`
+	Mode                   string `protobuf:"bytes,30,opt,name=Mode,proto3" json:"Mode,omitempty"`
+	SnakeTimeout           int32  `protobuf:"varint,31,opt,name=SnakeTimeout,proto3" json:"SnakeTimeout,omitempty"`
+	SnakeTimeoutGrace      int32  `protobuf:"varint,32,opt,name=SnakeTimeoutGrace,proto3" json:"SnakeTimeoutGrace,omitempty"`
+}
+
+func (m *Ruleset) Reset()                    { *m = Ruleset{} }
+func (m *Ruleset) String() string            { return proto.CompactTextString(m) }
+func (*Ruleset) ProtoMessage()               {}
+func (*Ruleset) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{28} }
+
+func (m *Ruleset) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Ruleset) GetMaxHealth() int32 {
+	if m != nil {
+		return m.MaxHealth
+	}
+	return 0
+}
+
+func (m *Ruleset) GetEliminateUnresponsive() bool {
+	if m != nil {
+		return m.EliminateUnresponsive
+	}
+	return false
+}
+
+func (m *Ruleset) GetMaxConsecutiveFailures() int32 {
+	if m != nil {
+		return m.MaxConsecutiveFailures
+	}
+	return 0
+}
+
+func (m *Ruleset) GetEqualHeadToHead() string {
+	if m != nil {
+		return m.EqualHeadToHead
+	}
+	return ""
+}
+
+func (m *Ruleset) GetFoodSpawnStartTurn() int32 {
+	if m != nil {
+		return m.FoodSpawnStartTurn
+	}
+	return 0
+}
+
+func (m *Ruleset) GetSquadMode() bool {
+	if m != nil {
+		return m.SquadMode
+	}
+	return false
+}
+
+func (m *Ruleset) GetAllowBodyCollisions() bool {
+	if m != nil {
+		return m.AllowBodyCollisions
+	}
+	return false
+}
+
+func (m *Ruleset) GetStarvationStartTurn() int32 {
+	if m != nil {
+		return m.StarvationStartTurn
+	}
+	return 0
+}
+
+func (m *Ruleset) GetRespawnAfterTurns() int32 {
+	if m != nil {
+		return m.RespawnAfterTurns
+	}
+	return 0
+}
+
+func (m *Ruleset) GetUnresponsiveAfterMoves() int32 {
+	if m != nil {
+		return m.UnresponsiveAfterMoves
+	}
+	return 0
+}
+
+func (m *Ruleset) GetStartingHealth() int32 {
+	if m != nil {
+		return m.StartingHealth
+	}
+	return 0
+}
+
+func (m *Ruleset) GetWrapHorizontal() bool {
+	if m != nil {
+		return m.WrapHorizontal
+	}
+	return false
+}
+
+func (m *Ruleset) GetWrapVertical() bool {
+	if m != nil {
+		return m.WrapVertical
+	}
+	return false
+}
+
+func (m *Ruleset) GetShortCircuitGameOver() bool {
+	if m != nil {
+		return m.ShortCircuitGameOver
+	}
+	return false
+}
+
+func (m *Ruleset) GetHazardFoodPlacement() string {
+	if m != nil {
+		return m.HazardFoodPlacement
+	}
+	return ""
+}
+
+func (m *Ruleset) GetMaxFoodSpawnPerTurn() int32 {
+	if m != nil {
+		return m.MaxFoodSpawnPerTurn
+	}
+	return 0
+}
+
+func (m *Ruleset) GetSquadWipe() bool {
+	if m != nil {
+		return m.SquadWipe
+	}
+	return false
+}
+
+func (m *Ruleset) GetDisableFood() bool {
+	if m != nil {
+		return m.DisableFood
+	}
+	return false
+}
+
+func (m *Ruleset) GetMaxConcurrentMoves() int32 {
+	if m != nil {
+		return m.MaxConcurrentMoves
+	}
+	return 0
+}
+
+func (m *Ruleset) GetHazardBorder() int32 {
+	if m != nil {
+		return m.HazardBorder
+	}
+	return 0
+}
+
+func (m *Ruleset) GetHazardDamagePerTurn() int32 {
+	if m != nil {
+		return m.HazardDamagePerTurn
+	}
+	return 0
+}
+
+func (m *Ruleset) GetDisableFoodReplacement() bool {
+	if m != nil {
+		return m.DisableFoodReplacement
+	}
+	return false
+}
+
+func (m *Ruleset) GetMinimumFood() int32 {
+	if m != nil {
+		return m.MinimumFood
+	}
+	return 0
+}
+
+func (m *Ruleset) GetStartingDirection() string {
+	if m != nil {
+		return m.StartingDirection
+	}
+	return ""
+}
+
+func (m *Ruleset) GetRespawnLength() int32 {
+	if m != nil {
+		return m.RespawnLength
+	}
+	return 0
+}
+
+func (m *Ruleset) GetHazardHealRate() int32 {
+	if m != nil {
+		return m.HazardHealRate
+	}
+	return 0
+}
+
+func (m *Ruleset) GetDebugMoveRequests() bool {
+	if m != nil {
+		return m.DebugMoveRequests
+	}
+	return false
+}
+
+func (m *Ruleset) GetMaxSnakeLength() int32 {
+	if m != nil {
+		return m.MaxSnakeLength
+	}
+	return 0
+}
+
+func (m *Ruleset) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *Ruleset) GetSnakeTimeout() int32 {
+	if m != nil {
+		return m.SnakeTimeout
+	}
+	return 0
+}
+
+func (m *Ruleset) GetSnakeTimeoutGrace() int32 {
+	if m != nil {
+		return m.SnakeTimeoutGrace
+	}
+	return 0
+}
+
 type GameFrame struct {
-	Turn   int32    `protobuf:"varint,1,opt,name=Turn,proto3" json:"Turn,omitempty"`
-	Food   []*Point `protobuf:"bytes,2,rep,name=Food" json:"Food,omitempty"`
-	Snakes []*Snake `protobuf:"bytes,3,rep,name=Snakes" json:"Snakes,omitempty"`
+	Turn    int32    `protobuf:"varint,1,opt,name=Turn,proto3" json:"Turn,omitempty"`
+	Food    []*Point `protobuf:"bytes,2,rep,name=Food" json:"Food,omitempty"`
+	Snakes  []*Snake `protobuf:"bytes,3,rep,name=Snakes" json:"Snakes,omitempty"`
+	Events  []*Event `protobuf:"bytes,4,rep,name=Events" json:"Events,omitempty"`
+	Hazards []*Point `protobuf:"bytes,5,rep,name=Hazards" json:"Hazards,omitempty"`
 }
 
 func (m *GameFrame) Reset()                    { *m = GameFrame{} }
 func (m *GameFrame) String() string            { return proto.CompactTextString(m) }
 func (*GameFrame) ProtoMessage()               {}
-func (*GameFrame) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{22} }
+func (*GameFrame) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{29} }
 
 func (m *GameFrame) GetTurn() int32 {
 	if m != nil {
@@ -610,6 +1086,61 @@ func (m *GameFrame) GetSnakes() []*Snake {
 	return nil
 }
 
+func (m *GameFrame) GetEvents() []*Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *GameFrame) GetHazards() []*Point {
+	if m != nil {
+		return m.Hazards
+	}
+	return nil
+}
+
+// Event is something that happened on a turn, such as a snake eating or dying.
+type Event struct {
+	Type    string `protobuf:"bytes,1,opt,name=Type,proto3" json:"Type,omitempty"`
+	SnakeID string `protobuf:"bytes,2,opt,name=SnakeID,proto3" json:"SnakeID,omitempty"`
+	Point   *Point `protobuf:"bytes,3,opt,name=Point" json:"Point,omitempty"`
+	Cause   string `protobuf:"bytes,4,opt,name=Cause,proto3" json:"Cause,omitempty"`
+}
+
+func (m *Event) Reset()                    { *m = Event{} }
+func (m *Event) String() string            { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()               {}
+func (*Event) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{30} }
+
+func (m *Event) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Event) GetSnakeID() string {
+	if m != nil {
+		return m.SnakeID
+	}
+	return ""
+}
+
+func (m *Event) GetPoint() *Point {
+	if m != nil {
+		return m.Point
+	}
+	return nil
+}
+
+func (m *Event) GetCause() string {
+	if m != nil {
+		return m.Cause
+	}
+	return ""
+}
+
 type Point struct {
 	X int32 `protobuf:"varint,1,opt,name=X,proto3" json:"X,omitempty"`
 	Y int32 `protobuf:"varint,2,opt,name=Y,proto3" json:"Y,omitempty"`
@@ -618,7 +1149,7 @@ type Point struct {
 func (m *Point) Reset()                    { *m = Point{} }
 func (m *Point) String() string            { return proto.CompactTextString(m) }
 func (*Point) ProtoMessage()               {}
-func (*Point) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{23} }
+func (*Point) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{31} }
 
 func (m *Point) GetX() int32 {
 	if m != nil {
@@ -635,19 +1166,24 @@ func (m *Point) GetY() int32 {
 }
 
 type Snake struct {
-	ID     string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Name   string   `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
-	URL    string   `protobuf:"bytes,3,opt,name=URL,proto3" json:"URL,omitempty"`
-	Body   []*Point `protobuf:"bytes,4,rep,name=Body" json:"Body,omitempty"`
-	Health int32    `protobuf:"varint,5,opt,name=Health,proto3" json:"Health,omitempty"`
-	Death  *Death   `protobuf:"bytes,6,opt,name=Death" json:"Death,omitempty"`
-	Color  string   `protobuf:"bytes,7,opt,name=Color,proto3" json:"Color,omitempty"`
+	ID                  string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name                string   `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	URL                 string   `protobuf:"bytes,3,opt,name=URL,proto3" json:"URL,omitempty"`
+	Body                []*Point `protobuf:"bytes,4,rep,name=Body" json:"Body,omitempty"`
+	Health              int32    `protobuf:"varint,5,opt,name=Health,proto3" json:"Health,omitempty"`
+	Death               *Death   `protobuf:"bytes,6,opt,name=Death" json:"Death,omitempty"`
+	Color               string   `protobuf:"bytes,7,opt,name=Color,proto3" json:"Color,omitempty"`
+	ConsecutiveFailures int32    `protobuf:"varint,8,opt,name=ConsecutiveFailures,proto3" json:"ConsecutiveFailures,omitempty"`
+	Squad               string   `protobuf:"bytes,9,opt,name=Squad,proto3" json:"Squad,omitempty"`
+	Unresponsive        bool     `protobuf:"varint,10,opt,name=Unresponsive,proto3" json:"Unresponsive,omitempty"`
+	MoveStatus          string   `protobuf:"bytes,11,opt,name=MoveStatus,proto3" json:"MoveStatus,omitempty"`
+	Facing              string   `protobuf:"bytes,12,opt,name=Facing,proto3" json:"Facing,omitempty"`
 }
 
 func (m *Snake) Reset()                    { *m = Snake{} }
 func (m *Snake) String() string            { return proto.CompactTextString(m) }
 func (*Snake) ProtoMessage()               {}
-func (*Snake) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{24} }
+func (*Snake) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{32} }
 
 func (m *Snake) GetID() string {
 	if m != nil {
@@ -688,12 +1224,47 @@ func (m *Snake) GetDeath() *Death {
 	if m != nil {
 		return m.Death
 	}
-	return nil
+	return nil
+}
+
+func (m *Snake) GetColor() string {
+	if m != nil {
+		return m.Color
+	}
+	return ""
+}
+
+func (m *Snake) GetConsecutiveFailures() int32 {
+	if m != nil {
+		return m.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (m *Snake) GetSquad() string {
+	if m != nil {
+		return m.Squad
+	}
+	return ""
+}
+
+func (m *Snake) GetUnresponsive() bool {
+	if m != nil {
+		return m.Unresponsive
+	}
+	return false
+}
+
+func (m *Snake) GetMoveStatus() string {
+	if m != nil {
+		return m.MoveStatus
+	}
+	return ""
 }
 
-func (m *Snake) GetColor() string {
+func (m *Snake) GetFacing() string {
 	if m != nil {
-		return m.Color
+		return m.Facing
 	}
 	return ""
 }
@@ -701,12 +1272,15 @@ func (m *Snake) GetColor() string {
 type Death struct {
 	Cause string `protobuf:"bytes,1,opt,name=Cause,proto3" json:"Cause,omitempty"`
 	Turn  int32  `protobuf:"varint,2,opt,name=Turn,proto3" json:"Turn,omitempty"`
+	// EliminatedBy is the ID of the snake that caused a snake or head to head
+	// collision.
+	EliminatedBy string `protobuf:"bytes,3,opt,name=EliminatedBy,proto3" json:"EliminatedBy,omitempty"`
 }
 
 func (m *Death) Reset()                    { *m = Death{} }
 func (m *Death) String() string            { return proto.CompactTextString(m) }
 func (*Death) ProtoMessage()               {}
-func (*Death) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{25} }
+func (*Death) Descriptor() ([]byte, []int) { return fileDescriptorController, []int{33} }
 
 func (m *Death) GetCause() string {
 	if m != nil {
@@ -722,6 +1296,13 @@ func (m *Death) GetTurn() int32 {
 	return 0
 }
 
+func (m *Death) GetEliminatedBy() string {
+	if m != nil {
+		return m.EliminatedBy
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ValidateSnakeRequest)(nil), "pb.ValidateSnakeRequest")
 	proto.RegisterType((*ValidateSnakeResponse)(nil), "pb.ValidateSnakeResponse")
@@ -741,11 +1322,19 @@ func init() {
 	proto.RegisterType((*ListGameFramesResponse)(nil), "pb.ListGameFramesResponse")
 	proto.RegisterType((*EndGameRequest)(nil), "pb.EndGameRequest")
 	proto.RegisterType((*EndGameResponse)(nil), "pb.EndGameResponse")
+	proto.RegisterType((*PauseGameRequest)(nil), "pb.PauseGameRequest")
+	proto.RegisterType((*PauseGameResponse)(nil), "pb.PauseGameResponse")
+	proto.RegisterType((*ResumeGameRequest)(nil), "pb.ResumeGameRequest")
+	proto.RegisterType((*ResumeGameResponse)(nil), "pb.ResumeGameResponse")
+	proto.RegisterType((*AbortGameRequest)(nil), "pb.AbortGameRequest")
+	proto.RegisterType((*AbortGameResponse)(nil), "pb.AbortGameResponse")
 	proto.RegisterType((*PingRequest)(nil), "pb.PingRequest")
 	proto.RegisterType((*PingResponse)(nil), "pb.PingResponse")
 	proto.RegisterType((*SnakeOptions)(nil), "pb.SnakeOptions")
 	proto.RegisterType((*Game)(nil), "pb.Game")
+	proto.RegisterType((*Ruleset)(nil), "pb.Ruleset")
 	proto.RegisterType((*GameFrame)(nil), "pb.GameFrame")
+	proto.RegisterType((*Event)(nil), "pb.Event")
 	proto.RegisterType((*Point)(nil), "pb.Point")
 	proto.RegisterType((*Snake)(nil), "pb.Snake")
 	proto.RegisterType((*Death)(nil), "pb.Death")
@@ -894,6 +1483,9 @@ func (this *PopRequest) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
+	if this.WorkerID != that1.WorkerID {
+		return false
+	}
 	return true
 }
 func (this *PopResponse) Equal(that interface{}) bool {
@@ -1055,6 +1647,34 @@ func (this *CreateRequest) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if !this.Ruleset.Equal(that1.Ruleset) {
+		return false
+	}
+	if this.Seed != that1.Seed {
+		return false
+	}
+	if len(this.Hazards) != len(that1.Hazards) {
+		return false
+	}
+	for i := range this.Hazards {
+		if !this.Hazards[i].Equal(that1.Hazards[i]) {
+			return false
+		}
+	}
+	if this.SnakeTimeout != that1.SnakeTimeout {
+		return false
+	}
+	if len(this.Obstacles) != len(that1.Obstacles) {
+		return false
+	}
+	for i := range this.Obstacles {
+		if !this.Obstacles[i].Equal(that1.Obstacles[i]) {
+			return false
+		}
+	}
+	if this.Name != that1.Name {
+		return false
+	}
 	return true
 }
 func (this *CreateResponse) Equal(that interface{}) bool {
@@ -1160,6 +1780,9 @@ func (this *ListGameFramesRequest) Equal(that interface{}) bool {
 	if this.Offset != that1.Offset {
 		return false
 	}
+	if this.Reverse != that1.Reverse {
+		return false
+	}
 	return true
 }
 func (this *ListGameFramesResponse) Equal(that interface{}) bool {
@@ -1239,6 +1862,144 @@ func (this *EndGameResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *PauseGameRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*PauseGameRequest)
+	if !ok {
+		that2, ok := that.(PauseGameRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.ID != that1.ID {
+		return false
+	}
+	return true
+}
+func (this *PauseGameResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*PauseGameResponse)
+	if !ok {
+		that2, ok := that.(PauseGameResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *ResumeGameRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ResumeGameRequest)
+	if !ok {
+		that2, ok := that.(ResumeGameRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.ID != that1.ID {
+		return false
+	}
+	return true
+}
+func (this *ResumeGameResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ResumeGameResponse)
+	if !ok {
+		that2, ok := that.(ResumeGameResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *AbortGameRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AbortGameRequest)
+	if !ok {
+		that2, ok := that.(AbortGameRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.ID != that1.ID {
+		return false
+	}
+	if this.Reason != that1.Reason {
+		return false
+	}
+	return true
+}
+func (this *AbortGameResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AbortGameResponse)
+	if !ok {
+		that2, ok := that.(AbortGameResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
 func (this *PingRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -1312,6 +2073,9 @@ func (this *SnakeOptions) Equal(that interface{}) bool {
 	if this.ID != that1.ID {
 		return false
 	}
+	if this.Squad != that1.Squad {
+		return false
+	}
 	return true
 }
 func (this *Game) Equal(that interface{}) bool {
@@ -1333,27 +2097,167 @@ func (this *Game) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.ID != that1.ID {
+	if this.ID != that1.ID {
+		return false
+	}
+	if this.Status != that1.Status {
+		return false
+	}
+	if this.Width != that1.Width {
+		return false
+	}
+	if this.Height != that1.Height {
+		return false
+	}
+	if this.SnakeTimeout != that1.SnakeTimeout {
+		return false
+	}
+	if this.TurnTimeout != that1.TurnTimeout {
+		return false
+	}
+	if this.Mode != that1.Mode {
+		return false
+	}
+	if !this.Ruleset.Equal(that1.Ruleset) {
+		return false
+	}
+	if this.RulesetVersion != that1.RulesetVersion {
+		return false
+	}
+	if this.Seed != that1.Seed {
+		return false
+	}
+	if this.FoodTarget != that1.FoodTarget {
+		return false
+	}
+	if len(this.Obstacles) != len(that1.Obstacles) {
+		return false
+	}
+	for i := range this.Obstacles {
+		if !this.Obstacles[i].Equal(that1.Obstacles[i]) {
+			return false
+		}
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	return true
+}
+func (this *Ruleset) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Ruleset)
+	if !ok {
+		that2, ok := that.(Ruleset)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.MaxHealth != that1.MaxHealth {
+		return false
+	}
+	if this.EliminateUnresponsive != that1.EliminateUnresponsive {
+		return false
+	}
+	if this.MaxConsecutiveFailures != that1.MaxConsecutiveFailures {
+		return false
+	}
+	if this.EqualHeadToHead != that1.EqualHeadToHead {
+		return false
+	}
+	if this.FoodSpawnStartTurn != that1.FoodSpawnStartTurn {
+		return false
+	}
+	if this.SquadMode != that1.SquadMode {
+		return false
+	}
+	if this.AllowBodyCollisions != that1.AllowBodyCollisions {
+		return false
+	}
+	if this.StarvationStartTurn != that1.StarvationStartTurn {
+		return false
+	}
+	if this.RespawnAfterTurns != that1.RespawnAfterTurns {
+		return false
+	}
+	if this.UnresponsiveAfterMoves != that1.UnresponsiveAfterMoves {
+		return false
+	}
+	if this.StartingHealth != that1.StartingHealth {
+		return false
+	}
+	if this.WrapHorizontal != that1.WrapHorizontal {
+		return false
+	}
+	if this.WrapVertical != that1.WrapVertical {
+		return false
+	}
+	if this.ShortCircuitGameOver != that1.ShortCircuitGameOver {
+		return false
+	}
+	if this.HazardFoodPlacement != that1.HazardFoodPlacement {
+		return false
+	}
+	if this.MaxFoodSpawnPerTurn != that1.MaxFoodSpawnPerTurn {
+		return false
+	}
+	if this.SquadWipe != that1.SquadWipe {
+		return false
+	}
+	if this.DisableFood != that1.DisableFood {
+		return false
+	}
+	if this.MaxConcurrentMoves != that1.MaxConcurrentMoves {
+		return false
+	}
+	if this.HazardBorder != that1.HazardBorder {
+		return false
+	}
+	if this.HazardDamagePerTurn != that1.HazardDamagePerTurn {
+		return false
+	}
+	if this.DisableFoodReplacement != that1.DisableFoodReplacement {
+		return false
+	}
+	if this.MinimumFood != that1.MinimumFood {
 		return false
 	}
-	if this.Status != that1.Status {
+	if this.StartingDirection != that1.StartingDirection {
 		return false
 	}
-	if this.Width != that1.Width {
+	if this.RespawnLength != that1.RespawnLength {
 		return false
 	}
-	if this.Height != that1.Height {
+	if this.HazardHealRate != that1.HazardHealRate {
 		return false
 	}
-	if this.SnakeTimeout != that1.SnakeTimeout {
+	if this.DebugMoveRequests != that1.DebugMoveRequests {
 		return false
 	}
-	if this.TurnTimeout != that1.TurnTimeout {
+	if this.MaxSnakeLength != that1.MaxSnakeLength {
 		return false
 	}
 	if this.Mode != that1.Mode {
 		return false
 	}
+	if this.SnakeTimeout != that1.SnakeTimeout {
+		return false
+	}
+	if this.SnakeTimeoutGrace != that1.SnakeTimeoutGrace {
+		return false
+	}
 	return true
 }
 func (this *GameFrame) Equal(that interface{}) bool {
@@ -1394,6 +2298,55 @@ func (this *GameFrame) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if len(this.Events) != len(that1.Events) {
+		return false
+	}
+	for i := range this.Events {
+		if !this.Events[i].Equal(that1.Events[i]) {
+			return false
+		}
+	}
+	if len(this.Hazards) != len(that1.Hazards) {
+		return false
+	}
+	for i := range this.Hazards {
+		if !this.Hazards[i].Equal(that1.Hazards[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Event) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Event)
+	if !ok {
+		that2, ok := that.(Event)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Type != that1.Type {
+		return false
+	}
+	if this.SnakeID != that1.SnakeID {
+		return false
+	}
+	if !this.Point.Equal(that1.Point) {
+		return false
+	}
+	if this.Cause != that1.Cause {
+		return false
+	}
 	return true
 }
 func (this *Point) Equal(that interface{}) bool {
@@ -1468,6 +2421,21 @@ func (this *Snake) Equal(that interface{}) bool {
 	if this.Color != that1.Color {
 		return false
 	}
+	if this.ConsecutiveFailures != that1.ConsecutiveFailures {
+		return false
+	}
+	if this.Squad != that1.Squad {
+		return false
+	}
+	if this.Unresponsive != that1.Unresponsive {
+		return false
+	}
+	if this.MoveStatus != that1.MoveStatus {
+		return false
+	}
+	if this.Facing != that1.Facing {
+		return false
+	}
 	return true
 }
 func (this *Death) Equal(that interface{}) bool {
@@ -1495,6 +2463,9 @@ func (this *Death) Equal(that interface{}) bool {
 	if this.Turn != that1.Turn {
 		return false
 	}
+	if this.EliminatedBy != that1.EliminatedBy {
+		return false
+	}
 	return true
 }
 
@@ -1521,13 +2492,21 @@ type ControllerClient interface {
 	// Create creates a new game, but doesn't start running frames.
 	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
 	// AddGameFrame adds a new game frame to the game. A lock must be held for this
-	// call to succeed.
+	// call to succeed. The game of the response only has its ID and status set.
 	AddGameFrame(ctx context.Context, in *AddGameFrameRequest, opts ...grpc.CallOption) (*AddGameFrameResponse, error)
 	// ListGameFrames will list all game frames given a limit and offset.
 	ListGameFrames(ctx context.Context, in *ListGameFramesRequest, opts ...grpc.CallOption) (*ListGameFramesResponse, error)
 	// EndGame sets the game status to complete. A lock must be held for this call
 	// to succeed.
 	EndGame(ctx context.Context, in *EndGameRequest, opts ...grpc.CallOption) (*EndGameResponse, error)
+	// PauseGame stops workers from producing frames for a running game, without
+	// ending it.
+	PauseGame(ctx context.Context, in *PauseGameRequest, opts ...grpc.CallOption) (*PauseGameResponse, error)
+	// ResumeGame makes a paused game ready to be picked up by a worker again.
+	ResumeGame(ctx context.Context, in *ResumeGameRequest, opts ...grpc.CallOption) (*ResumeGameResponse, error)
+	// AbortGame ends a game with an error status on an operator's request. The
+	// reason is recorded on a last frame and any lock on the game is released.
+	AbortGame(ctx context.Context, in *AbortGameRequest, opts ...grpc.CallOption) (*AbortGameResponse, error)
 	// ping will ping the controller.
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
 	// ValidateSnake will call a snake URL and return stats about it's validity.
@@ -1605,6 +2584,33 @@ func (c *controllerClient) EndGame(ctx context.Context, in *EndGameRequest, opts
 	return out, nil
 }
 
+func (c *controllerClient) PauseGame(ctx context.Context, in *PauseGameRequest, opts ...grpc.CallOption) (*PauseGameResponse, error) {
+	out := new(PauseGameResponse)
+	err := grpc.Invoke(ctx, "/pb.Controller/PauseGame", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ResumeGame(ctx context.Context, in *ResumeGameRequest, opts ...grpc.CallOption) (*ResumeGameResponse, error) {
+	out := new(ResumeGameResponse)
+	err := grpc.Invoke(ctx, "/pb.Controller/ResumeGame", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) AbortGame(ctx context.Context, in *AbortGameRequest, opts ...grpc.CallOption) (*AbortGameResponse, error) {
+	out := new(AbortGameResponse)
+	err := grpc.Invoke(ctx, "/pb.Controller/AbortGame", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controllerClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
 	out := new(PingResponse)
 	err := grpc.Invoke(ctx, "/pb.Controller/Ping", in, out, c.cc, opts...)
@@ -1638,13 +2644,21 @@ type ControllerServer interface {
 	// Create creates a new game, but doesn't start running frames.
 	Create(context.Context, *CreateRequest) (*CreateResponse, error)
 	// AddGameFrame adds a new game frame to the game. A lock must be held for this
-	// call to succeed.
+	// call to succeed. The game of the response only has its ID and status set.
 	AddGameFrame(context.Context, *AddGameFrameRequest) (*AddGameFrameResponse, error)
 	// ListGameFrames will list all game frames given a limit and offset.
 	ListGameFrames(context.Context, *ListGameFramesRequest) (*ListGameFramesResponse, error)
 	// EndGame sets the game status to complete. A lock must be held for this call
 	// to succeed.
 	EndGame(context.Context, *EndGameRequest) (*EndGameResponse, error)
+	// PauseGame stops workers from producing frames for a running game, without
+	// ending it.
+	PauseGame(context.Context, *PauseGameRequest) (*PauseGameResponse, error)
+	// ResumeGame makes a paused game ready to be picked up by a worker again.
+	ResumeGame(context.Context, *ResumeGameRequest) (*ResumeGameResponse, error)
+	// AbortGame ends a game with an error status on an operator's request. The
+	// reason is recorded on a last frame and any lock on the game is released.
+	AbortGame(context.Context, *AbortGameRequest) (*AbortGameResponse, error)
 	// ping will ping the controller.
 	Ping(context.Context, *PingRequest) (*PingResponse, error)
 	// ValidateSnake will call a snake URL and return stats about it's validity.
@@ -1781,6 +2795,60 @@ func _Controller_EndGame_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Controller_PauseGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).PauseGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Controller/PauseGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).PauseGame(ctx, req.(*PauseGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ResumeGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ResumeGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Controller/ResumeGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ResumeGame(ctx, req.(*ResumeGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_AbortGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).AbortGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Controller/AbortGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).AbortGame(ctx, req.(*AbortGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Controller_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PingRequest)
 	if err := dec(in); err != nil {
@@ -1849,6 +2917,18 @@ var _Controller_serviceDesc = grpc.ServiceDesc{
 			MethodName: "EndGame",
 			Handler:    _Controller_EndGame_Handler,
 		},
+		{
+			MethodName: "PauseGame",
+			Handler:    _Controller_PauseGame_Handler,
+		},
+		{
+			MethodName: "ResumeGame",
+			Handler:    _Controller_ResumeGame_Handler,
+		},
+		{
+			MethodName: "AbortGame",
+			Handler:    _Controller_AbortGame_Handler,
+		},
 		{
 			MethodName: "Ping",
 			Handler:    _Controller_Ping_Handler,
@@ -1928,6 +3008,7 @@ func NewPopulatedScore(r randyController, easy bool) *Score {
 
 func NewPopulatedPopRequest(r randyController, easy bool) *PopRequest {
 	this := &PopRequest{}
+	this.WorkerID = string(randStringController(r))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -1999,6 +3080,32 @@ func NewPopulatedCreateRequest(r randyController, easy bool) *CreateRequest {
 			this.Snakes[i] = NewPopulatedSnakeOptions(r, easy)
 		}
 	}
+	if r.Intn(10) != 0 {
+		this.Ruleset = NewPopulatedRuleset(r, easy)
+	}
+	this.Seed = int64(r.Int63())
+	if r.Intn(2) == 0 {
+		this.Seed *= -1
+	}
+	if r.Intn(10) != 0 {
+		v3 := r.Intn(5)
+		this.Hazards = make([]*Point, v3)
+		for i := 0; i < v3; i++ {
+			this.Hazards[i] = NewPopulatedPoint(r, easy)
+		}
+	}
+	this.SnakeTimeout = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.SnakeTimeout *= -1
+	}
+	if r.Intn(10) != 0 {
+		v4 := r.Intn(5)
+		this.Obstacles = make([]*Point, v4)
+		for i := 0; i < v4; i++ {
+			this.Obstacles[i] = NewPopulatedPoint(r, easy)
+		}
+	}
+	this.Name = string(randStringController(r))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -2044,6 +3151,7 @@ func NewPopulatedListGameFramesRequest(r randyController, easy bool) *ListGameFr
 	if r.Intn(2) == 0 {
 		this.Offset *= -1
 	}
+	this.Reverse = bool(bool(r.Intn(2) == 0))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -2052,9 +3160,9 @@ func NewPopulatedListGameFramesRequest(r randyController, easy bool) *ListGameFr
 func NewPopulatedListGameFramesResponse(r randyController, easy bool) *ListGameFramesResponse {
 	this := &ListGameFramesResponse{}
 	if r.Intn(10) != 0 {
-		v3 := r.Intn(5)
-		this.Frames = make([]*GameFrame, v3)
-		for i := 0; i < v3; i++ {
+		v5 := r.Intn(5)
+		this.Frames = make([]*GameFrame, v5)
+		for i := 0; i < v5; i++ {
 			this.Frames[i] = NewPopulatedGameFrame(r, easy)
 		}
 	}
@@ -2082,6 +3190,52 @@ func NewPopulatedEndGameResponse(r randyController, easy bool) *EndGameResponse
 	return this
 }
 
+func NewPopulatedPauseGameRequest(r randyController, easy bool) *PauseGameRequest {
+	this := &PauseGameRequest{}
+	this.ID = string(randStringController(r))
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedPauseGameResponse(r randyController, easy bool) *PauseGameResponse {
+	this := &PauseGameResponse{}
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedResumeGameRequest(r randyController, easy bool) *ResumeGameRequest {
+	this := &ResumeGameRequest{}
+	this.ID = string(randStringController(r))
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedResumeGameResponse(r randyController, easy bool) *ResumeGameResponse {
+	this := &ResumeGameResponse{}
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedAbortGameRequest(r randyController, easy bool) *AbortGameRequest {
+	this := &AbortGameRequest{}
+	this.ID = string(randStringController(r))
+	this.Reason = string(randStringController(r))
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedAbortGameResponse(r randyController, easy bool) *AbortGameResponse {
+	this := &AbortGameResponse{}
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
 func NewPopulatedPingRequest(r randyController, easy bool) *PingRequest {
 	this := &PingRequest{}
 	if !easy && r.Intn(10) != 0 {
@@ -2102,6 +3256,7 @@ func NewPopulatedSnakeOptions(r randyController, easy bool) *SnakeOptions {
 	this.Name = string(randStringController(r))
 	this.URL = string(randStringController(r))
 	this.ID = string(randStringController(r))
+	this.Squad = string(randStringController(r))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -2128,6 +3283,116 @@ func NewPopulatedGame(r randyController, easy bool) *Game {
 		this.TurnTimeout *= -1
 	}
 	this.Mode = string(randStringController(r))
+	if r.Intn(10) != 0 {
+		this.Ruleset = NewPopulatedRuleset(r, easy)
+	}
+	this.RulesetVersion = string(randStringController(r))
+	this.Seed = int64(r.Int63())
+	if r.Intn(2) == 0 {
+		this.Seed *= -1
+	}
+	this.FoodTarget = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.FoodTarget *= -1
+	}
+	if r.Intn(10) != 0 {
+		v6 := r.Intn(5)
+		this.Obstacles = make([]*Point, v6)
+		for i := 0; i < v6; i++ {
+			this.Obstacles[i] = NewPopulatedPoint(r, easy)
+		}
+	}
+	this.Name = string(randStringController(r))
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedRuleset(r randyController, easy bool) *Ruleset {
+	this := &Ruleset{}
+	this.Name = string(randStringController(r))
+	this.MaxHealth = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.MaxHealth *= -1
+	}
+	this.EliminateUnresponsive = bool(bool(r.Intn(2) == 0))
+	this.MaxConsecutiveFailures = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.MaxConsecutiveFailures *= -1
+	}
+	this.EqualHeadToHead = string(randStringController(r))
+	this.FoodSpawnStartTurn = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.FoodSpawnStartTurn *= -1
+	}
+	this.SquadMode = bool(bool(r.Intn(2) == 0))
+	this.AllowBodyCollisions = bool(bool(r.Intn(2) == 0))
+	this.StarvationStartTurn = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.StarvationStartTurn *= -1
+	}
+	this.RespawnAfterTurns = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.RespawnAfterTurns *= -1
+	}
+	this.UnresponsiveAfterMoves = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.UnresponsiveAfterMoves *= -1
+	}
+	this.StartingHealth = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.StartingHealth *= -1
+	}
+	this.WrapHorizontal = bool(bool(r.Intn(2) == 0))
+	this.WrapVertical = bool(bool(r.Intn(2) == 0))
+	this.ShortCircuitGameOver = bool(bool(r.Intn(2) == 0))
+	this.HazardFoodPlacement = string(randStringController(r))
+	this.MaxFoodSpawnPerTurn = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.MaxFoodSpawnPerTurn *= -1
+	}
+	this.SquadWipe = bool(bool(r.Intn(2) == 0))
+	this.DisableFood = bool(bool(r.Intn(2) == 0))
+	this.MaxConcurrentMoves = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.MaxConcurrentMoves *= -1
+	}
+	this.HazardBorder = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.HazardBorder *= -1
+	}
+	this.HazardDamagePerTurn = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.HazardDamagePerTurn *= -1
+	}
+	this.DisableFoodReplacement = bool(bool(r.Intn(2) == 0))
+	this.MinimumFood = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.MinimumFood *= -1
+	}
+	this.StartingDirection = string(randStringController(r))
+	this.RespawnLength = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.RespawnLength *= -1
+	}
+	this.HazardHealRate = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.HazardHealRate *= -1
+	}
+	this.DebugMoveRequests = bool(bool(r.Intn(2) == 0))
+	this.MaxSnakeLength = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.MaxSnakeLength *= -1
+	}
+	this.Mode = string(randStringController(r))
+	this.SnakeTimeout = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.SnakeTimeout *= -1
+	}
+	this.SnakeTimeoutGrace = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.SnakeTimeoutGrace *= -1
+	}
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -2140,19 +3405,46 @@ func NewPopulatedGameFrame(r randyController, easy bool) *GameFrame {
 		this.Turn *= -1
 	}
 	if r.Intn(10) != 0 {
-		v4 := r.Intn(5)
-		this.Food = make([]*Point, v4)
-		for i := 0; i < v4; i++ {
+		v7 := r.Intn(5)
+		this.Food = make([]*Point, v7)
+		for i := 0; i < v7; i++ {
 			this.Food[i] = NewPopulatedPoint(r, easy)
 		}
 	}
 	if r.Intn(10) != 0 {
-		v5 := r.Intn(5)
-		this.Snakes = make([]*Snake, v5)
-		for i := 0; i < v5; i++ {
+		v8 := r.Intn(5)
+		this.Snakes = make([]*Snake, v8)
+		for i := 0; i < v8; i++ {
 			this.Snakes[i] = NewPopulatedSnake(r, easy)
 		}
 	}
+	if r.Intn(10) != 0 {
+		v9 := r.Intn(5)
+		this.Events = make([]*Event, v9)
+		for i := 0; i < v9; i++ {
+			this.Events[i] = NewPopulatedEvent(r, easy)
+		}
+	}
+	if r.Intn(10) != 0 {
+		v10 := r.Intn(5)
+		this.Hazards = make([]*Point, v10)
+		for i := 0; i < v10; i++ {
+			this.Hazards[i] = NewPopulatedPoint(r, easy)
+		}
+	}
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedEvent(r randyController, easy bool) *Event {
+	this := &Event{}
+	this.Type = string(randStringController(r))
+	this.SnakeID = string(randStringController(r))
+	if r.Intn(10) != 0 {
+		this.Point = NewPopulatedPoint(r, easy)
+	}
+	this.Cause = string(randStringController(r))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -2179,9 +3471,9 @@ func NewPopulatedSnake(r randyController, easy bool) *Snake {
 	this.Name = string(randStringController(r))
 	this.URL = string(randStringController(r))
 	if r.Intn(10) != 0 {
-		v6 := r.Intn(5)
-		this.Body = make([]*Point, v6)
-		for i := 0; i < v6; i++ {
+		v11 := r.Intn(5)
+		this.Body = make([]*Point, v11)
+		for i := 0; i < v11; i++ {
 			this.Body[i] = NewPopulatedPoint(r, easy)
 		}
 	}
@@ -2193,6 +3485,14 @@ func NewPopulatedSnake(r randyController, easy bool) *Snake {
 		this.Death = NewPopulatedDeath(r, easy)
 	}
 	this.Color = string(randStringController(r))
+	this.ConsecutiveFailures = int32(r.Int31())
+	if r.Intn(2) == 0 {
+		this.ConsecutiveFailures *= -1
+	}
+	this.Squad = string(randStringController(r))
+	this.Unresponsive = bool(bool(r.Intn(2) == 0))
+	this.MoveStatus = string(randStringController(r))
+	this.Facing = string(randStringController(r))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -2205,6 +3505,7 @@ func NewPopulatedDeath(r randyController, easy bool) *Death {
 	if r.Intn(2) == 0 {
 		this.Turn *= -1
 	}
+	this.EliminatedBy = string(randStringController(r))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -2229,9 +3530,9 @@ func randUTF8RuneController(r randyController) rune {
 	return rune(ru + 61)
 }
 func randStringController(r randyController) string {
-	v7 := r.Intn(100)
-	tmps := make([]rune, v7)
-	for i := 0; i < v7; i++ {
+	v12 := r.Intn(100)
+	tmps := make([]rune, v12)
+	for i := 0; i < v12; i++ {
 		tmps[i] = randUTF8RuneController(r)
 	}
 	return string(tmps)
@@ -2253,11 +3554,11 @@ func randFieldController(dAtA []byte, r randyController, fieldNumber int, wire i
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateController(dAtA, uint64(key))
-		v8 := r.Int63()
+		v13 := r.Int63()
 		if r.Intn(2) == 0 {
-			v8 *= -1
+			v13 *= -1
 		}
-		dAtA = encodeVarintPopulateController(dAtA, uint64(v8))
+		dAtA = encodeVarintPopulateController(dAtA, uint64(v13))
 	case 1:
 		dAtA = encodeVarintPopulateController(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -2286,69 +3587,123 @@ func encodeVarintPopulateController(dAtA []byte, v uint64) []byte {
 func init() { proto.RegisterFile("controller.proto", fileDescriptorController) }
 
 var fileDescriptorController = []byte{
-	// 1010 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x56, 0x5f, 0x6f, 0x1b, 0x45,
-	0x10, 0xd7, 0xf9, 0x6c, 0xa7, 0x9e, 0xd8, 0x8e, 0xb3, 0x49, 0x8b, 0x7b, 0x82, 0x34, 0x2c, 0x02,
-	0x05, 0x01, 0x89, 0x48, 0x41, 0x88, 0xc7, 0x36, 0x69, 0x02, 0x52, 0x42, 0xa2, 0x6b, 0x5a, 0x5a,
-	0x78, 0x3a, 0xfb, 0x36, 0xf6, 0x29, 0xce, 0xad, 0xb9, 0x3b, 0x83, 0x10, 0x5f, 0x08, 0x09, 0x89,
-	0x47, 0xc4, 0x33, 0xdf, 0x04, 0xbe, 0x03, 0x12, 0x8f, 0xec, 0xce, 0xce, 0xde, 0x9f, 0xf8, 0xdc,
-	0x87, 0x44, 0x3b, 0x33, 0xbf, 0x9d, 0x9d, 0xfd, 0xcd, 0xec, 0xcf, 0x07, 0x83, 0xb1, 0x8c, 0xb3,
-	0x44, 0xce, 0x66, 0x22, 0xd9, 0x9f, 0x27, 0x32, 0x93, 0xac, 0x31, 0x1f, 0x79, 0x9f, 0x4c, 0xa2,
-	0x6c, 0xba, 0x18, 0xed, 0x8f, 0xe5, 0xed, 0xc1, 0x44, 0x4e, 0xe4, 0x01, 0x86, 0x46, 0x8b, 0x6b,
-	0xb4, 0xd0, 0xc0, 0x95, 0xd9, 0xc2, 0xf7, 0x60, 0xfb, 0x65, 0x30, 0x8b, 0xc2, 0x20, 0x13, 0xcf,
-	0xe3, 0xe0, 0x46, 0xf8, 0xe2, 0x87, 0x85, 0x48, 0x33, 0x36, 0x00, 0xf7, 0x85, 0x7f, 0x36, 0x74,
-	0x76, 0x9d, 0xbd, 0x8e, 0xaf, 0x97, 0xfc, 0x2f, 0x07, 0xee, 0xdf, 0x81, 0xa6, 0x73, 0x19, 0xa7,
-	0x82, 0x7d, 0x09, 0xeb, 0xcf, 0xb3, 0x20, 0xc9, 0xd4, 0xbf, 0x6c, 0x91, 0xe2, 0x9e, 0xf5, 0xc3,
-	0xb7, 0xf6, 0xe7, 0xa3, 0xfd, 0x0a, 0xce, 0x84, 0xfd, 0x32, 0x96, 0x7d, 0x01, 0x70, 0x2e, 0x7f,
-	0xa4, 0xd0, 0xb0, 0xf1, 0xe6, 0x9d, 0x25, 0x28, 0xfb, 0x1c, 0x3a, 0xcf, 0xe2, 0x90, 0xf6, 0xb9,
-	0x6f, 0xde, 0x57, 0x20, 0xf9, 0xef, 0x0e, 0x6c, 0xd5, 0x40, 0xd8, 0x10, 0xd6, 0xce, 0x45, 0x9a,
-	0x06, 0x13, 0x41, 0x57, 0xb6, 0x26, 0x7b, 0x00, 0xed, 0x67, 0x49, 0x22, 0x13, 0x5d, 0x9d, 0xab,
-	0x02, 0x64, 0x31, 0x06, 0xcd, 0x2c, 0xba, 0x15, 0x78, 0x76, 0xcb, 0xc7, 0xb5, 0x26, 0x2d, 0x09,
-	0x7e, 0x1a, 0x36, 0x0d, 0x69, 0x6a, 0xc9, 0x76, 0x00, 0x52, 0x3c, 0xe1, 0x48, 0x86, 0x62, 0xd8,
-	0x42, 0x6c, 0xc9, 0xc3, 0x1e, 0x41, 0x2b, 0x1d, 0xcb, 0x44, 0x0c, 0xdb, 0x78, 0x85, 0x0e, 0x5e,
-	0x41, 0x3b, 0x7c, 0xe3, 0xe7, 0x17, 0xd0, 0x42, 0x9b, 0x71, 0xe8, 0x8e, 0xa7, 0x62, 0x7c, 0x93,
-	0x5e, 0x06, 0x69, 0x2a, 0x42, 0x2c, 0xb3, 0xe5, 0x57, 0x7c, 0x05, 0xe6, 0x24, 0x88, 0x66, 0x0a,
-	0xd3, 0x28, 0x63, 0x8c, 0x8f, 0x77, 0x01, 0x2e, 0xe5, 0x9c, 0xda, 0xcc, 0x1f, 0xc3, 0x3a, 0x5a,
-	0xd4, 0xc9, 0x3e, 0x34, 0xbe, 0x3e, 0x26, 0x06, 0xd4, 0x8a, 0x6d, 0x43, 0xeb, 0x4a, 0xde, 0x88,
-	0x18, 0x33, 0x75, 0x7c, 0x63, 0xf0, 0x47, 0xd0, 0x23, 0x66, 0x69, 0x58, 0xee, 0x6c, 0xe3, 0xdf,
-	0x43, 0xdf, 0x02, 0x28, 0xf1, 0xdb, 0xd0, 0x3c, 0x0d, 0x6e, 0x05, 0xcd, 0xc6, 0x3d, 0x7d, 0x4d,
-	0x6d, 0xfb, 0xe8, 0x65, 0x1f, 0x41, 0xe7, 0x2c, 0x48, 0xb3, 0x93, 0x44, 0x43, 0xcc, 0x10, 0xf4,
-	0x2c, 0x04, 0x9d, 0x7e, 0x11, 0xe7, 0x3b, 0xd0, 0xc5, 0x09, 0x5a, 0x75, 0xf8, 0x06, 0x56, 0xa7,
-	0xe3, 0xe6, 0x6c, 0xfe, 0x0b, 0xf4, 0x8e, 0x12, 0xa1, 0xa6, 0xd6, 0xee, 0x50, 0xb7, 0xfa, 0x36,
-	0x0a, 0xb3, 0x29, 0x71, 0x68, 0x0c, 0xdd, 0xe8, 0xaf, 0x44, 0x34, 0x99, 0x66, 0x44, 0x1b, 0x59,
-	0xba, 0xd1, 0x27, 0x52, 0x86, 0xb6, 0xd1, 0x7a, 0xcd, 0xf6, 0xa0, 0x8d, 0x53, 0x94, 0xaa, 0x5e,
-	0xbb, 0xaa, 0xda, 0x41, 0x3e, 0x7a, 0x17, 0xf3, 0x2c, 0x52, 0xa7, 0xfa, 0x14, 0xe7, 0xbb, 0xd0,
-	0xb7, 0x87, 0xd7, 0x73, 0xcc, 0x7d, 0xd8, 0x7a, 0x12, 0x86, 0xc5, 0x55, 0xeb, 0xaf, 0xa5, 0x39,
-	0xca, 0x31, 0x2b, 0x38, 0xca, 0x97, 0xfc, 0x33, 0xd8, 0xae, 0xe6, 0x2c, 0xda, 0x30, 0xa9, 0x6d,
-	0x83, 0xf6, 0xf2, 0x17, 0x70, 0xff, 0x2c, 0x4a, 0xb3, 0x7c, 0xdb, 0xaa, 0xfe, 0x6a, 0x02, 0xcf,
-	0xa2, 0xdb, 0xc8, 0x32, 0x65, 0x0c, 0x4d, 0xe0, 0xc5, 0xf5, 0x75, 0x2a, 0x32, 0xa2, 0x8a, 0x2c,
-	0x95, 0xf6, 0xc1, 0xdd, 0xb4, 0x54, 0xce, 0xfb, 0xd0, 0x36, 0x1e, 0x95, 0xdb, 0x5d, 0xbe, 0x10,
-	0x05, 0xf5, 0x71, 0x47, 0x72, 0x11, 0xe7, 0xc7, 0xa1, 0xa1, 0x99, 0x55, 0xef, 0xfa, 0x74, 0x35,
-	0x65, 0x7c, 0x13, 0x36, 0x72, 0x04, 0xcd, 0x42, 0x4f, 0xcd, 0x7b, 0x14, 0x4f, 0xec, 0xf8, 0xef,
-	0x41, 0xd7, 0x98, 0x54, 0x90, 0x92, 0x81, 0x97, 0x22, 0x49, 0x55, 0x07, 0xad, 0x0c, 0x90, 0xc9,
-	0x8f, 0xd5, 0xd4, 0x95, 0xfa, 0xab, 0xa7, 0xe2, 0x1b, 0xcb, 0x64, 0xc7, 0xc7, 0xb5, 0xd5, 0xcc,
-	0x46, 0xae, 0x99, 0x54, 0x91, 0x9b, 0x57, 0xf4, 0x87, 0x63, 0xde, 0xc1, 0x12, 0xa3, 0x8a, 0xbb,
-	0x92, 0x06, 0x2a, 0x95, 0x21, 0x5d, 0xca, 0x47, 0xd5, 0xad, 0x1f, 0xd5, 0x66, 0x65, 0x54, 0x39,
-	0x15, 0x79, 0xa5, 0xc4, 0x48, 0x2e, 0x32, 0x14, 0x15, 0xf5, 0xfe, 0xcb, 0x3e, 0xb6, 0x0b, 0xeb,
-	0x57, 0x8b, 0x24, 0xb6, 0x90, 0x35, 0x84, 0x94, 0x5d, 0xfa, 0x6a, 0xe7, 0x5a, 0xad, 0xee, 0x99,
-	0xab, 0xe9, 0x35, 0x0f, 0x4a, 0xd3, 0xa7, 0x01, 0x1a, 0x4f, 0xcf, 0x07, 0xd7, 0xec, 0x1d, 0x7a,
-	0x25, 0x0d, 0x6c, 0x24, 0xea, 0xd8, 0xa5, 0x8c, 0xe2, 0x8c, 0x1e, 0xcc, 0xbb, 0xf9, 0x83, 0x71,
-	0x0b, 0x80, 0x11, 0x62, 0xfb, 0x52, 0xde, 0x83, 0x16, 0xee, 0x60, 0x5d, 0x70, 0x5e, 0x51, 0x6e,
-	0xe7, 0x95, 0xb6, 0x5e, 0x53, 0xe3, 0x9d, 0xd7, 0xfc, 0x37, 0x47, 0xe9, 0xa1, 0xc6, 0x2f, 0x31,
-	0x68, 0x1b, 0xd2, 0x58, 0x6e, 0x88, 0x5b, 0x34, 0x44, 0x95, 0xf9, 0x54, 0x86, 0x3f, 0xd3, 0xb3,
-	0x2d, 0x97, 0xa9, 0xdd, 0x86, 0xd8, 0x60, 0xa6, 0xf8, 0x6e, 0x59, 0x62, 0xb5, 0xa5, 0x65, 0xfa,
-	0x58, 0x3d, 0xe2, 0x69, 0x59, 0xa6, 0xd1, 0xe1, 0x1b, 0xbf, 0x19, 0xd1, 0x99, 0x4c, 0x90, 0xcf,
-	0x8e, 0x6f, 0x0c, 0xfe, 0x29, 0x94, 0xc2, 0xc1, 0x22, 0xb5, 0xe3, 0x62, 0x8c, 0x9c, 0xc7, 0x46,
-	0xc1, 0xe3, 0xe1, 0xbf, 0x2e, 0xc0, 0x51, 0xfe, 0xbb, 0xce, 0x3e, 0x00, 0x57, 0xe9, 0x33, 0xeb,
-	0x9b, 0x42, 0xad, 0x6c, 0x7b, 0x1b, 0xb9, 0x4d, 0x83, 0x7b, 0x60, 0xe7, 0x87, 0x6d, 0x22, 0xb3,
-	0x65, 0x79, 0xf6, 0x58, 0xd9, 0x45, 0x1b, 0x3e, 0x56, 0x3c, 0x6a, 0x95, 0x64, 0x03, 0x0a, 0xe6,
-	0x82, 0xea, 0x6d, 0x96, 0x3c, 0x45, 0x7a, 0xa3, 0x62, 0x26, 0x7d, 0x45, 0x4e, 0x4d, 0xfa, 0x3b,
-	0x22, 0xf7, 0x04, 0xba, 0x65, 0x01, 0x62, 0xf8, 0xdb, 0x5c, 0x23, 0x73, 0xde, 0x70, 0x39, 0x40,
-	0x29, 0x4e, 0xa1, 0x5f, 0x95, 0x0d, 0xf6, 0x50, 0x63, 0x6b, 0x15, 0xca, 0xf3, 0xea, 0x42, 0x94,
-	0xe8, 0x10, 0xd6, 0x48, 0x06, 0x18, 0x96, 0x5a, 0x55, 0x0d, 0x6f, 0xab, 0xe2, 0xa3, 0x3d, 0x1f,
-	0x42, 0x53, 0x0b, 0x03, 0x33, 0x44, 0x17, 0x8a, 0xe1, 0x0d, 0x0a, 0x07, 0x41, 0x8f, 0xa1, 0x57,
-	0xf9, 0x2c, 0x62, 0x78, 0xa5, 0xba, 0x8f, 0x2a, 0xef, 0x61, 0x4d, 0xc4, 0x64, 0x79, 0x3a, 0xf8,
-	0xef, 0xef, 0x1d, 0xe7, 0xd7, 0x7f, 0x76, 0x9c, 0x3f, 0xd5, 0xdf, 0x77, 0xea, 0x43, 0x6e, 0xd4,
-	0xc6, 0x0f, 0xb4, 0xc7, 0xff, 0x07, 0x00, 0x00, 0xff, 0xff, 0x70, 0x40, 0xd4, 0xec, 0xe7, 0x09,
-	0x00, 0x00,
+	// 1875 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x58, 0xcd, 0x72, 0xdb, 0xc8,
+	0x11, 0x2e, 0x92, 0xa2, 0x28, 0x36, 0x45, 0x89, 0x1a, 0xfd, 0x2c, 0xcc, 0xd8, 0xb2, 0x16, 0xce,
+	0x6e, 0x94, 0xca, 0x46, 0x4e, 0x69, 0x37, 0xff, 0x27, 0x59, 0x94, 0x2d, 0x57, 0x49, 0x91, 0x0a,
+	0x92, 0xed, 0xdd, 0xcd, 0x69, 0x48, 0x8e, 0x29, 0x94, 0x41, 0x0c, 0x3d, 0x18, 0xc8, 0xf2, 0x3e,
+	0x50, 0x2a, 0xb9, 0xe4, 0x9c, 0x43, 0x4e, 0x79, 0x81, 0x7d, 0x86, 0xf8, 0x21, 0x52, 0x39, 0xa6,
+	0xba, 0x67, 0x00, 0x0c, 0x49, 0x50, 0x95, 0x0b, 0x6b, 0xfa, 0xeb, 0x9e, 0xbf, 0xee, 0x0f, 0x3d,
+	0xdd, 0x84, 0xce, 0x40, 0xc6, 0x5a, 0xc9, 0x28, 0x12, 0xea, 0x60, 0xa2, 0xa4, 0x96, 0xac, 0x3a,
+	0xe9, 0x77, 0x7f, 0x39, 0x0a, 0xf5, 0x4d, 0xda, 0x3f, 0x18, 0xc8, 0xf1, 0xd3, 0x91, 0x1c, 0xc9,
+	0xa7, 0xa4, 0xea, 0xa7, 0x6f, 0x49, 0x22, 0x81, 0x46, 0x66, 0x8a, 0xbf, 0x0f, 0x5b, 0xaf, 0x79,
+	0x14, 0x0e, 0xb9, 0x16, 0x57, 0x31, 0x7f, 0x27, 0x02, 0xf1, 0x3e, 0x15, 0x89, 0x66, 0x1d, 0xa8,
+	0xbd, 0x0a, 0xce, 0xbc, 0xca, 0x5e, 0x65, 0xbf, 0x19, 0xe0, 0xd0, 0xff, 0x57, 0x05, 0xb6, 0x67,
+	0x4c, 0x93, 0x89, 0x8c, 0x13, 0xc1, 0x7e, 0x0f, 0xad, 0x2b, 0xcd, 0x95, 0xbe, 0xd2, 0x5c, 0xa7,
+	0x09, 0xcd, 0x69, 0x1d, 0x7e, 0x76, 0x30, 0xe9, 0x1f, 0x4c, 0xd9, 0x19, 0x75, 0xe0, 0xda, 0xb2,
+	0xdf, 0x02, 0x9c, 0xcb, 0x5b, 0xab, 0xf2, 0xaa, 0xf7, 0xcf, 0x74, 0x4c, 0xd9, 0xaf, 0xa1, 0x79,
+	0x12, 0x0f, 0xed, 0xbc, 0xda, 0xfd, 0xf3, 0x0a, 0x4b, 0xff, 0xef, 0x15, 0xd8, 0x2c, 0x31, 0x61,
+	0x1e, 0x34, 0xce, 0x45, 0x92, 0xf0, 0x91, 0xb0, 0x57, 0xce, 0x44, 0xb6, 0x03, 0xcb, 0x27, 0x4a,
+	0x49, 0x85, 0xa7, 0xab, 0xed, 0x37, 0x03, 0x2b, 0x31, 0x06, 0x4b, 0x3a, 0x1c, 0x0b, 0xda, 0xbb,
+	0x1e, 0xd0, 0x18, 0x9d, 0xa6, 0xf8, 0x07, 0x6f, 0xc9, 0x38, 0x4d, 0xf1, 0x0f, 0x6c, 0x17, 0x20,
+	0xa1, 0x1d, 0x8e, 0xe5, 0x50, 0x78, 0x75, 0xb2, 0x75, 0x10, 0xf6, 0x18, 0xea, 0xc9, 0x40, 0x2a,
+	0xe1, 0x2d, 0xd3, 0x15, 0x9a, 0x74, 0x05, 0x04, 0x02, 0x83, 0xfb, 0x17, 0x50, 0x27, 0x99, 0xf9,
+	0xb0, 0x3a, 0xb8, 0x11, 0x83, 0x77, 0xc9, 0x25, 0x4f, 0x12, 0x31, 0xa4, 0x63, 0xd6, 0x83, 0x29,
+	0xac, 0xb0, 0x79, 0xce, 0xc3, 0x48, 0x0c, 0xbd, 0xaa, 0x6b, 0x63, 0x30, 0x7f, 0x1f, 0xe0, 0x52,
+	0x4e, 0xb2, 0x30, 0x77, 0x61, 0xe5, 0x8d, 0x54, 0xef, 0x84, 0x7a, 0xd9, 0xb3, 0x17, 0xcf, 0x65,
+	0xff, 0x6b, 0x68, 0x91, 0xa5, 0x8d, 0xf2, 0x1a, 0x54, 0x73, 0xa3, 0xea, 0xcb, 0x1e, 0xdb, 0x82,
+	0xfa, 0xb5, 0x7c, 0x27, 0x62, 0xda, 0xa5, 0x19, 0x18, 0xc1, 0x7f, 0x0c, 0x6d, 0xeb, 0x75, 0xbb,
+	0xc3, 0xcc, 0x34, 0xff, 0xcf, 0xb0, 0x96, 0x19, 0xd8, 0x85, 0x1f, 0xc2, 0xd2, 0x0b, 0x3e, 0x16,
+	0x96, 0x37, 0x2b, 0xe8, 0x02, 0x94, 0x03, 0x42, 0xd9, 0x2f, 0xa0, 0x79, 0xc6, 0x13, 0xfd, 0x5c,
+	0xa1, 0x89, 0x21, 0x48, 0x3b, 0x33, 0x21, 0x30, 0x28, 0xf4, 0xfe, 0x2e, 0xac, 0x12, 0xbb, 0x16,
+	0x6d, 0xbe, 0x0e, 0x6d, 0xab, 0x37, 0x7b, 0xfb, 0xff, 0xac, 0x42, 0xfb, 0x58, 0x09, 0xae, 0x73,
+	0xe2, 0x6f, 0x41, 0xfd, 0x4d, 0x38, 0xd4, 0x37, 0xd6, 0xc1, 0x46, 0x40, 0x16, 0x9c, 0x8a, 0x70,
+	0x74, 0xa3, 0xad, 0x4f, 0xad, 0x84, 0x2c, 0x78, 0x2e, 0xe5, 0x30, 0x63, 0x01, 0x8e, 0xd9, 0x3e,
+	0x2c, 0x13, 0xc5, 0x12, 0x6f, 0x69, 0xaf, 0xb6, 0xdf, 0x3a, 0xec, 0xe4, 0xbc, 0xbc, 0x98, 0xe8,
+	0x50, 0xc6, 0x49, 0x60, 0xf5, 0xec, 0x0b, 0x68, 0x04, 0x69, 0x24, 0x12, 0xa1, 0x89, 0x1a, 0xad,
+	0xc3, 0x16, 0x9a, 0x5a, 0x28, 0xc8, 0x74, 0xb8, 0xc9, 0x95, 0x10, 0x43, 0xe2, 0x48, 0x2d, 0xa0,
+	0x31, 0x7b, 0x02, 0x8d, 0x53, 0xfe, 0x03, 0x57, 0xc3, 0xc4, 0x6b, 0xec, 0xd5, 0x32, 0xea, 0x5c,
+	0xca, 0x30, 0xd6, 0x41, 0xa6, 0x41, 0x3e, 0xd0, 0x4e, 0xd7, 0xe1, 0x58, 0xc8, 0x54, 0x7b, 0x2b,
+	0x86, 0x0f, 0x2e, 0xc6, 0x7e, 0x06, 0xcd, 0x8b, 0x7e, 0xa2, 0xf9, 0x20, 0x12, 0x89, 0xd7, 0x9c,
+	0x5d, 0xaa, 0xd0, 0xe1, 0x29, 0xfe, 0x84, 0x31, 0x00, 0xf2, 0x26, 0x8d, 0xfd, 0x3d, 0x58, 0xcb,
+	0xbc, 0x57, 0xce, 0x12, 0x3f, 0x80, 0xcd, 0xa3, 0xe1, 0xb0, 0x08, 0x56, 0x79, 0x60, 0x30, 0xca,
+	0xb9, 0xcd, 0x82, 0x28, 0xe7, 0x43, 0xff, 0x1b, 0xd8, 0x9a, 0x5e, 0xb3, 0x20, 0xd2, 0xa8, 0x94,
+	0x48, 0x88, 0xfa, 0x12, 0xb6, 0xcf, 0xc2, 0x44, 0xe7, 0xd3, 0x16, 0x31, 0x14, 0x19, 0x70, 0x16,
+	0x8e, 0xc3, 0x2c, 0xd4, 0x46, 0x40, 0x06, 0x5c, 0xbc, 0x7d, 0x8b, 0xa1, 0x32, 0xb1, 0xb6, 0x12,
+	0x66, 0x8e, 0x40, 0xdc, 0x0a, 0x95, 0x08, 0xfa, 0xee, 0x57, 0x82, 0x4c, 0xf4, 0x5f, 0xc1, 0xce,
+	0xec, 0x86, 0xf6, 0xa0, 0x5f, 0xc0, 0xb2, 0x41, 0xbc, 0xca, 0x5e, 0x6d, 0xfe, 0xaa, 0x56, 0x89,
+	0x07, 0x39, 0x96, 0x69, 0x9c, 0x1f, 0x84, 0x04, 0xf4, 0xf9, 0x49, 0x4c, 0xb7, 0x5f, 0xc4, 0xf2,
+	0x0d, 0x58, 0xcf, 0x2d, 0x2c, 0xcf, 0x7d, 0xe8, 0x5c, 0xf2, 0x34, 0x11, 0xf7, 0x4d, 0xdb, 0x84,
+	0x0d, 0xc7, 0xc6, 0x4e, 0x7c, 0x02, 0x1b, 0x81, 0x48, 0xd2, 0xf1, 0xbd, 0x33, 0xb7, 0x80, 0xb9,
+	0x46, 0x76, 0xea, 0x1f, 0xa0, 0x73, 0xd4, 0x97, 0x4a, 0xdf, 0x33, 0x13, 0xbd, 0x1a, 0x08, 0x9e,
+	0xc8, 0x2c, 0x8b, 0x58, 0x09, 0xcf, 0xe2, 0xcc, 0xb5, 0x0b, 0xb6, 0xa1, 0x75, 0x19, 0xc6, 0x23,
+	0xbb, 0x96, 0xbf, 0x0f, 0xab, 0x46, 0xb4, 0x5e, 0xf5, 0xa0, 0xf1, 0x5a, 0xa8, 0x24, 0x94, 0x71,
+	0x96, 0xc3, 0xad, 0xe8, 0x7f, 0x0f, 0xab, 0xee, 0xf7, 0x97, 0x53, 0xb9, 0x52, 0x50, 0x39, 0x7b,
+	0xf0, 0xaa, 0xf9, 0x83, 0x67, 0xcf, 0x5a, 0x73, 0x79, 0x71, 0xf5, 0x3e, 0xe5, 0x43, 0x9b, 0xdf,
+	0x8d, 0xe0, 0xff, 0xa7, 0x6a, 0xd2, 0x57, 0xd9, 0xd5, 0x9c, 0x67, 0xad, 0x19, 0x58, 0xa9, 0x48,
+	0x30, 0xb5, 0xf2, 0x04, 0xb3, 0x34, 0x95, 0x60, 0x66, 0x3f, 0xe1, 0xe5, 0x92, 0x4f, 0x78, 0x0f,
+	0x5a, 0xd7, 0xa9, 0x8a, 0x33, 0x93, 0x06, 0x99, 0xb8, 0x10, 0x5e, 0xf8, 0x1c, 0x1f, 0xa0, 0x15,
+	0x73, 0x61, 0x1c, 0xbb, 0xc9, 0xa7, 0x79, 0x4f, 0xf2, 0xf9, 0x12, 0xd6, 0xec, 0x30, 0x73, 0xae,
+	0x49, 0x00, 0x33, 0x68, 0x9e, 0xa4, 0x5a, 0x4e, 0x92, 0xda, 0x05, 0xc0, 0x8c, 0x78, 0xcd, 0xd5,
+	0x48, 0x68, 0x6f, 0xd5, 0xbc, 0x7e, 0x05, 0x32, 0x9d, 0x7b, 0xda, 0xff, 0x47, 0xee, 0x59, 0x73,
+	0x72, 0xcf, 0x8f, 0x00, 0x6e, 0x86, 0x9c, 0x0b, 0xe8, 0x43, 0x68, 0x9e, 0xf3, 0xbb, 0x53, 0xc1,
+	0x23, 0x7d, 0x63, 0xbf, 0xa0, 0x02, 0x60, 0xdf, 0xc0, 0xf6, 0x49, 0x14, 0x8e, 0xc3, 0x98, 0x6b,
+	0xf1, 0x2a, 0x56, 0x86, 0x43, 0xe1, 0xad, 0x79, 0xcf, 0x57, 0x82, 0x72, 0x25, 0xfb, 0x0d, 0xec,
+	0x9c, 0xf3, 0xbb, 0x63, 0xa4, 0xdb, 0x20, 0xd5, 0xe1, 0xad, 0xc0, 0x47, 0x35, 0x55, 0x94, 0xea,
+	0x71, 0x83, 0x05, 0x5a, 0xb6, 0x0f, 0xeb, 0x27, 0xef, 0x53, 0x1e, 0x9d, 0x0a, 0x3e, 0xbc, 0x96,
+	0xf8, 0x4b, 0x09, 0xbf, 0x19, 0xcc, 0xc2, 0xec, 0x00, 0x18, 0x3a, 0xe8, 0x6a, 0xc2, 0x3f, 0xc4,
+	0xf4, 0x54, 0x61, 0x18, 0x6d, 0xd4, 0x4b, 0x34, 0x78, 0x4b, 0xe2, 0x21, 0x85, 0xb7, 0x41, 0x67,
+	0x2f, 0x00, 0xf6, 0x2b, 0xd8, 0x3c, 0x8a, 0x22, 0xf9, 0xe1, 0x99, 0x1c, 0x7e, 0x3c, 0x96, 0x51,
+	0x14, 0x62, 0xa8, 0x12, 0xa2, 0xc1, 0x4a, 0x50, 0xa6, 0xc2, 0x19, 0xb8, 0xf8, 0x2d, 0xc7, 0x2f,
+	0xa5, 0x38, 0x40, 0x93, 0x0e, 0x50, 0xa6, 0x62, 0x5f, 0x51, 0x86, 0xc0, 0x53, 0x1d, 0xbd, 0xd5,
+	0x42, 0x21, 0x96, 0x10, 0x47, 0xea, 0xc1, 0xbc, 0x02, 0x3d, 0xe8, 0x7a, 0x94, 0x34, 0x58, 0xd6,
+	0x25, 0x44, 0x9c, 0x7a, 0xb0, 0x40, 0x8b, 0x34, 0xa4, 0x2d, 0xc3, 0x78, 0x64, 0x43, 0x6a, 0xe8,
+	0x34, 0x83, 0xa2, 0xdd, 0x1b, 0xc5, 0x27, 0xa7, 0x52, 0x85, 0x3f, 0xc8, 0x58, 0xf3, 0xc8, 0x6b,
+	0xd3, 0x65, 0x67, 0x50, 0xfc, 0xae, 0x10, 0x79, 0x2d, 0x94, 0x0e, 0x07, 0x3c, 0x22, 0x66, 0xad,
+	0x04, 0x53, 0x18, 0x3b, 0x84, 0xad, 0xab, 0x1b, 0xa9, 0xf4, 0x71, 0xa8, 0x06, 0x69, 0x48, 0xb9,
+	0xe8, 0xe2, 0x56, 0x28, 0x6f, 0x9d, 0x6c, 0x4b, 0x75, 0xe8, 0x3f, 0xf3, 0xfa, 0x62, 0xac, 0x2e,
+	0x23, 0x3e, 0x10, 0x63, 0x11, 0x6b, 0xaf, 0x43, 0xd1, 0x2e, 0x53, 0xe1, 0x8c, 0x73, 0x7e, 0x97,
+	0x87, 0xf6, 0xd2, 0x78, 0xca, 0xdb, 0x30, 0x1e, 0x2f, 0x51, 0xe5, 0x31, 0x7f, 0x13, 0x4e, 0x84,
+	0xc7, 0x9c, 0x98, 0x23, 0x80, 0xd9, 0xa0, 0x17, 0x26, 0xbc, 0x1f, 0x09, 0x9c, 0xe8, 0x6d, 0x92,
+	0xde, 0x85, 0x90, 0x63, 0x86, 0xa7, 0x83, 0x54, 0x29, 0x11, 0x6b, 0xe3, 0xff, 0x2d, 0xc3, 0xb1,
+	0x79, 0x0d, 0xfa, 0xca, 0x1c, 0xfc, 0x99, 0x54, 0x43, 0xa1, 0xbc, 0x6d, 0x93, 0x83, 0x5c, 0xac,
+	0xb8, 0x77, 0x8f, 0x8f, 0xf9, 0x48, 0x64, 0xb7, 0xd8, 0x31, 0xb7, 0x28, 0x51, 0x21, 0x13, 0x9c,
+	0x43, 0x05, 0x62, 0x92, 0x3b, 0xeb, 0x33, 0x3a, 0xf2, 0x02, 0x2d, 0xde, 0xef, 0x3c, 0x8c, 0xc3,
+	0x71, 0x3a, 0xa6, 0xfb, 0x79, 0x26, 0xdb, 0x39, 0x10, 0x32, 0x32, 0x63, 0x45, 0x2f, 0x54, 0x62,
+	0x80, 0x7c, 0xf5, 0x1e, 0x50, 0x04, 0xe6, 0x15, 0xec, 0xa7, 0xd0, 0xb6, 0x34, 0x3d, 0x13, 0xf1,
+	0x48, 0xdf, 0x78, 0x5d, 0x5a, 0x71, 0x1a, 0x44, 0x5e, 0x99, 0x4b, 0x20, 0xcf, 0x02, 0xae, 0x85,
+	0xf7, 0x13, 0xc3, 0xbf, 0x69, 0x14, 0xf7, 0xee, 0x89, 0x7e, 0x3a, 0x42, 0xcf, 0xd9, 0x87, 0x2a,
+	0xf1, 0x1e, 0xd2, 0x85, 0xe6, 0x15, 0xb8, 0xea, 0x39, 0xbf, 0xa3, 0x64, 0x6e, 0x37, 0x7f, 0x64,
+	0x56, 0x9d, 0x46, 0xf3, 0xfc, 0xbd, 0xeb, 0xe4, 0xef, 0xd9, 0x97, 0xe1, 0x71, 0xc9, 0xcb, 0x80,
+	0x9e, 0x70, 0xe4, 0x17, 0x8a, 0x0f, 0x84, 0xb7, 0x67, 0xbe, 0xcd, 0x39, 0x85, 0xff, 0xb7, 0x8a,
+	0x53, 0x85, 0xe1, 0x9e, 0x14, 0x42, 0x53, 0x07, 0xd3, 0x98, 0x3d, 0xb2, 0xe5, 0x6e, 0x75, 0x36,
+	0x57, 0x13, 0xcc, 0x3e, 0xcf, 0x2b, 0xdf, 0x5a, 0x61, 0x40, 0x48, 0x5e, 0xf2, 0x7e, 0x0e, 0xcb,
+	0x27, 0xb7, 0x22, 0xd6, 0x59, 0x71, 0x4c, 0x26, 0x84, 0x04, 0x56, 0xe1, 0x96, 0xb6, 0xf5, 0x45,
+	0xa5, 0xad, 0x1f, 0x41, 0x9d, 0xcc, 0xe9, 0x98, 0x1f, 0x27, 0x79, 0xea, 0xc7, 0x31, 0x56, 0x02,
+	0xb4, 0xdd, 0xcb, 0x9e, 0x7d, 0x7b, 0x33, 0x11, 0xfb, 0x2d, 0x5a, 0xc8, 0xb6, 0x8c, 0xce, 0xca,
+	0x06, 0xa7, 0x9a, 0x0b, 0x8b, 0xa0, 0xec, 0x91, 0x27, 0xc1, 0x7f, 0x62, 0xa7, 0xb1, 0x55, 0xa8,
+	0x7c, 0x6b, 0x3d, 0x52, 0xf9, 0x16, 0xa5, 0xef, 0xec, 0xd3, 0x52, 0xf9, 0xce, 0xff, 0xb1, 0x0a,
+	0x75, 0xda, 0x67, 0xae, 0x14, 0xc8, 0x9e, 0xa7, 0xea, 0x7c, 0xbd, 0x51, 0x2b, 0xea, 0x8d, 0x47,
+	0xb0, 0x84, 0xc9, 0xd8, 0x75, 0x8c, 0x75, 0x2e, 0xc2, 0xa6, 0x42, 0xa0, 0xcc, 0x57, 0xcf, 0x2a,
+	0x04, 0x94, 0xf0, 0x4a, 0x3d, 0xc1, 0xf5, 0x8d, 0xdb, 0x42, 0x12, 0x10, 0x18, 0xdc, 0x94, 0x91,
+	0x91, 0x54, 0x5e, 0xc3, 0x5e, 0x09, 0x05, 0xfc, 0x60, 0xcb, 0xde, 0x31, 0xd3, 0x22, 0x94, 0xa9,
+	0x8a, 0xfa, 0xa7, 0xe9, 0xd4, 0x3f, 0x48, 0xc3, 0xa9, 0xf7, 0x13, 0x4c, 0x22, 0x75, 0x31, 0xac,
+	0x03, 0x9c, 0x2e, 0xbf, 0x45, 0xd3, 0x1d, 0x04, 0xaf, 0xf6, 0x9c, 0x0f, 0xc2, 0x78, 0x44, 0x49,
+	0xbd, 0x19, 0x58, 0xc9, 0x7f, 0x05, 0xce, 0x15, 0x28, 0x2a, 0x15, 0x27, 0x2a, 0x39, 0x43, 0xab,
+	0x0e, 0x43, 0x7d, 0x58, 0xcd, 0x9f, 0xee, 0xe1, 0xb3, 0x8f, 0xd6, 0xbf, 0x53, 0xd8, 0xe1, 0x5f,
+	0xea, 0x00, 0xc7, 0xf9, 0x7f, 0x27, 0xec, 0x4b, 0xa8, 0x5d, 0xca, 0x09, 0x5b, 0x33, 0x0e, 0xcf,
+	0x5a, 0xe3, 0xee, 0x7a, 0x2e, 0xdb, 0xfa, 0xf2, 0x69, 0x56, 0xd0, 0xb1, 0x0d, 0xe2, 0xb5, 0xdb,
+	0xe6, 0x76, 0x99, 0x0b, 0xd9, 0x09, 0x5f, 0x41, 0x9d, 0xd2, 0x0d, 0xeb, 0x58, 0x65, 0xde, 0x98,
+	0x76, 0x37, 0x1c, 0xa4, 0x58, 0xde, 0xf4, 0x52, 0x66, 0xf9, 0xa9, 0xae, 0xb4, 0xcb, 0x5c, 0xc8,
+	0x4e, 0x38, 0x82, 0x55, 0xb7, 0x0d, 0x62, 0xf4, 0xff, 0x47, 0x49, 0xb3, 0xd5, 0xf5, 0xe6, 0x15,
+	0x76, 0x89, 0x17, 0xb0, 0x36, 0xdd, 0xa2, 0xb0, 0x07, 0x68, 0x5b, 0xda, 0x27, 0x75, 0xbb, 0x65,
+	0x2a, 0xbb, 0xd0, 0x21, 0x34, 0x6c, 0xcb, 0xc1, 0xe8, 0xa8, 0xd3, 0x1d, 0x4a, 0x77, 0x73, 0x0a,
+	0xb3, 0x73, 0x7e, 0x07, 0xcd, 0xbc, 0xdf, 0x60, 0x5b, 0xe4, 0xed, 0x99, 0x16, 0xa5, 0xbb, 0x3d,
+	0x83, 0xda, 0x99, 0x7f, 0x04, 0x28, 0xfa, 0x0d, 0x46, 0x46, 0x73, 0x4d, 0x4a, 0x77, 0x67, 0x16,
+	0x2e, 0xb6, 0xcd, 0x5b, 0x0b, 0xb3, 0xed, 0x6c, 0x97, 0xd2, 0xdd, 0x9e, 0x41, 0xed, 0xcc, 0x9f,
+	0xc3, 0x12, 0x36, 0x1c, 0xcc, 0x30, 0xa3, 0xe8, 0x44, 0xba, 0x9d, 0x02, 0xb0, 0xa6, 0x3d, 0x68,
+	0x4f, 0xfd, 0x57, 0xc6, 0x28, 0x06, 0x65, 0xff, 0xb4, 0x75, 0x1f, 0x94, 0x68, 0xcc, 0x2a, 0xcf,
+	0x3a, 0xff, 0xfd, 0xf7, 0x6e, 0xe5, 0xaf, 0x9f, 0x76, 0x2b, 0xff, 0xf8, 0xb4, 0x5b, 0xf9, 0xbe,
+	0x3a, 0xe9, 0xf7, 0x97, 0xe9, 0x5f, 0xbb, 0xaf, 0xff, 0x37, 0x00, 0x63, 0xb4, 0x2c, 0x16, 0xfc,
+	0x13, 0x00, 0x00,
 }
@@ -1,6 +1,7 @@
 package pb
 
-// AliveSnakes returns all the alive snakes
+// AliveSnakes returns all the alive snakes, in the order they appear in
+// Snakes. Rules rely on this order to apply updates in a fixed sequence.
 func (gt *GameFrame) AliveSnakes() []*Snake {
 	snakes := []*Snake{}
 
@@ -19,6 +19,22 @@ func TestGameFrameAliveSnakes(t *testing.T) {
 	require.Len(t, snakes, 1)
 }
 
+func TestGameFrameAliveSnakesOrder(t *testing.T) {
+	gt := &GameFrame{
+		Snakes: []*Snake{
+			&Snake{ID: "1"},
+			&Snake{ID: "2", Death: &Death{}},
+			&Snake{ID: "3"},
+			&Snake{ID: "4"},
+		},
+	}
+	snakes := gt.AliveSnakes()
+	require.Len(t, snakes, 3)
+	require.Equal(t, "1", snakes[0].ID)
+	require.Equal(t, "3", snakes[1].ID)
+	require.Equal(t, "4", snakes[2].ID)
+}
+
 func TestGameFrameDeadSnakes(t *testing.T) {
 	gt := &GameFrame{
 		Snakes: []*Snake{
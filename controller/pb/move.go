@@ -0,0 +1,22 @@
+package pb
+
+// Move is a direction a snake moves its head in. The origin of the board is
+// its top left corner, so MoveUp decreases Y and MoveDown increases it.
+type Move string
+
+// The moves a snake can make.
+const (
+	MoveUp    Move = "up"
+	MoveDown  Move = "down"
+	MoveLeft  Move = "left"
+	MoveRight Move = "right"
+)
+
+// Valid reports whether m is one of the moves a snake can make.
+func (m Move) Valid() bool {
+	switch m {
+	case MoveUp, MoveDown, MoveLeft, MoveRight:
+		return true
+	}
+	return false
+}
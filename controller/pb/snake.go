@@ -1,5 +1,7 @@
 package pb
 
+import "fmt"
+
 // Move the snake 1 space in the specified direction, move does not remove the end point of the snake, that will be done
 // after snakes have eaten
 func (s *Snake) Move(direction string) {
@@ -7,20 +9,20 @@ func (s *Snake) Move(direction string) {
 	if h == nil {
 		return
 	}
-	switch direction {
-	case "up":
+	switch Move(direction) {
+	case MoveUp:
 		s.Body = append([]*Point{
 			{X: h.X, Y: h.Y - 1},
 		}, s.Body...)
-	case "down":
+	case MoveDown:
 		s.Body = append([]*Point{
 			{X: h.X, Y: h.Y + 1},
 		}, s.Body...)
-	case "left":
+	case MoveLeft:
 		s.Body = append([]*Point{
 			{X: h.X - 1, Y: h.Y},
 		}, s.Body...)
-	case "right":
+	case MoveRight:
 		s.Body = append([]*Point{
 			{X: h.X + 1, Y: h.Y},
 		}, s.Body...)
@@ -29,31 +31,44 @@ func (s *Snake) Move(direction string) {
 	}
 }
 
-// DefaultMove the snake will move 1 space in the direction it was already heading
+// DefaultMove the snake will move 1 space in the direction it was already heading, see
+// NeckDirection. A snake with no direction yet moves the way it is Facing, or up when it was
+// placed without one.
 func (s *Snake) DefaultMove() {
+	direction := s.NeckDirection()
+	if direction == "" && Move(s.Facing).Valid() {
+		direction = s.Facing
+	}
+	if direction == "" {
+		direction = string(MoveUp)
+	}
+	s.Move(direction)
+}
+
+// NeckDirection returns the direction the snake is facing: the move that took its neck, the
+// second body segment, to its head. A freshly spawned snake that is still stacked on one square
+// has no direction and returns "". Head and neck more than one square apart on a line are taken to
+// have crossed a wrapping edge, so a head on the left edge with its neck on the right edge faces
+// right.
+func (s *Snake) NeckDirection() string {
 	if len(s.Body) < 2 {
-		s.Move("up")
-		return
+		return ""
 	}
 	head := s.Head()
 	neck := s.Body[1]
-
-	if head.X == neck.X && head.Y == neck.Y {
-		// this is the case when the game starts up and all 3 segments are still on the same point
-		s.Move("up")
-	} else if head.X == neck.X {
-		if head.Y > neck.Y {
-			s.Move("down")
-		} else {
-			s.Move("up")
-		}
-	} else if head.Y == neck.Y {
-		if head.X > neck.X {
-			s.Move("right")
-		} else {
-			s.Move("left")
-		}
+	dx := head.X - neck.X
+	dy := head.Y - neck.Y
+	switch {
+	case dy == 0 && (dx == 1 || dx < -1):
+		return string(MoveRight)
+	case dy == 0 && (dx == -1 || dx > 1):
+		return string(MoveLeft)
+	case dx == 0 && (dy == 1 || dy < -1):
+		return string(MoveDown)
+	case dx == 0 && (dy == -1 || dy > 1):
+		return string(MoveUp)
 	}
+	return ""
 }
 
 // Head returns the first point in the body
@@ -63,3 +78,41 @@ func (s *Snake) Head() *Point {
 	}
 	return s.Body[0]
 }
+
+// Validate checks that the snake can be on a board of width by height: it has a body, every
+// segment is on the board and every segment is on the square of the one before it or next to
+// it. On a board that wraps horizontally or vertically a segment on the opposite left or right,
+// or top or bottom edge of the one before it is next to it too.
+func (s *Snake) Validate(width, height int32, wrapHorizontal, wrapVertical bool) error {
+	if len(s.Body) == 0 {
+		return fmt.Errorf("snake %s has no body", s.ID)
+	}
+	for i, p := range s.Body {
+		if p.X < 0 || p.X >= width || p.Y < 0 || p.Y >= height {
+			return fmt.Errorf("snake %s segment %d at (%d,%d) is off the %dx%d board", s.ID, i, p.X, p.Y, width, height)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := s.Body[i-1]
+		dx := step(prev.X, p.X, width, wrapHorizontal)
+		dy := step(prev.Y, p.Y, height, wrapVertical)
+		if dx+dy > 1 {
+			return fmt.Errorf("snake %s segment %d at (%d,%d) is not next to (%d,%d)", s.ID, i, p.X, p.Y, prev.X, prev.Y)
+		}
+	}
+	return nil
+}
+
+// step returns the distance between a and b along one side of the board, going over the edge
+// when the board wraps.
+func step(a, b, size int32, wrapped bool) int32 {
+	d := a - b
+	if d < 0 {
+		d = -d
+	}
+	if wrapped && size-d < d {
+		d = size - d
+	}
+	return d
+}
@@ -94,3 +94,92 @@ func TestSnake_DefaultMove(t *testing.T) {
 		require.Equal(t, test.Expected, s.Head())
 	}
 }
+
+func TestSnake_NeckDirection(t *testing.T) {
+	tests := []struct {
+		Body     []*Point
+		Expected string
+	}{
+		{Body: []*Point{{X: 5, Y: 4}, {X: 5, Y: 5}, {X: 5, Y: 6}}, Expected: "up"},
+		{Body: []*Point{{X: 5, Y: 6}, {X: 5, Y: 5}, {X: 5, Y: 4}}, Expected: "down"},
+		{Body: []*Point{{X: 4, Y: 5}, {X: 5, Y: 5}, {X: 6, Y: 5}}, Expected: "left"},
+		{Body: []*Point{{X: 6, Y: 5}, {X: 5, Y: 5}, {X: 4, Y: 5}}, Expected: "right"},
+		// Just spawned, or too short to tell.
+		{Body: []*Point{{X: 5, Y: 5}, {X: 5, Y: 5}, {X: 5, Y: 5}}, Expected: ""},
+		{Body: []*Point{{X: 5, Y: 5}}, Expected: ""},
+		{Expected: ""},
+		// Wrapped over an edge of a 10x10 board.
+		{Body: []*Point{{X: 0, Y: 5}, {X: 9, Y: 5}}, Expected: "right"},
+		{Body: []*Point{{X: 9, Y: 5}, {X: 0, Y: 5}}, Expected: "left"},
+		{Body: []*Point{{X: 5, Y: 0}, {X: 5, Y: 9}}, Expected: "down"},
+		{Body: []*Point{{X: 5, Y: 9}, {X: 5, Y: 0}}, Expected: "up"},
+	}
+
+	for _, test := range tests {
+		s := &Snake{Body: test.Body}
+		require.Equal(t, test.Expected, s.NeckDirection(), "Body: %v", test.Body)
+	}
+}
+
+func TestSnake_DefaultMoveWrapped(t *testing.T) {
+	s := &Snake{Body: []*Point{{X: 0, Y: 5}, {X: 9, Y: 5}}}
+	s.DefaultMove()
+	require.Equal(t, &Point{X: 1, Y: 5}, s.Head())
+}
+
+func TestSnake_DefaultMoveFacing(t *testing.T) {
+	s := &Snake{Body: []*Point{{X: 0, Y: 0}, {X: 0, Y: 0}}, Facing: "right"}
+	s.DefaultMove()
+	require.Equal(t, &Point{X: 1, Y: 0}, s.Head())
+
+	// Once the snake has a neck it keeps its heading.
+	s.Move("down")
+	s.DefaultMove()
+	require.Equal(t, &Point{X: 1, Y: 2}, s.Head())
+
+	// A facing that is not a move is ignored.
+	s = &Snake{Body: []*Point{{X: 5, Y: 5}, {X: 5, Y: 5}}, Facing: "sideways"}
+	s.DefaultMove()
+	require.Equal(t, &Point{X: 5, Y: 4}, s.Head())
+}
+
+func TestSnake_Validate(t *testing.T) {
+	snake := func(body ...*Point) *Snake {
+		return &Snake{ID: "s", Body: body}
+	}
+	valid := []*Snake{
+		snake(&Point{X: 1, Y: 1}),
+		snake(&Point{X: 1, Y: 1}, &Point{X: 1, Y: 1}, &Point{X: 1, Y: 1}),
+		snake(&Point{X: 1, Y: 1}, &Point{X: 2, Y: 1}, &Point{X: 2, Y: 2}, &Point{X: 2, Y: 2}),
+	}
+	for _, s := range valid {
+		require.NoError(t, s.Validate(5, 5, false, false), "%v", s.Body)
+	}
+
+	invalid := map[string]*Snake{
+		"snake s has no body":                              snake(),
+		"snake s segment 1 at (5,1) is off the 5x5 board":  snake(&Point{X: 4, Y: 1}, &Point{X: 5, Y: 1}),
+		"snake s segment 0 at (-1,0) is off the 5x5 board": snake(&Point{X: -1, Y: 0}),
+		"snake s segment 1 at (2,2) is not next to (1,1)":  snake(&Point{X: 1, Y: 1}, &Point{X: 2, Y: 2}),
+		"snake s segment 2 at (3,1) is not next to (1,1)":  snake(&Point{X: 1, Y: 2}, &Point{X: 1, Y: 1}, &Point{X: 3, Y: 1}),
+		"snake s segment 1 at (4,1) is not next to (0,1)":  snake(&Point{X: 0, Y: 1}, &Point{X: 4, Y: 1}),
+	}
+	for msg, s := range invalid {
+		err := s.Validate(5, 5, false, false)
+		require.Error(t, err)
+		require.Equal(t, msg, err.Error())
+	}
+
+	// Wrapping only joins opposite edges.
+	require.NoError(t, snake(&Point{X: 0, Y: 1}, &Point{X: 4, Y: 1}).Validate(5, 5, true, true))
+	require.Error(t, snake(&Point{X: 0, Y: 1}, &Point{X: 3, Y: 1}).Validate(5, 5, true, true))
+	require.Error(t, snake(&Point{X: 0, Y: 0}, &Point{X: 4, Y: 4}).Validate(5, 5, true, true))
+
+	// And only along the axes that wrap.
+	across := snake(&Point{X: 0, Y: 1}, &Point{X: 4, Y: 1})
+	require.NoError(t, across.Validate(5, 5, true, false))
+	require.Error(t, across.Validate(5, 5, false, true))
+	down := snake(&Point{X: 1, Y: 0}, &Point{X: 1, Y: 4})
+	require.NoError(t, down.Validate(5, 5, false, true))
+	require.Error(t, down.Validate(5, 5, true, false))
+}
@@ -0,0 +1,228 @@
+// Package recording provides a controller.Store that records the calls made to
+// it, so tests can assert how a store is used.
+package recording
+
+import (
+	"context"
+	"sync"
+
+	"github.com/battlesnakeio/engine/controller"
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/battlesnakeio/engine/rules"
+)
+
+// Call is a single recorded call. Args holds the arguments after the context,
+// in the order of the method signature, and Err the error the inner store
+// returned.
+type Call struct {
+	Method string
+	Args   []interface{}
+	Err    error
+}
+
+// Store wraps a controller.Store and records every call before passing it on.
+type Store struct {
+	inner controller.Store
+	calls []Call
+	lock  sync.Mutex
+}
+
+// NewStore returns a Store that records the calls made to it and passes them
+// on to inner.
+func NewStore(inner controller.Store) *Store {
+	return &Store{inner: inner}
+}
+
+// Calls returns the recorded calls in the order they were made.
+func (s *Store) Calls() []Call {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Call{}, s.calls...)
+}
+
+// Methods returns the names of the methods called, in order.
+func (s *Store) Methods() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	methods := make([]string, 0, len(s.calls))
+	for _, c := range s.calls {
+		methods = append(methods, c.Method)
+	}
+	return methods
+}
+
+// Count returns how often method was called.
+func (s *Store) Count(method string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	n := 0
+	for _, c := range s.calls {
+		if c.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset forgets the recorded calls.
+func (s *Store) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.calls = nil
+}
+
+func (s *Store) record(err error, method string, args ...interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.calls = append(s.calls, Call{Method: method, Args: args, Err: err})
+}
+
+// Lock records the call and passes it on.
+func (s *Store) Lock(ctx context.Context, key, token string) (string, error) {
+	t, err := s.inner.Lock(ctx, key, token)
+	s.record(err, "Lock", key, token)
+	return t, err
+}
+
+// Unlock records the call and passes it on.
+func (s *Store) Unlock(ctx context.Context, key, token string) error {
+	err := s.inner.Unlock(ctx, key, token)
+	s.record(err, "Unlock", key, token)
+	return err
+}
+
+// ForceUnlock records the call and passes it on.
+func (s *Store) ForceUnlock(ctx context.Context, key string) error {
+	err := s.inner.ForceUnlock(ctx, key)
+	s.record(err, "ForceUnlock", key)
+	return err
+}
+
+// LockInfo records the call and passes it on.
+func (s *Store) LockInfo(ctx context.Context, key string) (*controller.LockInfo, error) {
+	info, err := s.inner.LockInfo(ctx, key)
+	s.record(err, "LockInfo", key)
+	return info, err
+}
+
+// PopGameID records the call and passes it on.
+func (s *Store) PopGameID(ctx context.Context) (string, error) {
+	id, err := s.inner.PopGameID(ctx)
+	s.record(err, "PopGameID")
+	return id, err
+}
+
+// PeekRunningGames records the call and passes it on.
+func (s *Store) PeekRunningGames(ctx context.Context, count int) ([]string, error) {
+	ids, err := s.inner.PeekRunningGames(ctx, count)
+	s.record(err, "PeekRunningGames", count)
+	return ids, err
+}
+
+// SetGameStatus records the call and passes it on.
+func (s *Store) SetGameStatus(ctx context.Context, id string, status rules.GameStatus) error {
+	err := s.inner.SetGameStatus(ctx, id, status)
+	s.record(err, "SetGameStatus", id, status)
+	return err
+}
+
+// CreateGame records the call and passes it on.
+func (s *Store) CreateGame(ctx context.Context, game *pb.Game, frames []*pb.GameFrame) error {
+	err := s.inner.CreateGame(ctx, game, frames)
+	s.record(err, "CreateGame", game, frames)
+	return err
+}
+
+// PushGameFrame records the call and passes it on.
+func (s *Store) PushGameFrame(ctx context.Context, id, token string, frame *pb.GameFrame) error {
+	err := s.inner.PushGameFrame(ctx, id, token, frame)
+	s.record(err, "PushGameFrame", id, token, frame)
+	return err
+}
+
+// PushGameFrames records the call and passes it on.
+func (s *Store) PushGameFrames(ctx context.Context, id, token string, frames []*pb.GameFrame) error {
+	err := s.inner.PushGameFrames(ctx, id, token, frames)
+	s.record(err, "PushGameFrames", id, token, frames)
+	return err
+}
+
+// ListGameFrames records the call and passes it on.
+func (s *Store) ListGameFrames(ctx context.Context, id string, limit, offset int) ([]*pb.GameFrame, error) {
+	frames, err := s.inner.ListGameFrames(ctx, id, limit, offset)
+	s.record(err, "ListGameFrames", id, limit, offset)
+	return frames, err
+}
+
+// ListGameFramesReverse records the call and passes it on.
+func (s *Store) ListGameFramesReverse(ctx context.Context, id string, limit, offset int) ([]*pb.GameFrame, error) {
+	frames, err := s.inner.ListGameFramesReverse(ctx, id, limit, offset)
+	s.record(err, "ListGameFramesReverse", id, limit, offset)
+	return frames, err
+}
+
+// ListGameFramesSince records the call and passes it on.
+func (s *Store) ListGameFramesSince(ctx context.Context, id string, afterTurn int) ([]*pb.GameFrame, error) {
+	frames, err := s.inner.ListGameFramesSince(ctx, id, afterTurn)
+	s.record(err, "ListGameFramesSince", id, afterTurn)
+	return frames, err
+}
+
+// RewindGame records the call and passes it on.
+func (s *Store) RewindGame(ctx context.Context, id string, toTurn int) error {
+	err := s.inner.RewindGame(ctx, id, toTurn)
+	s.record(err, "RewindGame", id, toTurn)
+	return err
+}
+
+// CompactGame records the call and passes it on.
+func (s *Store) CompactGame(ctx context.Context, id string) error {
+	err := s.inner.CompactGame(ctx, id)
+	s.record(err, "CompactGame", id)
+	return err
+}
+
+// UpdateGame records the call and passes it on.
+func (s *Store) UpdateGame(ctx context.Context, game *pb.Game) error {
+	err := s.inner.UpdateGame(ctx, game)
+	s.record(err, "UpdateGame", game)
+	return err
+}
+
+// GetGame records the call and passes it on.
+func (s *Store) GetGame(ctx context.Context, id string) (*pb.Game, error) {
+	game, err := s.inner.GetGame(ctx, id)
+	s.record(err, "GetGame", id)
+	return game, err
+}
+
+// GetGameStatus records the call and passes it on.
+func (s *Store) GetGameStatus(ctx context.Context, id string) (string, error) {
+	status, err := s.inner.GetGameStatus(ctx, id)
+	s.record(err, "GetGameStatus", id)
+	return status, err
+}
+
+// GetGameAndLastFrame records the call and passes it on.
+func (s *Store) GetGameAndLastFrame(ctx context.Context, id string) (*pb.Game, *pb.GameFrame, error) {
+	game, frame, err := s.inner.GetGameAndLastFrame(ctx, id)
+	s.record(err, "GetGameAndLastFrame", id)
+	return game, frame, err
+}
+
+// WithTx records the call and passes it on. The writes of the transaction
+// are recorded as its arguments, as controller.TxOp values.
+func (s *Store) WithTx(ctx context.Context, fn func(tx *controller.StoreTx) error) error {
+	var ops []controller.TxOp
+	err := s.inner.WithTx(ctx, func(tx *controller.StoreTx) error {
+		err := fn(tx)
+		ops = tx.Ops()
+		return err
+	})
+	args := make([]interface{}, 0, len(ops))
+	for _, op := range ops {
+		args = append(args, op)
+	}
+	s.record(err, "WithTx", args...)
+	return err
+}
@@ -0,0 +1,63 @@
+package recording
+
+import (
+	"context"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller"
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/battlesnakeio/engine/rules"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreRecordsCalls(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(controller.InMemStore())
+
+	game := &pb.Game{ID: "test", Status: string(rules.GameStatusRunning)}
+	require.Nil(t, s.CreateGame(ctx, game, []*pb.GameFrame{{Turn: 0}}))
+	for turn := int32(1); turn <= 3; turn++ {
+		require.Nil(t, s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: turn}))
+	}
+	_, err := s.GetGame(ctx, "missing")
+	require.NotNil(t, err)
+
+	require.Equal(t, []string{"CreateGame", "PushGameFrame", "PushGameFrame", "PushGameFrame", "GetGame"}, s.Methods())
+	require.Equal(t, 1, s.Count("CreateGame"))
+	require.Equal(t, 3, s.Count("PushGameFrame"))
+
+	calls := s.Calls()
+	require.Equal(t, []interface{}{"test", "", &pb.GameFrame{Turn: 2}}, calls[2].Args)
+	require.Nil(t, calls[2].Err)
+	require.Equal(t, controller.ErrNotFound, calls[4].Err)
+
+	frames, err := s.ListGameFrames(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Len(t, frames, 4)
+
+	s.Reset()
+	require.Empty(t, s.Calls())
+}
+
+func TestStoreRecordsTx(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(controller.InMemStore())
+	game := &pb.Game{ID: "test", Status: string(rules.GameStatusRunning)}
+	require.Nil(t, s.CreateGame(ctx, game, []*pb.GameFrame{{Turn: 0}}))
+	s.Reset()
+
+	err := s.WithTx(ctx, func(tx *controller.StoreTx) error {
+		tx.PushGameFrame("test", "", &pb.GameFrame{Turn: 1})
+		tx.SetGameStatus("test", rules.GameStatusComplete)
+		return nil
+	})
+	require.Nil(t, err)
+
+	calls := s.Calls()
+	require.Len(t, calls, 1)
+	require.Equal(t, "WithTx", calls[0].Method)
+	require.Equal(t, []interface{}{
+		controller.TxOp{GameID: "test", Frame: &pb.GameFrame{Turn: 1}},
+		controller.TxOp{GameID: "test", Status: rules.GameStatusComplete},
+	}, calls[0].Args)
+}
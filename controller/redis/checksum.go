@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCorruptFrame is returned when a frame read from redis does not match the
+// checksum it was stored with.
+var ErrCorruptFrame = status.Error(codes.DataLoss, "redis: frame does not match its checksum")
+
+// Frames are stored as a marker byte, the CRC32 of the encoded frame and the
+// encoded frame. Field number 0 is not valid in protobuf, so a frame stored
+// before checksums were added never starts with the marker.
+const (
+	checksumMarker    = 0x00
+	checksumHeaderLen = 5
+)
+
+// marshalFrame encodes a frame with its checksum.
+func marshalFrame(f *pb.GameFrame) ([]byte, error) {
+	data, err := proto.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return withChecksum(data), nil
+}
+
+func withChecksum(data []byte) []byte {
+	stored := make([]byte, checksumHeaderLen, checksumHeaderLen+len(data))
+	stored[0] = checksumMarker
+	binary.BigEndian.PutUint32(stored[1:checksumHeaderLen], crc32.ChecksumIEEE(data))
+	return append(stored, data...)
+}
+
+// stripChecksum returns the encoded frame of stored data, checking it
+// against its checksum when verify is set. Frames stored without a checksum
+// are returned as they are.
+func stripChecksum(stored []byte, verify bool) ([]byte, error) {
+	if len(stored) < checksumHeaderLen || stored[0] != checksumMarker {
+		return stored, nil
+	}
+	data := stored[checksumHeaderLen:]
+	if verify && binary.BigEndian.Uint32(stored[1:checksumHeaderLen]) != crc32.ChecksumIEEE(data) {
+		return nil, ErrCorruptFrame
+	}
+	return data, nil
+}
+
+// unmarshalFrame decodes stored frame data into f.
+func (rs *Store) unmarshalFrame(stored []byte, f *pb.GameFrame) error {
+	data, err := stripChecksum(stored, rs.verifyChecksums)
+	if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal(data, f); err != nil {
+		return errors.Wrap(err, "unable to unmarshal frame")
+	}
+	return nil
+}
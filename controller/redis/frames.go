@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis"
+)
+
+// Frames are stored in a list by default, at the index matching their turn.
+// With WithFramesByTurn they are stored in a hash instead, with the turn as
+// field, so a single frame is read with one HGET however long the game is.
+// Games are numbered from turn 0 without gaps in both modes, so the number of
+// frames is the length of the list or hash and the last turn is one less.
+
+// WithFramesByTurn stores frames in a hash keyed by turn instead of a list.
+// A store only reads frames stored in the mode it is configured with, so the
+// mode must not be changed while the store holds games.
+func WithFramesByTurn() Option {
+	return func(o *options) {
+		o.framesByTurn = true
+	}
+}
+
+// frameKey returns the key the frames of a game are stored at.
+func (rs *Store) frameKey(id string) string {
+	if rs.framesByTurn {
+		return framesByTurnKey(id)
+	}
+	return framesKey(id)
+}
+
+// frameCount returns the number of frames of a game.
+func (rs *Store) frameCount(cmd redis.Cmdable, id string) (int64, error) {
+	if rs.framesByTurn {
+		return cmd.HLen(framesByTurnKey(id)).Result()
+	}
+	return cmd.LLen(framesKey(id)).Result()
+}
+
+// frameAt returns the stored frame at index i, redis.Nil is returned when
+// there is none.
+func (rs *Store) frameAt(cmd redis.Cmdable, id string, i int64) ([]byte, error) {
+	if rs.framesByTurn {
+		return cmd.HGet(framesByTurnKey(id), strconv.FormatInt(i, 10)).Bytes()
+	}
+	return cmd.LIndex(framesKey(id), i).Bytes()
+}
+
+// frameRange returns the stored frames from index start to end, both
+// included. Negative indexes count from the last frame, as in LRANGE.
+func (rs *Store) frameRange(cmd redis.Cmdable, id string, start, end int64) ([]string, error) {
+	if !rs.framesByTurn {
+		return cmd.LRange(framesKey(id), start, end).Result()
+	}
+	r, err := frameRangeByTurnCmd.Run(cmd, []string{framesByTurnKey(id)}, start, end).Result()
+	if err != nil {
+		return nil, err
+	}
+	return frameRangeData(r), nil
+}
+
+// lastFrame queues a read of the last frame on pipe. The returned function
+// gives the frame once the pipeline is executed, or redis.Nil when the game
+// has no frames.
+func (rs *Store) lastFrame(pipe redis.Pipeliner, id string) func() ([]byte, error) {
+	if !rs.framesByTurn {
+		return pipe.LIndex(framesKey(id), -1).Bytes
+	}
+	cmd := pipe.Eval(frameRangeByTurnScript, []string{framesByTurnKey(id)}, -1, -1)
+	return func() ([]byte, error) {
+		r, err := cmd.Result()
+		if err != nil {
+			return nil, err
+		}
+		data := frameRangeData(r)
+		if len(data) == 0 {
+			return nil, redis.Nil
+		}
+		return []byte(data[0]), nil
+	}
+}
+
+// appendFrames queues writing frames from index first on pipe.
+func (rs *Store) appendFrames(pipe redis.Pipeliner, id string, first int64, frames []interface{}) {
+	if !rs.framesByTurn {
+		pipe.RPush(framesKey(id), frames...)
+		return
+	}
+	fields := make(map[string]interface{}, len(frames))
+	for i, f := range frames {
+		fields[strconv.FormatInt(first+int64(i), 10)] = f
+	}
+	pipe.HMSet(framesByTurnKey(id), fields)
+}
+
+func frameRangeData(r interface{}) []string {
+	values, _ := r.([]interface{})
+	data := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			data = append(data, s)
+		}
+	}
+	return data
+}
+
+// frameRangeByTurnScript returns the frames from ARGV[1] to ARGV[2] of a hash
+// keyed by turn, with the index semantics of LRANGE. The fields are fetched in
+// batches, so a long range does not run into the limit on arguments to unpack.
+// The script never computes a negative number, turns are counted from 1 in
+// the loop: the Lua interpreter of the tests fails pointer checks under -race
+// on numbers it has not preallocated, such as -1.
+const frameRangeByTurnScript = `
+	local n = redis.call("HLEN", KEYS[1])
+	if n == 0 then return {} end
+	local first = tonumber(ARGV[1])
+	local last = tonumber(ARGV[2])
+	if first < 0 then
+		if -first > n then first = 0 else first = n + first end
+	end
+	if last < 0 then
+		if -last > n then return {} end
+		last = n + last
+	end
+	if last >= n then last = n - 1 end
+	local frames = {}
+	local fields = {}
+	for turn = first + 1, last + 1 do
+		fields[#fields + 1] = turn - 1
+		if #fields == 1000 or turn == last + 1 then
+			local values = redis.call("HMGET", KEYS[1], unpack(fields))
+			for i = 1, #fields do
+				if values[i] then
+					frames[#frames + 1] = values[i]
+				end
+			end
+			fields = {}
+		end
+	end
+	return frames
+`
+
+var frameRangeByTurnCmd = redis.NewScript(frameRangeByTurnScript)
+
+// generates the redis key for game frames stored by turn
+func framesByTurnKey(gameID string) string {
+	return fmt.Sprintf("game:%s:framesByTurn", gameID)
+}
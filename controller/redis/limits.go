@@ -0,0 +1,35 @@
+package redis
+
+import (
+	"github.com/battlesnakeio/engine/controller/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrFrameTooLarge is returned when a frame pushed to the store is larger, or
+// has more snakes, than the store accepts, see WithFrameLimits.
+var ErrFrameTooLarge = status.Error(codes.ResourceExhausted, "redis: frame exceeds the configured limits")
+
+// WithFrameLimits makes PushGameFrame, PushGameFrames and the frame pushes of
+// WithTx reject frames that take more than maxBytes once marshalled, or that
+// have more than maxSnakes snakes, with ErrFrameTooLarge. This keeps a single
+// runaway game from using up the memory of redis. A limit of 0 is no limit,
+// which is the default.
+func WithFrameLimits(maxBytes, maxSnakes int) Option {
+	return func(o *options) {
+		o.maxFrameBytes = maxBytes
+		o.maxFrameSnakes = maxSnakes
+	}
+}
+
+// checkFrameLimits returns ErrFrameTooLarge when frame, stored as data, does
+// not fit the limits of the store.
+func (rs *Store) checkFrameLimits(frame *pb.GameFrame, data []byte) error {
+	if rs.maxFrameBytes > 0 && len(data) > rs.maxFrameBytes {
+		return ErrFrameTooLarge
+	}
+	if rs.maxFrameSnakes > 0 && len(frame.Snakes) > rs.maxFrameSnakes {
+		return ErrFrameTooLarge
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"context"
+	"strings"
+
+	"github.com/battlesnakeio/engine/controller"
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/go-redis/redis"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Migrate upgrades every game in the store to the current format, so data
+// written by older versions can be read without special cases. For each game
+// the hash fields kept next to the state, id, status and name, are filled in
+// from the state where they are missing, the name is added to the name index,
+// and the state and frames are re-encoded with checksums, see CompactGame.
+//
+// Games are upgraded one at a time, each in its own transaction, and a game
+// already in the current format is not written to. Migrate is therefore safe
+// to run again, for instance after it was interrupted, and while games are
+// being played. Games created while it runs are already current.
+func (rs *Store) Migrate(c context.Context) error {
+	iter := rs.client.Scan(0, gameKey("*"), statsScanCount).Iterator()
+	for iter.Next() {
+		id := strings.TrimSuffix(strings.TrimPrefix(iter.Val(), "game:"), ":state")
+		if err := rs.migrateGame(c, id); err != nil {
+			return errors.Wrapf(err, "unable to migrate game %s", id)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return errors.Wrap(err, "unexpected redis error while scanning games")
+	}
+	return nil
+}
+
+// migrateGame upgrades a single game, a game that expired since it was
+// scanned is skipped.
+func (rs *Store) migrateGame(c context.Context, id string) error {
+	gk := gameKey(id)
+	err := rs.client.Watch(func(tx *redis.Tx) error {
+		fields, err := tx.HGetAll(gk).Result()
+		if err != nil {
+			return err
+		}
+		state, ok := fields["state"]
+		if !ok {
+			return controller.ErrNotFound
+		}
+		game := &pb.Game{}
+		if err := proto.Unmarshal([]byte(state), game); err != nil {
+			return errors.Wrap(err, "unable to unmarshal game state")
+		}
+
+		missing := map[string]string{}
+		if _, ok := fields["id"]; !ok {
+			missing["id"] = id
+		}
+		if _, ok := fields["status"]; !ok && game.Status != "" {
+			missing["status"] = game.Status
+		}
+		if _, ok := fields["name"]; !ok && game.Name != "" {
+			missing["name"] = game.Name
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			for field, value := range missing {
+				pipe.HSet(gk, field, value)
+			}
+			if name, ok := missing["name"]; ok {
+				pipe.SAdd(gameNamesKey, gameNameEntry(name, id))
+			}
+			return nil
+		})
+		return err
+	}, gk)
+	if err == controller.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = rs.CompactGame(c, id)
+	if err == controller.ErrNotFound {
+		return nil
+	}
+	return err
+}
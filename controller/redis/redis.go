@@ -1,8 +1,11 @@
 package redis
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/battlesnakeio/engine/controller"
@@ -12,12 +15,22 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
 )
 
 // Store is an implementation of the controller.Store interface
 type Store struct {
-	client  *redis.Client
-	dataTTL time.Duration
+	client       *redis.Client
+	dataTTL      time.Duration
+	archiver     controller.Archiver
+	archiveTTL   time.Duration
+	popMaxBlock  time.Duration
+	popPollEvery time.Duration
+
+	verifyChecksums bool
+	framesByTurn    bool
+	maxFrameBytes   int
+	maxFrameSnakes  int
 }
 
 // DefaultDataTTL is how long data will be kept before redis evicts it
@@ -26,15 +39,93 @@ const DefaultDataTTL = time.Hour * 24 * 30
 // DefaultLockExpiry is how long locks are kept around for
 const DefaultLockExpiry = time.Minute
 
+// DefaultPopPollInterval is how often a blocking PopGameID looks for a game
+// when no interval is configured.
+const DefaultPopPollInterval = 100 * time.Millisecond
+
+type options struct {
+	client       *redis.Options
+	archiver     controller.Archiver
+	archiveTTL   time.Duration
+	popMaxBlock  time.Duration
+	popPollEvery time.Duration
+
+	verifyChecksums bool
+	framesByTurn    bool
+	maxFrameBytes   int
+	maxFrameSnakes  int
+}
+
+// Option configures the store created by NewStore.
+type Option func(*options)
+
+// WithRetries makes the client retry commands that fail on a network error,
+// such as a dropped connection, up to maxRetries times. Between attempts it
+// backs off exponentially from minBackoff up to maxBackoff, so a short blip
+// does not fail a tick write.
+//
+// The client does not look at the context passed to the store methods, so
+// retries are not cut short by a context deadline. Keep maxRetries times
+// maxBackoff, plus the client timeouts, below the deadline callers use, or
+// the call returns after the caller has given up on it. A command is retried
+// when its reply is lost. A retried PushGameFrame does not append a frame
+// twice, but may fail when redis already executed it, pushing the same frame
+// again is a no-op.
+func WithRetries(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(o *options) {
+		o.client.MaxRetries = maxRetries
+		o.client.MinRetryBackoff = minBackoff
+		o.client.MaxRetryBackoff = maxBackoff
+	}
+}
+
+// WithArchiver hands every game that is completed to archiver, together with
+// all its frames. Once a game is archived its keys expire after expireAfter,
+// or after the usual data TTL when expireAfter is 0. When archiving fails the
+// error is logged and the keys are kept, the game is still completed.
+func WithArchiver(archiver controller.Archiver, expireAfter time.Duration) Option {
+	return func(o *options) {
+		o.archiver = archiver
+		o.archiveTTL = expireAfter
+	}
+}
+
+// WithBlockingPop makes PopGameID wait up to maxBlock for a game to become
+// available, looking for one every pollEvery. The wait is cut short by the
+// deadline of the context, so a caller that is about to give up gets
+// controller.ErrNotFound in time instead of a deadline error. Without this
+// option PopGameID returns immediately.
+func WithBlockingPop(maxBlock, pollEvery time.Duration) Option {
+	return func(o *options) {
+		o.popMaxBlock = maxBlock
+		o.popPollEvery = pollEvery
+	}
+}
+
+// WithChecksumVerification checks every frame read from redis against the
+// checksum it was stored with, a frame that does not match returns
+// ErrCorruptFrame. Checksums are always stored, frames stored before they
+// were added are not checked.
+func WithChecksumVerification() Option {
+	return func(o *options) {
+		o.verifyChecksums = true
+	}
+}
+
 // NewStore will create a new instance of an underlying redis client, so it should not be re-created across "threads"
 // - connectURL see: github.com/go-redis/redis/options.go for URL specifics
+// - opts configure the client further, see WithRetries, WithBlockingPop, WithChecksumVerification, WithFramesByTurn, WithFrameLimits
 // The underlying redis client will be immediately tested for connectivity, so don't call this until you know redis can connect.
 // Returns a new instance OR an error if unable (meaning an issue connecting to your redis URL)
-func NewStore(connectURL string) (*Store, error) {
+func NewStore(connectURL string, opts ...Option) (*Store, error) {
 	o, err := redis.ParseURL(connectURL)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to parse redis URL")
 	}
+	storeOpts := &options{client: o}
+	for _, opt := range opts {
+		opt(storeOpts)
+	}
 
 	client := redis.NewClient(o)
 
@@ -44,7 +135,23 @@ func NewStore(connectURL string) (*Store, error) {
 		return nil, errors.Wrap(err, "unable to connect ")
 	}
 
-	return &Store{client: client, dataTTL: DefaultDataTTL}, nil
+	if storeOpts.popPollEvery <= 0 {
+		storeOpts.popPollEvery = DefaultPopPollInterval
+	}
+
+	return &Store{
+		client:       client,
+		dataTTL:      DefaultDataTTL,
+		archiver:     storeOpts.archiver,
+		archiveTTL:   storeOpts.archiveTTL,
+		popMaxBlock:  storeOpts.popMaxBlock,
+		popPollEvery: storeOpts.popPollEvery,
+
+		verifyChecksums: storeOpts.verifyChecksums,
+		framesByTurn:    storeOpts.framesByTurn,
+		maxFrameBytes:   storeOpts.maxFrameBytes,
+		maxFrameSnakes:  storeOpts.maxFrameSnakes,
+	}, nil
 }
 
 // Close closes the underlying redis client. see: github.com/go-redis/redis/Client.go
@@ -99,31 +206,176 @@ func (rs *Store) Unlock(ctx context.Context, key, token string) error {
 	return nil
 }
 
+// ForceUnlock deletes the lock on a game regardless of its token. Recovery
+// tooling uses this to clear the lock of a worker that died, workers must use
+// Unlock.
+func (rs *Store) ForceUnlock(ctx context.Context, key string) error {
+	n, err := rs.client.Del(gameLockKey(key)).Result()
+	if err != nil {
+		return errors.Wrap(err, "unexpected redis error during force unlock")
+	}
+	if n == 0 {
+		return controller.ErrNotFound
+	}
+
+	return nil
+}
+
+// LockInfo returns who holds the lock of a game.
+func (rs *Store) LockInfo(ctx context.Context, key string) (*controller.LockInfo, error) {
+	pipe := rs.client.Pipeline()
+	token := pipe.Get(gameLockKey(key))
+	ttl := pipe.PTTL(gameLockKey(key))
+	_, err := pipe.Exec()
+	if err == redis.Nil {
+		return nil, controller.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unexpected redis error during lock info")
+	}
+	return controller.NewLockInfo(token.Val(), time.Now().Add(ttl.Val())), nil
+}
+
 // PopGameID returns a new game that is unlocked and running. Workers call
-// this method through the controller to find games to process.
+// this method through the controller to find games to process. With a
+// blocking pop configured it keeps looking until a game is found or the wait
+// is over, see WithBlockingPop.
 func (rs *Store) PopGameID(c context.Context) (string, error) {
-	r, err := findUnlockedGameCmd.Run(rs.client, []string{}).Result()
+	deadline := time.Now().Add(rs.popWait(c))
+	for {
+		id, err := rs.popUnlockedGame()
+		if err != controller.ErrNotFound {
+			return id, err
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return "", err
+		}
+		pause := rs.popPollEvery
+		if pause > remaining {
+			pause = remaining
+		}
+		select {
+		case <-time.After(pause):
+		case <-c.Done():
+			return "", controller.ErrNotFound
+		}
+	}
+}
+
+// popWait returns how long PopGameID may block, the configured maximum or
+// less when the context expires earlier.
+func (rs *Store) popWait(c context.Context) time.Duration {
+	wait := rs.popMaxBlock
+	if deadline, ok := c.Deadline(); ok {
+		if untilDeadline := time.Until(deadline); untilDeadline < wait {
+			wait = untilDeadline
+		}
+	}
+	return wait
+}
+
+// popUnlockedGame runs a single lookup for an unlocked, running game.
+func (rs *Store) popUnlockedGame() (string, error) {
+	ids, err := rs.findUnlockedGames(1)
 	if err != nil {
 		return "", errors.Wrap(err, "unexpected redis exception while popping game")
 	}
-
-	gameID := fmt.Sprint(r)
-	if len(gameID) == 0 {
+	if len(ids) == 0 {
 		return "", controller.ErrNotFound
 	}
+	return ids[0], nil
+}
 
-	return fmt.Sprint(r), nil
+// PeekRunningGames runs the lookup of PopGameID for up to count games.
+func (rs *Store) PeekRunningGames(c context.Context, count int) ([]string, error) {
+	if count <= 0 {
+		return []string{}, nil
+	}
+	ids, err := rs.findUnlockedGames(count)
+	if err != nil {
+		return nil, errors.Wrap(err, "unexpected redis exception while peeking games")
+	}
+	return ids, nil
+}
+
+// findUnlockedGames returns up to count unlocked, running games.
+func (rs *Store) findUnlockedGames(count int) ([]string, error) {
+	r, err := findUnlockedGamesCmd.Run(rs.client, []string{}, count).Result()
+	if err != nil {
+		return nil, err
+	}
+	values, _ := r.([]interface{})
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		ids = append(ids, fmt.Sprint(v))
+	}
+	return ids, nil
 }
 
 // SetGameStatus is used to set a specific game status. This operation
-// should be atomic.
+// should be atomic, the status key is watched so the transition is checked
+// against the status that is replaced. Games that are completed are handed
+// to the archiver, if the store has one.
 func (rs *Store) SetGameStatus(c context.Context, id string, status rules.GameStatus) error {
 	key := gameKey(id)
-	err := rs.client.HSet(key, "status", string(status)).Err()
+	var previous string
+	err := rs.client.Watch(func(tx *redis.Tx) error {
+		current, err := tx.HGet(key, "status").Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		previous = current
+		if !rules.CanTransition(rules.GameStatus(current), status) {
+			return controller.ErrInvalidTransition
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.HSet(key, "status", string(status))
+			return nil
+		})
+		return err
+	}, key)
+	if err == controller.ErrInvalidTransition {
+		return err
+	}
 	if err != nil {
 		return errors.Wrap(err, "unexpected redis error when setting game status")
 	}
 
+	if rs.archiver != nil && status == rules.GameStatusComplete && previous != string(status) {
+		if err := rs.archiveGame(c, id); err != nil {
+			log.WithError(err).WithField("GameID", id).Error("unable to archive game, keeping it in redis")
+		}
+	}
+	return nil
+}
+
+// archiveGame hands the game and all its frames to the archiver and expires
+// the keys of the game once it is archived.
+func (rs *Store) archiveGame(c context.Context, id string) error {
+	game, err := rs.GetGame(c, id)
+	if err != nil {
+		return err
+	}
+	frames, err := rs.ListGameFramesSince(c, id, -1)
+	if err != nil {
+		return err
+	}
+	if err := rs.archiver.Archive(c, id, game, frames); err != nil {
+		return err
+	}
+
+	if rs.archiveTTL <= 0 {
+		return nil
+	}
+	pipe := rs.client.TxPipeline()
+	pipe.Expire(gameKey(id), rs.archiveTTL)
+	pipe.Expire(rs.frameKey(id), rs.archiveTTL)
+	_, err = pipe.Exec()
+	if err != nil {
+		return errors.Wrap(err, "unexpected redis error while expiring archived game")
+	}
 	return nil
 }
 
@@ -143,24 +395,30 @@ func (rs *Store) CreateGame(c context.Context, game *pb.Game, frames []*pb.GameF
 	pipe.HSet(gk, "state", gameBytes)
 	pipe.HSet(gk, "status", game.Status)
 	pipe.HSet(gk, "id", game.ID)
+	if game.Name != "" {
+		pipe.HSet(gk, "name", game.Name)
+		pipe.SAdd(gameNamesKey, gameNameEntry(game.Name, game.ID))
+	}
 	pipe.Expire(gk, DefaultDataTTL)
 
 	// Marshal the frames
 	if len(frames) > 0 {
-		framesKey := framesKey(game.ID)
 		var frameData []interface{}
 
 		for _, f := range frames {
 			var data []byte
-			data, err = proto.Marshal(f)
+			data, err = marshalFrame(f)
 			if err != nil {
 				return errors.Wrap(err, "unable to marshal frame")
 			}
 			frameData = append(frameData, data)
 		}
-		pipe.LPush(framesKey, frameData...)
+		// The frames are appended in order, so each is stored at the index of
+		// its turn. They used to be pushed to the head of the list, which
+		// stored a batch of more than one frame in reverse.
+		rs.appendFrames(pipe, game.ID, 0, frameData)
 		// Frames will expire the same time as the game
-		pipe.Expire(framesKey, DefaultDataTTL)
+		pipe.Expire(rs.frameKey(game.ID), DefaultDataTTL)
 	}
 
 	// Execute the entire set of operations in one big transactional pipeline
@@ -172,44 +430,172 @@ func (rs *Store) CreateGame(c context.Context, game *pb.Game, frames []*pb.GameF
 	return nil
 }
 
-// PushGameFrame will push a game frame onto the list of frames.
-func (rs *Store) PushGameFrame(c context.Context, id string, t *pb.GameFrame) error {
-	frameBytes, err := proto.Marshal(t)
+// PushGameFrame will push a game frame onto the list of frames. The last
+// frame is read to skip duplicate pushes, then a script checks the lock token,
+// that no frame was pushed in the meantime and that the frame is for the turn
+// after the last stored frame before it pushes the frame, all in one step.
+// Frames are stored at the index of their turn, a frame for any other turn
+// returns controller.ErrInvalidSequence.
+func (rs *Store) PushGameFrame(c context.Context, id, token string, t *pb.GameFrame) error {
+	frameBytes, err := marshalFrame(t)
 	if err != nil {
 		return errors.Wrap(err, "frame marshalling error")
 	}
+	if err := rs.checkFrameLimits(t, frameBytes); err != nil {
+		return err
+	}
+	// The last frame is read by its index, so a frame pushed after the length
+	// was read does not get in the way. The script catches such a push.
+	length, err := rs.frameCount(rs.client, id)
+	if err != nil {
+		return errors.Wrap(err, "unexpected redis error")
+	}
+	if length > 0 {
+		data, err := rs.frameAt(rs.client, id, length-1)
+		if err != nil {
+			return errors.Wrap(err, "unexpected redis error")
+		}
+		last := &pb.GameFrame{}
+		if err := rs.unmarshalFrame(data, last); err != nil {
+			return err
+		}
+		if dup, err := controller.DuplicateFrame(last, t); dup || err != nil {
+			return err
+		}
+	}
+
 	// Do not update expiry here, we don't want the frames kept longer than the corresponding game
-	numAdded, err := rs.client.RPush(framesKey(id), frameBytes).Result()
+	push := pushFencedFrameCmd
+	if rs.framesByTurn {
+		push = pushFencedFrameByTurnCmd
+	}
+	r, err := push.Run(rs.client, []string{gameLockKey(id), rs.frameKey(id)}, token, frameBytes, length, t.Turn).Result()
 	if err != nil {
 		return errors.Wrap(err, "unexpected redis error")
 	}
-	if numAdded != 1 {
-		return errors.Wrap(err, "unexpected redis result")
+	switch r {
+	case int64(0):
+		return fenceError(token)
+	case int64(-1):
+		return errors.New("frames changed while pushing frame")
+	case int64(2):
+		return controller.ErrInvalidSequence
 	}
+	return nil
+}
 
+// PushGameFrames checks that the batch is contiguous and pushes it with a
+// single script. The script checks the lock and that the first frame is for
+// the turn after the last stored frame, the frame count, so nothing is
+// written when another frame got in first.
+func (rs *Store) PushGameFrames(c context.Context, id, token string, frames []*pb.GameFrame) error {
+	if len(frames) == 0 {
+		return nil
+	}
+	if err := controller.CheckFrameSequence(frames[0].Turn, frames); err != nil {
+		return err
+	}
+	args := []interface{}{token, frames[0].Turn}
+	for _, f := range frames {
+		data, err := marshalFrame(f)
+		if err != nil {
+			return errors.Wrap(err, "frame marshalling error")
+		}
+		if err := rs.checkFrameLimits(f, data); err != nil {
+			return err
+		}
+		args = append(args, data)
+	}
+
+	push := pushFramesCmd
+	if rs.framesByTurn {
+		push = pushFramesByTurnCmd
+	}
+	r, err := push.Run(rs.client, []string{gameLockKey(id), rs.frameKey(id)}, args...).Result()
+	if err != nil {
+		return errors.Wrap(err, "unexpected redis error")
+	}
+	switch r {
+	case int64(0):
+		return fenceError(token)
+	case int64(-1):
+		return controller.ErrInvalidSequence
+	}
 	return nil
 }
 
+// fenceError returns the error of a write with token that the lock of the
+// game stopped, see controller.Store.PushGameFrame.
+func fenceError(token string) error {
+	if token == "" {
+		return controller.ErrIsLocked
+	}
+	return controller.ErrLockExpired
+}
+
 // ListGameFrames will list frames by an offset and limit, it supports
 // negative offset.
 func (rs *Store) ListGameFrames(c context.Context, id string, limit, offset int) ([]*pb.GameFrame, error) {
-	if limit <= 0 {
-		return nil, errors.Errorf("invalid limit %d", limit)
+	if err := controller.ValidateLimit(limit); err != nil {
+		return nil, err
 	}
 
-	// Calculate list indexes
+	// Calculate list indexes, LRANGE includes the end index. A negative
+	// offset counts from the end of the list, so the range must not run past
+	// the last frame back to the start.
 	start := int64(offset)
-	end := int64(limit + offset)
-	if offset <= 0 {
-		end--
+	end := int64(offset + limit - 1)
+	if offset < 0 && end >= 0 {
+		end = -1
 	}
 
 	// Retrieve serialized frames
-	frameData, err := rs.client.LRange(framesKey(id), start, end).Result()
+	frameData, err := rs.frameRange(rs.client, id, start, end)
 	if err != nil {
 		return nil, errors.Wrap(err, "unexpected redis error when getting frames")
 	}
 
+	return rs.unmarshalFrames(frameData)
+}
+
+// ListGameFramesReverse will list frames newest first by an offset and limit.
+// The range is taken from the end of the list, so no length lookup is needed
+// unless the offset is negative and counts from the first frame.
+func (rs *Store) ListGameFramesReverse(c context.Context, id string, limit, offset int) ([]*pb.GameFrame, error) {
+	if err := controller.ValidateLimit(limit); err != nil {
+		return nil, err
+	}
+
+	// Calculate list indexes of the oldest and newest frame to return
+	var start, end int64
+	if offset >= 0 {
+		start = -int64(offset + limit)
+		end = -int64(offset + 1)
+	} else {
+		end = -int64(offset) - 1
+		start = end - int64(limit) + 1
+		if start < 0 {
+			start = 0
+		}
+	}
+
+	frameData, err := rs.frameRange(rs.client, id, start, end)
+	if err != nil {
+		return nil, errors.Wrap(err, "unexpected redis error when getting frames")
+	}
+
+	frames, err := rs.unmarshalFrames(frameData)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames, nil
+}
+
+// unmarshalFrames deserializes each frame in a list of raw frame data.
+func (rs *Store) unmarshalFrames(frameData []string) ([]*pb.GameFrame, error) {
 	// No frames
 	if len(frameData) == 0 {
 		return nil, nil
@@ -219,7 +605,10 @@ func (rs *Store) ListGameFrames(c context.Context, id string, limit, offset int)
 	frames := make([]*pb.GameFrame, len(frameData))
 	for i, data := range frameData {
 		var f pb.GameFrame
-		err = proto.Unmarshal([]byte(data), &f)
+		err := rs.unmarshalFrame([]byte(data), &f)
+		if err == ErrCorruptFrame {
+			return nil, err
+		}
 		if err != nil {
 			return nil, errors.Wrapf(err, "unable to unmarshal frame %s", data)
 		}
@@ -229,6 +618,361 @@ func (rs *Store) ListGameFrames(c context.Context, id string, limit, offset int)
 	return frames, nil
 }
 
+// StreamGameFrames sends the frames of a game on the returned channel, oldest
+// first, reading them batch frames at a time, so exporting a long game does
+// not hold all of its frames in memory. The frame channel is closed once the
+// last frame is sent, when reading fails or when ctx is cancelled. A failure,
+// or the error of ctx, is sent on the error channel before it is closed, a
+// stream that sent every frame closes it without sending anything.
+//
+// The frames are read in separate ranges, not in one transaction, a frame
+// pushed while the game is streamed is sent if its batch was not read yet.
+func (rs *Store) StreamGameFrames(c context.Context, id string, batch int) (<-chan *pb.GameFrame, <-chan error) {
+	frames := make(chan *pb.GameFrame)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(frames)
+		if err := controller.ValidateLimit(batch); err != nil {
+			errs <- err
+			return
+		}
+		for start := int64(0); ; start += int64(batch) {
+			if err := c.Err(); err != nil {
+				errs <- err
+				return
+			}
+			frameData, err := rs.frameRange(rs.client, id, start, start+int64(batch)-1)
+			if err != nil {
+				errs <- errors.Wrap(err, "unexpected redis error when getting frames")
+				return
+			}
+			batchFrames, err := rs.unmarshalFrames(frameData)
+			if err != nil {
+				errs <- err
+				return
+			}
+			for _, f := range batchFrames {
+				select {
+				case frames <- f:
+				case <-c.Done():
+					errs <- c.Err()
+					return
+				}
+			}
+			if len(frameData) < batch {
+				return
+			}
+		}
+	}()
+	return frames, errs
+}
+
+// ListGameFramesSince will list all frames with a turn greater than
+// afterTurn. Frames are stored at the index matching their turn, so this is a
+// single range from afterTurn+1 to the last frame. A game that is caught up
+// has no frames to list, so the game is looked up first to return
+// controller.ErrNotFound for a game that does not exist.
+func (rs *Store) ListGameFramesSince(c context.Context, id string, afterTurn int) ([]*pb.GameFrame, error) {
+	exists, err := rs.client.Exists(gameKey(id)).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "unexpected redis error")
+	}
+	if exists == 0 {
+		return nil, controller.ErrNotFound
+	}
+
+	start := int64(afterTurn + 1)
+	if start < 0 {
+		start = 0
+	}
+
+	frameData, err := rs.frameRange(rs.client, id, start, -1)
+	if err != nil {
+		return nil, errors.Wrap(err, "unexpected redis error when getting frames")
+	}
+
+	return rs.unmarshalFrames(frameData)
+}
+
+// GetFrameAtTurn will fetch the frame of a single turn, with one LINDEX, or
+// one HGET when frames are stored by turn. controller.ErrNotFound is returned
+// when the game has no frame for the turn.
+func (rs *Store) GetFrameAtTurn(c context.Context, id string, turn int) (*pb.GameFrame, error) {
+	// A negative index would count from the last frame in list mode.
+	if turn < 0 {
+		return nil, controller.ErrNotFound
+	}
+	data, err := rs.frameAt(rs.client, id, int64(turn))
+	if err == redis.Nil {
+		return nil, controller.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unexpected redis error when getting frame")
+	}
+	var frame pb.GameFrame
+	err = rs.unmarshalFrame(data, &frame)
+	if err == ErrCorruptFrame {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal frame")
+	}
+	return &frame, nil
+}
+
+// RewindGame will drop every frame after toTurn so that toTurn becomes the
+// last frame. Frames are stored at the index matching their turn, so this
+// drops every index after toTurn. A complete game is set back to running.
+func (rs *Store) RewindGame(c context.Context, id string, toTurn int) error {
+	rewind := rewindGameCmd
+	if rs.framesByTurn {
+		rewind = rewindGameByTurnCmd
+	}
+	r, err := rewind.Run(rs.client, []string{gameKey(id), rs.frameKey(id)}, toTurn).Result()
+	if err != nil {
+		return errors.Wrap(err, "unexpected redis error while rewinding game")
+	}
+
+	switch r.(int64) {
+	case rewindNotFound:
+		return controller.ErrNotFound
+	case rewindInvalidTurn:
+		return controller.ErrInvalidTurn
+	}
+	return nil
+}
+
+// CompactGame re-encodes the game state and frames with the current encoding,
+// frames stored without a checksum get one. The keys are watched while the new data is written in one transaction, so
+// readers never see a partly rewritten list, and a frame pushed in the
+// meantime aborts the compaction instead of being lost.
+func (rs *Store) CompactGame(c context.Context, id string) error {
+	gk, fk := gameKey(id), rs.frameKey(id)
+	err := rs.client.Watch(func(tx *redis.Tx) error {
+		gameData, err := tx.HGet(gk, "state").Bytes()
+		if err == redis.Nil {
+			return controller.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		frameData, err := rs.frameRange(tx, id, 0, -1)
+		if err != nil {
+			return err
+		}
+		ttl, err := tx.PTTL(fk).Result()
+		if err != nil {
+			return err
+		}
+
+		gameBytes, gameChanged, err := reencode([]byte(gameData), &pb.Game{})
+		if err != nil {
+			return err
+		}
+		framesChanged := false
+		frameBytes := make([]interface{}, len(frameData))
+		for i, data := range frameData {
+			// Frames stored without a checksum get one.
+			encoded, err := stripChecksum([]byte(data), rs.verifyChecksums)
+			if err != nil {
+				return err
+			}
+			b, _, err := reencode(encoded, &pb.GameFrame{})
+			if err != nil {
+				return err
+			}
+			frameBytes[i] = withChecksum(b)
+			framesChanged = framesChanged || !bytes.Equal(frameBytes[i].([]byte), []byte(data))
+		}
+		if !gameChanged && !framesChanged {
+			return nil
+		}
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.HSet(gk, "state", gameBytes)
+			if framesChanged {
+				pipe.Del(fk)
+				rs.appendFrames(pipe, id, 0, frameBytes)
+				if ttl > 0 {
+					pipe.PExpire(fk, ttl)
+				}
+			}
+			return nil
+		})
+		return err
+	}, gk, fk)
+	if err == controller.ErrNotFound || err == ErrCorruptFrame {
+		return err
+	}
+	if err != nil {
+		return errors.Wrap(err, "unexpected redis error while compacting game")
+	}
+
+	return nil
+}
+
+// reencode unmarshals data into msg and marshals it again, reporting whether
+// the encoding changed.
+func reencode(data []byte, msg proto.Message) ([]byte, bool, error) {
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, false, errors.Wrap(err, "unable to unmarshal stored data")
+	}
+	encoded, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "unable to marshal stored data")
+	}
+	return encoded, !bytes.Equal(encoded, data), nil
+}
+
+// UpdateGame replaces the stored game state. The game and its frames are
+// watched, so a frame pushed while the game is updated makes the update fail
+// instead of changing the rules of a game in progress.
+func (rs *Store) UpdateGame(c context.Context, game *pb.Game) error {
+	gk, fk := gameKey(game.ID), rs.frameKey(game.ID)
+	gameBytes, err := proto.Marshal(game)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal game state")
+	}
+	err = rs.client.Watch(func(tx *redis.Tx) error {
+		exists, err := tx.Exists(gk).Result()
+		if err != nil {
+			return err
+		}
+		if exists == 0 {
+			return controller.ErrNotFound
+		}
+		n, err := rs.frameCount(tx, game.ID)
+		if err != nil {
+			return err
+		}
+		if n > 1 {
+			return controller.ErrInProgress
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.HSet(gk, "state", gameBytes)
+			pipe.HSet(gk, "name", game.Name)
+			if game.Name != "" {
+				pipe.SAdd(gameNamesKey, gameNameEntry(game.Name, game.ID))
+			}
+			return nil
+		})
+		return err
+	}, gk, fk)
+	if err == controller.ErrNotFound || err == controller.ErrInProgress {
+		return err
+	}
+	if err != nil {
+		return errors.Wrap(err, "unexpected redis error while updating game")
+	}
+
+	return nil
+}
+
+// RenameGame moves a game from oldID to newID, for merging datasets whose IDs
+// collide. The game, its frames and its lock are renamed in one transaction,
+// keeping their expiry, and the ID stored in the game state is rewritten. The
+// keys are watched, so a frame pushed in the meantime aborts the rename.
+// ErrGameExists is returned when newID is taken. A worker holding the lock of
+// the game can not push frames for it once it is renamed.
+func (rs *Store) RenameGame(c context.Context, oldID, newID string) error {
+	oldGK, oldFK, oldLK := gameKey(oldID), rs.frameKey(oldID), gameLockKey(oldID)
+	newGK, newFK, newLK := gameKey(newID), rs.frameKey(newID), gameLockKey(newID)
+	err := rs.client.Watch(func(tx *redis.Tx) error {
+		gameData, err := tx.HGet(oldGK, "state").Bytes()
+		if err == redis.Nil {
+			return controller.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		taken, err := tx.Exists(newGK, newFK, newLK).Result()
+		if err != nil {
+			return err
+		}
+		if taken > 0 {
+			return controller.ErrGameExists
+		}
+		// RENAME fails on keys that do not exist, a game may have neither
+		// frames nor a lock.
+		hasFrames, err := tx.Exists(oldFK).Result()
+		if err != nil {
+			return err
+		}
+		hasLock, err := tx.Exists(oldLK).Result()
+		if err != nil {
+			return err
+		}
+
+		game := &pb.Game{}
+		if err := proto.Unmarshal(gameData, game); err != nil {
+			return errors.Wrap(err, "unable to unmarshal game state")
+		}
+		game.ID = newID
+		gameBytes, err := proto.Marshal(game)
+		if err != nil {
+			return errors.Wrap(err, "unable to marshal game state")
+		}
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.Rename(oldGK, newGK)
+			pipe.HSet(newGK, "state", gameBytes)
+			pipe.HSet(newGK, "id", newID)
+			if game.Name != "" {
+				pipe.SAdd(gameNamesKey, gameNameEntry(game.Name, newID))
+			}
+			if hasFrames > 0 {
+				pipe.Rename(oldFK, newFK)
+			}
+			if hasLock > 0 {
+				pipe.Rename(oldLK, newLK)
+			}
+			return nil
+		})
+		return err
+	}, oldGK, oldFK, oldLK, newGK, newFK, newLK)
+	if err == controller.ErrNotFound || err == controller.ErrGameExists {
+		return err
+	}
+	if err != nil {
+		return errors.Wrap(err, "unexpected redis error while renaming game")
+	}
+
+	return nil
+}
+
+// FindGamesByName returns the IDs of the games whose name starts with prefix,
+// sorted. Names are kept in a single set of name and ID entries that is
+// walked with SSCAN, redis has no cheap way to search for a substring, so only
+// the start of a name is matched and every lookup scans the whole set. This
+// is fine for the number of games a lobby lists, not for large datasets.
+//
+// Entries are added when a game is created, renamed or updated and are not
+// removed when the game changes or expires. Entries that no longer match the
+// stored name of their game are dropped when a lookup comes across them.
+func (rs *Store) FindGamesByName(c context.Context, prefix string) ([]string, error) {
+	iter := rs.client.SScan(gameNamesKey, 0, globEscape(prefix)+"*", statsScanCount).Iterator()
+	ids := []string{}
+	for iter.Next() {
+		entry := iter.Val()
+		name, id := splitGameNameEntry(entry)
+		stored, err := rs.client.HGet(gameKey(id), "name").Result()
+		if err != nil && err != redis.Nil {
+			return nil, errors.Wrap(err, "unexpected redis error while finding games")
+		}
+		if stored != name {
+			rs.client.SRem(gameNamesKey, entry)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, errors.Wrap(err, "unexpected redis error while finding games")
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
 // GetGame will fetch the game.
 func (rs *Store) GetGame(c context.Context, id string) (*pb.Game, error) {
 	// Marshal the game
@@ -256,6 +1000,133 @@ func (rs *Store) GetGame(c context.Context, id string) (*pb.Game, error) {
 	return &game, nil
 }
 
+// GetGameStatus will fetch the status field of the game hash, the game state
+// is not read.
+func (rs *Store) GetGameStatus(c context.Context, id string) (string, error) {
+	status, err := rs.client.HGet(gameKey(id), "status").Result()
+	if err == redis.Nil {
+		return "", controller.ErrNotFound
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "unexpected redis error")
+	}
+	return status, nil
+}
+
+// GetGameAndLastFrame will fetch the game and its latest frame, pipelining the
+// game fetch with a read of the last frame so it costs one round trip.
+func (rs *Store) GetGameAndLastFrame(c context.Context, id string) (*pb.Game, *pb.GameFrame, error) {
+	pipe := rs.client.Pipeline()
+	gameData := pipe.HGet(gameKey(id), "state")
+	gameStatus := pipe.HGet(gameKey(id), "status")
+	lastFrame := rs.lastFrame(pipe, id)
+
+	// A missing game or frame shows up as redis.Nil, which is handled per
+	// command below.
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, nil, errors.Wrap(err, "unexpected redis error")
+	}
+
+	gameBytes, err := gameData.Bytes()
+	if err == redis.Nil {
+		return nil, nil, controller.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unexpected redis error")
+	}
+	var game pb.Game
+	err = proto.Unmarshal(gameBytes, &game)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to unmarshal game data")
+	}
+	game.Status = gameStatus.Val()
+
+	frameBytes, err := lastFrame()
+	if err == redis.Nil {
+		return &game, nil, nil
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unexpected redis error")
+	}
+	var frame pb.GameFrame
+	err = rs.unmarshalFrame(frameBytes, &frame)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &game, &frame, nil
+}
+
+// pushFencedFrameCmd pushes the frame in ARGV[2] for turn ARGV[4] if the lock
+// is held by the token passed, or if no token is passed and nobody holds the
+// lock. It returns 0 when the lock stops the write, -1 when the frames no
+// longer have the count ARGV[3] the frame was checked against and 2 when the
+// turn is not the next one, the frame count. The turn is compared as a string
+// so the script does not have to convert it. pushFencedFrameByTurnCmd does the
+// same for frames stored by turn.
+var (
+	pushFencedFrameCmd       = newPushFencedFrameScript("LLEN", `redis.call("RPUSH", KEYS[2], ARGV[2])`)
+	pushFencedFrameByTurnCmd = newPushFencedFrameScript("HLEN", `redis.call("HSET", KEYS[2], ARGV[4], ARGV[2])`)
+)
+
+func newPushFencedFrameScript(count, push string) *redis.Script {
+	return redis.NewScript(fmt.Sprintf(`
+	if ARGV[1] == "" then
+		if redis.call("EXISTS", KEYS[1]) == 1 then
+			return 0
+		end
+	elseif redis.call("GET", KEYS[1]) ~= ARGV[1] then
+		return 0
+	end
+	if redis.call("%s", KEYS[2]) ~= tonumber(ARGV[3]) then
+		return -1
+	end
+	if ARGV[4] ~= ARGV[3] then
+		return 2
+	end
+	%s
+	return 1
+`, count, push))
+}
+
+// pushFramesCmd pushes the frames from ARGV[3] on if the lock is held by the
+// token passed in ARGV[1], or no token is passed and nobody holds the lock,
+// and the game has ARGV[2] frames. It returns 0 when the lock stops the write
+// and -1 when the first frame
+// does not follow the stored ones. pushFramesByTurnCmd does the same for
+// frames stored by turn. Frames are pushed one at a time rather than unpacked
+// into a single RPUSH, which has a limit on its arguments and trips the
+// pointer checks of the Lua interpreter the tests run on under -race.
+var (
+	pushFramesCmd = newPushFramesScript("LLEN", `
+	for i = 3, #ARGV do
+		redis.call("RPUSH", KEYS[2], ARGV[i])
+	end`)
+	pushFramesByTurnCmd = newPushFramesScript("HLEN", `
+	for i = 3, #ARGV do
+		redis.call("HSET", KEYS[2], first + i - 3, ARGV[i])
+	end`)
+)
+
+func newPushFramesScript(count, push string) *redis.Script {
+	return redis.NewScript(fmt.Sprintf(`
+	if ARGV[1] == "" then
+		if redis.call("EXISTS", KEYS[1]) == 1 then
+			return 0
+		end
+	elseif redis.call("GET", KEYS[1]) ~= ARGV[1] then
+		return 0
+	end
+	local first = tonumber(ARGV[2])
+	if redis.call("%s", KEYS[2]) ~= first then
+		return -1
+	end
+	%s
+	return 1
+`, count, push))
+}
+
 var unlockCmd = redis.NewScript(`
 	if redis.call("GET", KEYS[1]) == ARGV[1] then
 		redis.call("DEL", KEYS[1])
@@ -264,9 +1135,12 @@ var unlockCmd = redis.NewScript(`
 	return false
 `)
 
-var findUnlockedGameCmd = redis.NewScript(fmt.Sprintf(`
+// findUnlockedGamesCmd returns up to ARGV[1] ids of games that are running
+// and not locked.
+var findUnlockedGamesCmd = redis.NewScript(fmt.Sprintf(`
+	local limit = tonumber(ARGV[1]);
+	local ids = {};
 	local cursor = "0";
-	local done = false;
 	repeat
 		local result = redis.call("SCAN", cursor, "match", "game:*:state");
 		cursor = result[1];
@@ -275,17 +1149,49 @@ var findUnlockedGameCmd = redis.NewScript(fmt.Sprintf(`
 			local id = redis.call("HGET", key, "id");
 			if redis.call("EXISTS", "game:" .. id .. ":locks") == 0 then
 				if redis.call("HGET", key, "status") == "%s" then
-					return id;
+					ids[#ids + 1] = id;
+					if #ids >= limit then
+						return ids;
+					end
 				end
 			end
 		end
-		if cursor == "0" then
-        	done = true;
-    	end
-	until done
-	return ""
+	until cursor == "0"
+	return ids
 `, rules.GameStatusRunning))
 
+// results of rewindGameCmd
+const (
+	rewindOK          = 1
+	rewindNotFound    = -1
+	rewindInvalidTurn = -2
+)
+
+// rewindGameCmd drops the frames after turn ARGV[1], rewindGameByTurnCmd does
+// the same for frames stored by turn.
+var (
+	rewindGameCmd       = newRewindGameScript("LLEN", `redis.call("LTRIM", KEYS[2], 0, toTurn)`)
+	rewindGameByTurnCmd = newRewindGameScript("HLEN", `for turn = toTurn + 1, n - 1 do redis.call("HDEL", KEYS[2], turn) end`)
+)
+
+func newRewindGameScript(count, trim string) *redis.Script {
+	return redis.NewScript(fmt.Sprintf(`
+	if redis.call("EXISTS", KEYS[1]) == 0 then
+		return %d
+	end
+	local toTurn = tonumber(ARGV[1])
+	local n = redis.call("%s", KEYS[2])
+	if toTurn < 0 or toTurn >= n then
+		return %d
+	end
+	%s
+	if redis.call("HGET", KEYS[1], "status") == "%s" then
+		redis.call("HSET", KEYS[1], "status", "%s")
+	end
+	return %d
+`, rewindNotFound, count, rewindInvalidTurn, trim, rules.GameStatusComplete, rules.GameStatusRunning, rewindOK))
+}
+
 // generates the redis key for a game
 func gameKey(gameID string) string {
 	return fmt.Sprintf("game:%s:state", gameID)
@@ -296,6 +1202,35 @@ func framesKey(gameID string) string {
 	return fmt.Sprintf("game:%s:frames", gameID)
 }
 
+// gameNamesKey is the set FindGamesByName searches, see gameNameEntry.
+const gameNamesKey = "games:names"
+
+// gameNameEntry returns the entry of a game in the name index. Names come
+// first, so entries are matched by the start of the name.
+func gameNameEntry(name, id string) string {
+	return name + "\x00" + id
+}
+
+func splitGameNameEntry(entry string) (name, id string) {
+	i := strings.LastIndexByte(entry, 0)
+	if i < 0 {
+		return entry, ""
+	}
+	return entry[:i], entry[i+1:]
+}
+
+// globEscape escapes the characters redis treats as patterns in MATCH.
+func globEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 // generates the redis key for game lock state
 func gameLockKey(gameID string) string {
 	return fmt.Sprintf("game:%s:locks", gameID)
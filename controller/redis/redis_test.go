@@ -2,16 +2,19 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/battlesnakeio/engine/controller"
 	"github.com/battlesnakeio/engine/controller/pb"
 	"github.com/battlesnakeio/engine/rules"
 	"github.com/dlsteuer/miniredis"
 	"github.com/go-redis/redis"
+	"github.com/gogo/protobuf/proto"
 	"github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -39,6 +42,21 @@ func TestLock(t *testing.T) {
 	assert.NotNil(t, tkn, "should still get a reasonable token back")
 }
 
+func TestLockInfo(t *testing.T) {
+	gameKey := uuid.NewV4().String()
+
+	_, err := store.LockInfo(context.Background(), gameKey)
+	assert.Equal(t, controller.ErrNotFound, err)
+
+	tkn, err := store.Lock(context.Background(), gameKey, controller.NewLockToken("host-1"))
+	require.NoError(t, err)
+	info, err := store.LockInfo(context.Background(), gameKey)
+	require.NoError(t, err)
+	assert.Equal(t, tkn, info.Token)
+	assert.Equal(t, "host-1", info.WorkerID)
+	assert.True(t, info.Expires.After(time.Now()))
+}
+
 // Unlock will unlock a game if it is locked and the token used to lock it
 // is correct.
 func TestUnlock(t *testing.T) {
@@ -59,6 +77,24 @@ func TestUnlock(t *testing.T) {
 	assert.NotZero(t, tkn, "expect a reasonable token string back")
 }
 
+func TestForceUnlock(t *testing.T) {
+	gameKey := uuid.NewV4().String()
+
+	// No lock to remove
+	err := store.ForceUnlock(context.Background(), gameKey)
+	assert.Equal(t, controller.ErrNotFound, err)
+
+	_, err = store.Lock(context.Background(), gameKey, "")
+	assert.NoError(t, err)
+
+	// Removed without the token
+	err = store.ForceUnlock(context.Background(), gameKey)
+	assert.NoError(t, err)
+	tkn, err := store.Lock(context.Background(), gameKey, "")
+	assert.NoError(t, err, "this should be a new lock")
+	assert.NotZero(t, tkn)
+}
+
 // PopGameID returns a new game that is unlocked and running. Workers call
 // this method through the controller to find games to process.
 func TestPopGameID(t *testing.T) {
@@ -92,6 +128,46 @@ func TestPopGameID(t *testing.T) {
 	assert.Zero(t, poppedID, "no game should be returned when empty unlocked games")
 }
 
+func TestBlockingPopGameIDShortDeadline(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()),
+		WithBlockingPop(time.Minute, 10*time.Millisecond))
+	require.NoError(t, err)
+	defer store.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	gameID, err := store.PopGameID(ctx)
+	require.Equal(t, controller.ErrNotFound, err)
+	assert.Zero(t, gameID)
+	assert.True(t, time.Since(start) < time.Second, "pop should give up at the context deadline")
+}
+
+func TestBlockingPopGameIDWaitsForGame(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()),
+		WithBlockingPop(2*time.Second, 10*time.Millisecond))
+	require.NoError(t, err)
+	defer store.Close()
+
+	game := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusRunning)}
+	created := make(chan error, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		created <- store.CreateGame(context.Background(), game, nil)
+	}()
+
+	gameID, err := store.PopGameID(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, <-created)
+	assert.Equal(t, game.ID, gameID)
+}
+
 // SetGameStatus is used to set a specific game status. This operation
 // should be atomic.
 func TestSetGameStatus(t *testing.T) {
@@ -114,7 +190,156 @@ func TestSetGameStatus(t *testing.T) {
 	assert.Equal(t, string(status), game.GetStatus())
 }
 
+func TestListGameFramesLimit(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()))
+	require.NoError(t, err)
+	defer store.Close()
+	// The limit is checked before redis is asked, so a store that can not
+	// reach redis still reports it.
+	server.Close()
+
+	_, err = store.ListGameFrames(context.Background(), "test", 0, 0)
+	assert.Equal(t, controller.ErrZeroLimit, err)
+	_, err = store.ListGameFrames(context.Background(), "test", -5, 0)
+	assert.Equal(t, controller.ErrNegativeLimit, err)
+	_, err = store.ListGameFramesReverse(context.Background(), "test", 0, 0)
+	assert.Equal(t, controller.ErrZeroLimit, err)
+	_, err = store.ListGameFramesReverse(context.Background(), "test", -5, 0)
+	assert.Equal(t, controller.ErrNegativeLimit, err)
+
+	_, err = store.ListGameFrames(context.Background(), "test", 1, 0)
+	assert.Error(t, err)
+}
+
+func TestGetGameStatus(t *testing.T) {
+	_, err := store.GetGameStatus(context.Background(), uuid.NewV4().String())
+	assert.Equal(t, controller.ErrNotFound, err)
+
+	game := &pb.Game{
+		ID:     uuid.NewV4().String(),
+		Status: string(rules.GameStatusStopped),
+	}
+	err = store.CreateGame(context.Background(), game, nil)
+	require.NoError(t, err)
+
+	for _, status := range []rules.GameStatus{rules.GameStatusRunning, rules.GameStatusComplete} {
+		require.NoError(t, store.SetGameStatus(context.Background(), game.ID, status))
+		current, err := store.GetGameStatus(context.Background(), game.ID)
+		require.NoError(t, err)
+		assert.Equal(t, string(status), current)
+	}
+}
+
 // Test Create/Get games
+func TestSetGameStatusPauseResume(t *testing.T) {
+	game := &pb.Game{
+		ID:     uuid.NewV4().String(),
+		Status: string(rules.GameStatusRunning),
+	}
+	err := store.CreateGame(context.Background(), game, nil)
+	assert.NoError(t, err)
+
+	err = store.SetGameStatus(context.Background(), game.ID, rules.GameStatusPaused)
+	assert.NoError(t, err)
+	game, _ = store.GetGame(context.Background(), game.ID)
+	assert.Equal(t, string(rules.GameStatusPaused), game.Status)
+
+	err = store.SetGameStatus(context.Background(), game.ID, rules.GameStatusRunning)
+	assert.NoError(t, err)
+	err = store.SetGameStatus(context.Background(), game.ID, rules.GameStatusComplete)
+	assert.NoError(t, err)
+
+	err = store.SetGameStatus(context.Background(), game.ID, rules.GameStatusPaused)
+	assert.Equal(t, controller.ErrInvalidTransition, err)
+}
+
+func TestPopGameIDSkipsPaused(t *testing.T) {
+	resetRedisServer(t)
+	// The first command after a restart fails on the stale connection.
+	store.PopGameID(context.Background())
+
+	game := &pb.Game{
+		ID:     uuid.NewV4().String(),
+		Status: string(rules.GameStatusPaused),
+	}
+	err := store.CreateGame(context.Background(), game, nil)
+	assert.NoError(t, err)
+
+	_, err = store.PopGameID(context.Background())
+	assert.Equal(t, controller.ErrNotFound, err)
+
+	err = store.SetGameStatus(context.Background(), game.ID, rules.GameStatusRunning)
+	assert.NoError(t, err)
+	poppedID, err := store.PopGameID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, game.ID, poppedID)
+}
+
+func TestPeekRunningGames(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()))
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	ids, err := store.PeekRunningGames(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, ids)
+
+	var running []string
+	for _, status := range []rules.GameStatus{rules.GameStatusRunning, rules.GameStatusRunning, rules.GameStatusPaused} {
+		game := &pb.Game{ID: uuid.NewV4().String(), Status: string(status)}
+		require.NoError(t, store.CreateGame(ctx, game, nil))
+		if status == rules.GameStatusRunning {
+			running = append(running, game.ID)
+		}
+	}
+
+	ids, err = store.PeekRunningGames(ctx, 10)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, running, ids)
+	ids, err = store.PeekRunningGames(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, ids, 1)
+
+	// Peeking does not lock, so the games can still be popped.
+	popped, err := store.PopGameID(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, running, popped)
+	_, err = store.Lock(ctx, popped, "")
+	require.NoError(t, err)
+	ids, err = store.PeekRunningGames(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, ids, 1)
+	assert.NotContains(t, ids, popped)
+}
+
+func TestGetGameAndLastFrame(t *testing.T) {
+	_, _, err := store.GetGameAndLastFrame(context.Background(), uuid.NewV4().String())
+	assert.Equal(t, controller.ErrNotFound, err)
+
+	game := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusRunning)}
+	err = store.CreateGame(context.Background(), game, nil)
+	assert.NoError(t, err)
+	g, f, err := store.GetGameAndLastFrame(context.Background(), game.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, game, g)
+	assert.Nil(t, f)
+
+	for _, frame := range testFrames {
+		err = store.PushGameFrame(context.Background(), game.ID, "", frame)
+		assert.NoError(t, err)
+	}
+	g, f, err = store.GetGameAndLastFrame(context.Background(), game.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, game, g)
+	assert.Equal(t, testFrames[len(testFrames)-1], f)
+}
+
 func TestCreateGame(t *testing.T) {
 
 	// Iterate over each game case and ensure they persist correctly
@@ -127,6 +352,13 @@ func TestCreateGame(t *testing.T) {
 		assert.NoError(t, err, "all games should have created and be retrievable")
 		assert.Equal(t, gameCase.game, game)
 	}
+
+	// Frames created with the game are stored in turn order.
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	require.NoError(t, store.CreateGame(context.Background(), game, testFrames))
+	frames, err := store.ListGameFrames(context.Background(), game.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, testFrames, frames)
 }
 
 // Tests PushGameFrame and ListGameFrames
@@ -142,7 +374,7 @@ func TestPushGameFrame(t *testing.T) {
 	assert.Zero(t, frames, "no frames yet")
 
 	// 1 frame
-	err = store.PushGameFrame(context.Background(), game.ID, testFrames[0])
+	err = store.PushGameFrame(context.Background(), game.ID, "", testFrames[0])
 	assert.NoError(t, err)
 	frames, err = store.ListGameFrames(context.Background(), game.ID, 10, 0)
 	assert.NoError(t, err)
@@ -150,9 +382,9 @@ func TestPushGameFrame(t *testing.T) {
 	assert.Equal(t, 1, len(frames), "only 1 frame should be present")
 
 	// remaining frames
-	err = store.PushGameFrame(context.Background(), game.ID, testFrames[1])
+	err = store.PushGameFrame(context.Background(), game.ID, "", testFrames[1])
 	assert.NoError(t, err)
-	err = store.PushGameFrame(context.Background(), game.ID, testFrames[2])
+	err = store.PushGameFrame(context.Background(), game.ID, "", testFrames[2])
 	assert.NoError(t, err)
 	frames, err = store.ListGameFrames(context.Background(), game.ID, 10, 0)
 	assert.NoError(t, err)
@@ -173,7 +405,18 @@ func TestPushGameFrame(t *testing.T) {
 		assert.Contains(t, frames, f, "the frames should match the test frames, so offset by 1 and limit 2 should mean 2nd and 3rd frames")
 	}
 
+	// offset and limit take exactly limit frames
+	frames, err = store.ListGameFrames(context.Background(), game.ID, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.GameFrame{testFrames[1]}, frames)
+
 	// negative offset
+	frames, err = store.ListGameFrames(context.Background(), game.ID, 2, -2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.GameFrame{testFrames[1], testFrames[2]}, frames)
+	frames, err = store.ListGameFrames(context.Background(), game.ID, 5, -2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.GameFrame{testFrames[1], testFrames[2]}, frames)
 	frames, err = store.ListGameFrames(context.Background(), game.ID, 1, -1)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(frames), "should only be 1 frame")
@@ -189,6 +432,772 @@ func TestPushGameFrame(t *testing.T) {
 	assert.Zero(t, frames)
 }
 
+func TestPushGameFrameSequence(t *testing.T) {
+	testPushGameFrameSequence(t, store)
+
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	byTurn, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()), WithFramesByTurn())
+	require.NoError(t, err)
+	defer byTurn.Close()
+	testPushGameFrameSequence(t, byTurn)
+}
+
+func testPushGameFrameSequence(t *testing.T, store controller.Store) {
+	ctx := context.Background()
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	require.NoError(t, store.CreateGame(ctx, game, testFrames[:2]))
+
+	// A gap is rejected.
+	err := store.PushGameFrame(ctx, game.ID, "", &pb.GameFrame{Turn: 3})
+	assert.Equal(t, controller.ErrInvalidSequence, err)
+	// So is a turn before the last stored frame.
+	err = store.PushGameFrame(ctx, game.ID, "", &pb.GameFrame{Turn: 0})
+	assert.Equal(t, controller.ErrInvalidSequence, err)
+
+	require.NoError(t, store.PushGameFrame(ctx, game.ID, "", testFrames[2]))
+	frames, err := store.ListGameFrames(ctx, game.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, testFrames, frames)
+}
+
+func TestPushGameFrames(t *testing.T) {
+	testPushGameFrames(t, store)
+
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	byTurn, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()), WithFramesByTurn())
+	require.NoError(t, err)
+	defer byTurn.Close()
+	testPushGameFrames(t, byTurn)
+}
+
+func testPushGameFrames(t *testing.T, store controller.Store) {
+	ctx := context.Background()
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	err := store.CreateGame(ctx, game, testFrames[:1])
+	require.NoError(t, err)
+
+	// A batch with a gap is rejected entirely.
+	err = store.PushGameFrames(ctx, game.ID, "", []*pb.GameFrame{{Turn: 1}, {Turn: 3}})
+	assert.Equal(t, controller.ErrInvalidSequence, err)
+	// So is a batch that does not follow the stored frames.
+	err = store.PushGameFrames(ctx, game.ID, "", []*pb.GameFrame{{Turn: 2}, {Turn: 3}})
+	assert.Equal(t, controller.ErrInvalidSequence, err)
+	err = store.PushGameFrames(ctx, game.ID, "", testFrames)
+	assert.Equal(t, controller.ErrInvalidSequence, err)
+	frames, err := store.ListGameFrames(ctx, game.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, testFrames[:1], frames)
+
+	// The lock fences the batch.
+	token, err := store.Lock(ctx, game.ID, "")
+	require.NoError(t, err)
+	err = store.PushGameFrames(ctx, game.ID, "stale", testFrames[1:])
+	assert.Equal(t, controller.ErrLockExpired, err)
+	err = store.PushGameFrames(ctx, game.ID, "", testFrames[1:])
+	assert.Equal(t, controller.ErrIsLocked, err)
+
+	err = store.PushGameFrames(ctx, game.ID, token, testFrames[1:])
+	require.NoError(t, err)
+	frames, err = store.ListGameFrames(ctx, game.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, testFrames, frames)
+
+	// A batch can only be pushed once.
+	err = store.PushGameFrames(ctx, game.ID, token, testFrames[1:])
+	assert.Equal(t, controller.ErrInvalidSequence, err)
+	assert.NoError(t, store.PushGameFrames(ctx, game.ID, token, nil))
+}
+
+func TestPushGameFrameStaleWriter(t *testing.T) {
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	err := store.CreateGame(context.Background(), game, testFrames[:1])
+	require.NoError(t, err)
+
+	stale, err := store.Lock(context.Background(), game.ID, "")
+	require.NoError(t, err)
+	err = store.PushGameFrame(context.Background(), game.ID, stale, testFrames[1])
+	assert.NoError(t, err)
+
+	// The lock expired and was taken by another worker
+	server.FastForward(DefaultLockExpiry + time.Second)
+	current, err := store.Lock(context.Background(), game.ID, "")
+	require.NoError(t, err)
+	err = store.PushGameFrame(context.Background(), game.ID, stale, testFrames[2])
+	assert.Equal(t, controller.ErrLockExpired, err)
+	err = store.PushGameFrame(context.Background(), game.ID, "", testFrames[2])
+	assert.Equal(t, controller.ErrIsLocked, err)
+	err = store.PushGameFrame(context.Background(), game.ID, current, testFrames[2])
+	assert.NoError(t, err)
+
+	frames, err := store.ListGameFrames(context.Background(), game.ID, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames, frames)
+}
+
+func TestWithTx(t *testing.T) {
+	game := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusRunning)}
+	err := store.CreateGame(context.Background(), game, testFrames[:1])
+	require.NoError(t, err)
+	token, err := store.Lock(context.Background(), game.ID, "")
+	require.NoError(t, err)
+
+	requireUnchanged := func() {
+		g, err := store.GetGame(context.Background(), game.ID)
+		require.NoError(t, err)
+		assert.Equal(t, string(rules.GameStatusRunning), g.Status)
+		frames, err := store.ListGameFrames(context.Background(), game.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, testFrames[:1], frames)
+	}
+
+	failure := errors.New("failed")
+	err = store.WithTx(context.Background(), func(tx *controller.StoreTx) error {
+		tx.PushGameFrame(game.ID, token, testFrames[1])
+		tx.SetGameStatus(game.ID, rules.GameStatusComplete)
+		return failure
+	})
+	assert.Equal(t, failure, err)
+	requireUnchanged()
+
+	err = store.WithTx(context.Background(), func(tx *controller.StoreTx) error {
+		tx.PushGameFrame(game.ID, token, testFrames[1])
+		tx.SetGameStatus(game.ID, rules.GameStatusComplete)
+		tx.SetGameStatus(game.ID, rules.GameStatusRunning)
+		return nil
+	})
+	assert.Equal(t, controller.ErrInvalidTransition, err)
+	requireUnchanged()
+
+	err = store.WithTx(context.Background(), func(tx *controller.StoreTx) error {
+		tx.PushGameFrame(game.ID, "", testFrames[1])
+		return nil
+	})
+	assert.Equal(t, controller.ErrIsLocked, err)
+	requireUnchanged()
+
+	err = store.WithTx(context.Background(), func(tx *controller.StoreTx) error {
+		tx.PushGameFrame(game.ID, token, testFrames[2])
+		return nil
+	})
+	assert.Equal(t, controller.ErrInvalidSequence, err)
+	requireUnchanged()
+
+	err = store.WithTx(context.Background(), func(tx *controller.StoreTx) error {
+		tx.PushGameFrame(game.ID, token, testFrames[1])
+		tx.PushGameFrame(game.ID, token, testFrames[2])
+		tx.SetGameStatus(game.ID, rules.GameStatusComplete)
+		return nil
+	})
+	require.NoError(t, err)
+	g, err := store.GetGame(context.Background(), game.ID)
+	require.NoError(t, err)
+	assert.Equal(t, string(rules.GameStatusComplete), g.Status)
+	frames, err := store.ListGameFrames(context.Background(), game.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, testFrames, frames)
+}
+
+func TestPushGameFrameDuplicate(t *testing.T) {
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	err := store.CreateGame(context.Background(), game, testFrames[:2])
+	require.NoError(t, err)
+
+	// Re-running the last tick is a no-op
+	err = store.PushGameFrame(context.Background(), game.ID, "", testFrames[1])
+	assert.NoError(t, err)
+	frames, err := store.ListGameFrames(context.Background(), game.ID, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames[:2], frames)
+
+	// Different content for the same turn
+	conflict := &pb.GameFrame{Turn: 1, Food: []*pb.Point{{X: 3, Y: 3}}}
+	err = store.PushGameFrame(context.Background(), game.ID, "", conflict)
+	assert.Equal(t, controller.ErrFrameConflict, err)
+	frames, err = store.ListGameFrames(context.Background(), game.ID, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames[:2], frames)
+}
+
+func TestRewindGame(t *testing.T) {
+	game := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusComplete)}
+	err := store.CreateGame(context.Background(), game, testFrames)
+	assert.NoError(t, err)
+
+	err = store.RewindGame(context.Background(), game.ID, 3)
+	assert.Equal(t, controller.ErrInvalidTurn, err)
+	err = store.RewindGame(context.Background(), game.ID, -1)
+	assert.Equal(t, controller.ErrInvalidTurn, err)
+
+	err = store.RewindGame(context.Background(), game.ID, 1)
+	assert.NoError(t, err)
+	frames, err := store.ListGameFrames(context.Background(), game.ID, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames[:2], frames)
+
+	game, err = store.GetGame(context.Background(), game.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, string(rules.GameStatusRunning), game.Status)
+
+	err = store.RewindGame(context.Background(), uuid.NewV4().String(), 0)
+	assert.Equal(t, controller.ErrNotFound, err)
+}
+
+func TestUpdateGame(t *testing.T) {
+	game := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusStopped), Width: 5}
+	err := store.UpdateGame(context.Background(), game)
+	assert.Equal(t, controller.ErrNotFound, err)
+
+	err = store.CreateGame(context.Background(), game, testFrames[:1])
+	require.NoError(t, err)
+	err = store.UpdateGame(context.Background(), &pb.Game{ID: game.ID, Width: 7})
+	assert.NoError(t, err)
+	g, err := store.GetGame(context.Background(), game.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), g.Width)
+	assert.Equal(t, string(rules.GameStatusStopped), g.Status)
+
+	// In progress
+	err = store.PushGameFrame(context.Background(), game.ID, "", testFrames[1])
+	require.NoError(t, err)
+	err = store.UpdateGame(context.Background(), &pb.Game{ID: game.ID, Width: 9})
+	assert.Equal(t, controller.ErrInProgress, err)
+}
+
+func TestListGameFramesReverse(t *testing.T) {
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	err := store.CreateGame(context.Background(), game, testFrames)
+	assert.NoError(t, err)
+
+	frames, err := store.ListGameFramesReverse(context.Background(), game.ID, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.GameFrame{testFrames[2], testFrames[1], testFrames[0]}, frames)
+
+	frames, err = store.ListGameFramesReverse(context.Background(), game.ID, 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.GameFrame{testFrames[2]}, frames)
+
+	frames, err = store.ListGameFramesReverse(context.Background(), game.ID, 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.GameFrame{testFrames[1], testFrames[0]}, frames)
+
+	frames, err = store.ListGameFramesReverse(context.Background(), game.ID, 2, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, frames)
+
+	// A negative offset counts from the first frame
+	frames, err = store.ListGameFramesReverse(context.Background(), game.ID, 1, -2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.GameFrame{testFrames[1]}, frames)
+	frames, err = store.ListGameFramesReverse(context.Background(), game.ID, 5, -2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.GameFrame{testFrames[1], testFrames[0]}, frames)
+
+	_, err = store.ListGameFramesReverse(context.Background(), game.ID, 0, 0)
+	assert.Error(t, err)
+}
+
+func TestCompactGame(t *testing.T) {
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	err := store.CreateGame(context.Background(), game, testFrames[:1])
+	assert.NoError(t, err)
+
+	// Store a frame the way an older encoder might have, with the fields out
+	// of order. It decodes to the same frame, but is not encoded canonically.
+	turn, err := proto.Marshal(&pb.GameFrame{Turn: testFrames[1].Turn})
+	require.NoError(t, err)
+	rest, err := proto.Marshal(&pb.GameFrame{Food: testFrames[1].Food, Snakes: testFrames[1].Snakes})
+	require.NoError(t, err)
+	legacy := append(rest, turn...)
+	client := store.(*Store).client
+	err = client.RPush(framesKey(game.ID), legacy).Err()
+	require.NoError(t, err)
+
+	err = store.CompactGame(context.Background(), game.ID)
+	assert.NoError(t, err)
+	data, err := client.LIndex(framesKey(game.ID), 1).Bytes()
+	require.NoError(t, err)
+	canonical, err := proto.Marshal(testFrames[1])
+	require.NoError(t, err)
+	assert.Equal(t, withChecksum(canonical), data)
+
+	frames, err := store.ListGameFrames(context.Background(), game.ID, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames[:2], frames)
+
+	// Already compact
+	err = store.CompactGame(context.Background(), game.ID)
+	assert.NoError(t, err)
+
+	err = store.CompactGame(context.Background(), uuid.NewV4().String())
+	assert.Equal(t, controller.ErrNotFound, err)
+}
+
+func TestRenameGame(t *testing.T) {
+	ctx := context.Background()
+	rs := store.(*Store)
+	game := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusRunning)}
+	require.NoError(t, store.CreateGame(ctx, game, testFrames))
+	token, err := store.Lock(ctx, game.ID, "")
+	require.NoError(t, err)
+
+	newID := uuid.NewV4().String()
+	err = rs.RenameGame(ctx, game.ID, newID)
+	assert.NoError(t, err)
+
+	renamed, err := store.GetGame(ctx, newID)
+	assert.NoError(t, err)
+	assert.Equal(t, newID, renamed.ID)
+	assert.Equal(t, string(rules.GameStatusRunning), renamed.Status)
+	frames, err := store.ListGameFrames(ctx, newID, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames, frames)
+	assert.NoError(t, store.Unlock(ctx, newID, token), "the lock moves along")
+	ids, err := store.PeekRunningGames(ctx, 1000)
+	assert.NoError(t, err)
+	assert.Contains(t, ids, newID)
+	assert.NotContains(t, ids, game.ID)
+
+	n, err := rs.client.Exists(gameKey(game.ID), framesKey(game.ID), gameLockKey(game.ID)).Result()
+	assert.NoError(t, err)
+	assert.Zero(t, n, "nothing is left under the old ID")
+	err = rs.RenameGame(ctx, game.ID, uuid.NewV4().String())
+	assert.Equal(t, controller.ErrNotFound, err)
+}
+
+func TestRenameGameCollision(t *testing.T) {
+	ctx := context.Background()
+	rs := store.(*Store)
+	a := &pb.Game{ID: uuid.NewV4().String(), Width: 5}
+	b := &pb.Game{ID: uuid.NewV4().String(), Width: 7}
+	require.NoError(t, store.CreateGame(ctx, a, testFrames[:1]))
+	require.NoError(t, store.CreateGame(ctx, b, testFrames))
+
+	err := rs.RenameGame(ctx, a.ID, b.ID)
+	assert.Equal(t, controller.ErrGameExists, err)
+
+	// Neither game was touched.
+	game, err := store.GetGame(ctx, a.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), game.Width)
+	game, err = store.GetGame(ctx, b.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), game.Width)
+	frames, err := store.ListGameFrames(ctx, b.ID, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames, frames)
+
+	// Renaming a game to its own ID collides with itself.
+	err = rs.RenameGame(ctx, a.ID, a.ID)
+	assert.Equal(t, controller.ErrGameExists, err)
+}
+
+func TestFindGamesByName(t *testing.T) {
+	ctx := context.Background()
+	rs := store.(*Store)
+	lobby := uuid.NewV4().String()
+	friday := &pb.Game{ID: uuid.NewV4().String(), Name: lobby + " friday"}
+	fridayFinal := &pb.Game{ID: uuid.NewV4().String(), Name: lobby + " friday final"}
+	glob := &pb.Game{ID: uuid.NewV4().String(), Name: lobby + " [*]"}
+	for _, g := range []*pb.Game{friday, fridayFinal, glob, {ID: uuid.NewV4().String()}} {
+		require.NoError(t, store.CreateGame(ctx, g, testFrames[:1]))
+	}
+
+	ids, err := rs.FindGamesByName(ctx, lobby+" friday final")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{fridayFinal.ID}, ids)
+
+	ids, err = rs.FindGamesByName(ctx, lobby+" friday")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{friday.ID, fridayFinal.ID}, ids)
+
+	// Pattern characters in names are matched literally.
+	ids, err = rs.FindGamesByName(ctx, lobby+" [*]")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{glob.ID}, ids)
+	ids, err = rs.FindGamesByName(ctx, lobby+" *")
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	// Games that were renamed or updated are found by their new ID and name.
+	newID := uuid.NewV4().String()
+	require.NoError(t, rs.RenameGame(ctx, friday.ID, newID))
+	require.NoError(t, store.UpdateGame(ctx, &pb.Game{ID: fridayFinal.ID, Name: lobby + " saturday"}))
+	ids, err = rs.FindGamesByName(ctx, lobby+" friday")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{newID}, ids)
+	ids, err = rs.FindGamesByName(ctx, lobby+" saturday")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{fridayFinal.ID}, ids)
+
+	ids, err = rs.FindGamesByName(ctx, uuid.NewV4().String())
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestChecksumVerification(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()), WithChecksumVerification())
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	err = store.CreateGame(ctx, game, testFrames[:2])
+	require.NoError(t, err)
+	frames, err := store.ListGameFrames(ctx, game.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, testFrames[:2], frames)
+
+	// Frames stored without a checksum are still read.
+	legacy, err := proto.Marshal(testFrames[2])
+	require.NoError(t, err)
+	err = store.client.RPush(framesKey(game.ID), legacy).Err()
+	require.NoError(t, err)
+	frames, err = store.ListGameFrames(ctx, game.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, testFrames, frames)
+
+	// Flip a bit in the stored food of the second frame.
+	stored, err := store.client.LIndex(framesKey(game.ID), 1).Bytes()
+	require.NoError(t, err)
+	mangled := append([]byte{}, stored...)
+	mangled[len(mangled)-1] ^= 0x01
+	err = store.client.LSet(framesKey(game.ID), 1, mangled).Err()
+	require.NoError(t, err)
+
+	_, err = store.ListGameFrames(ctx, game.ID, 10, 0)
+	assert.Equal(t, ErrCorruptFrame, err)
+	_, err = store.ListGameFramesSince(ctx, game.ID, 0)
+	assert.Equal(t, ErrCorruptFrame, err)
+
+	// Without verification the mangled frame is read as it is.
+	unverified := &Store{client: store.client}
+	frames, err = unverified.ListGameFrames(ctx, game.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, frames, 3)
+}
+
+func TestListGameFramesSince(t *testing.T) {
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	err := store.CreateGame(context.Background(), game, testFrames[:1])
+	assert.NoError(t, err)
+	err = store.PushGameFrame(context.Background(), game.ID, "", testFrames[1])
+	assert.NoError(t, err)
+	err = store.PushGameFrame(context.Background(), game.ID, "", testFrames[2])
+	assert.NoError(t, err)
+
+	// Only the frames after turn 0
+	frames, err := store.ListGameFramesSince(context.Background(), game.ID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.GameFrame{testFrames[1], testFrames[2]}, frames)
+
+	// Everything
+	frames, err = store.ListGameFramesSince(context.Background(), game.ID, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames, frames)
+
+	// Caught up
+	frames, err = store.ListGameFramesSince(context.Background(), game.ID, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, frames)
+
+	// Unknown game
+	_, err = store.ListGameFramesSince(context.Background(), uuid.NewV4().String(), 0)
+	assert.Equal(t, controller.ErrNotFound, err)
+}
+
+func TestGetFrameAtTurn(t *testing.T) {
+	testGetFrameAtTurn(t, store.(*Store))
+
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	byTurn, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()), WithFramesByTurn())
+	require.NoError(t, err)
+	defer byTurn.Close()
+	testGetFrameAtTurn(t, byTurn)
+}
+
+func testGetFrameAtTurn(t *testing.T, store *Store) {
+	ctx := context.Background()
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	require.NoError(t, store.CreateGame(ctx, game, testFrames))
+
+	for turn, want := range testFrames {
+		frame, err := store.GetFrameAtTurn(ctx, game.ID, turn)
+		require.NoError(t, err)
+		assert.Equal(t, want, frame)
+	}
+
+	_, err := store.GetFrameAtTurn(ctx, game.ID, len(testFrames))
+	assert.Equal(t, controller.ErrNotFound, err)
+	_, err = store.GetFrameAtTurn(ctx, game.ID, -1)
+	assert.Equal(t, controller.ErrNotFound, err)
+	_, err = store.GetFrameAtTurn(ctx, "missing", 0)
+	assert.Equal(t, controller.ErrNotFound, err)
+}
+
+func TestRetriesSurviveFlappingConnection(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()),
+		WithRetries(20, 10*time.Millisecond, 50*time.Millisecond))
+	require.NoError(t, err)
+	defer store.Close()
+
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	err = store.CreateGame(context.Background(), game, testFrames[:1])
+	require.NoError(t, err)
+
+	// Drop the connection and bring the server back a little later, the push
+	// keeps retrying in the meantime.
+	server.Close()
+	restarted := make(chan error, 1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		restarted <- server.Restart()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err = store.PushGameFrame(ctx, game.ID, "", testFrames[1])
+	require.NoError(t, err)
+	require.NoError(t, <-restarted)
+	require.NoError(t, ctx.Err(), "push should succeed within the context")
+
+	frames, err := store.ListGameFrames(ctx, game.ID, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, testFrames[:2], frames)
+}
+
+func TestNoRetriesFailOnDroppedConnection(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()))
+	require.NoError(t, err)
+	defer store.Close()
+
+	server.Close()
+	err = store.PushGameFrame(context.Background(), uuid.NewV4().String(), "", testFrames[0])
+	require.Error(t, err)
+}
+
+type fakeArchiver struct {
+	err    error
+	calls  int
+	gameID string
+	game   *pb.Game
+	frames []*pb.GameFrame
+}
+
+func (a *fakeArchiver) Archive(ctx context.Context, gameID string, game *pb.Game, frames []*pb.GameFrame) error {
+	a.calls++
+	a.gameID, a.game, a.frames = gameID, game, frames
+	return a.err
+}
+
+func TestArchiveOnComplete(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	archiver := &fakeArchiver{}
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()), WithArchiver(archiver, time.Minute))
+	require.NoError(t, err)
+	defer store.Close()
+
+	game := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusRunning)}
+	err = store.CreateGame(context.Background(), game, testFrames)
+	require.NoError(t, err)
+
+	// Only completing a game archives it
+	err = store.SetGameStatus(context.Background(), game.ID, rules.GameStatusPaused)
+	require.NoError(t, err)
+	err = store.SetGameStatus(context.Background(), game.ID, rules.GameStatusRunning)
+	require.NoError(t, err)
+	assert.Equal(t, 0, archiver.calls)
+
+	err = store.SetGameStatus(context.Background(), game.ID, rules.GameStatusComplete)
+	require.NoError(t, err)
+	assert.Equal(t, 1, archiver.calls)
+	assert.Equal(t, game.ID, archiver.gameID)
+	assert.Equal(t, string(rules.GameStatusComplete), archiver.game.Status)
+	assert.Equal(t, testFrames, archiver.frames)
+	assert.Equal(t, time.Minute, server.TTL(gameKey(game.ID)))
+	assert.Equal(t, time.Minute, server.TTL(framesKey(game.ID)))
+
+	// Completing it again does not archive it twice
+	err = store.SetGameStatus(context.Background(), game.ID, rules.GameStatusComplete)
+	require.NoError(t, err)
+	assert.Equal(t, 1, archiver.calls)
+}
+
+func TestArchiveFailureKeepsGame(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	archiver := &fakeArchiver{err: fmt.Errorf("bucket unavailable")}
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()), WithArchiver(archiver, time.Minute))
+	require.NoError(t, err)
+	defer store.Close()
+
+	game := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusRunning)}
+	err = store.CreateGame(context.Background(), game, testFrames)
+	require.NoError(t, err)
+
+	err = store.SetGameStatus(context.Background(), game.ID, rules.GameStatusComplete)
+	require.NoError(t, err, "the game is completed even when archiving fails")
+	assert.Equal(t, 1, archiver.calls)
+	assert.Equal(t, DefaultDataTTL, server.TTL(gameKey(game.ID)))
+}
+
+func TestStats(t *testing.T) {
+	// Stats covers the whole keyspace, so seed a server of its own.
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()))
+	require.NoError(t, err)
+	defer store.Close()
+
+	stats, err := store.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, StoreStats{}, stats)
+
+	running := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusRunning)}
+	err = store.CreateGame(context.Background(), running, testFrames)
+	require.NoError(t, err)
+	complete := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusComplete)}
+	err = store.CreateGame(context.Background(), complete, testFrames[:1])
+	require.NoError(t, err)
+	_, err = store.Lock(context.Background(), running.ID, "")
+	require.NoError(t, err)
+
+	stats, err = store.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, StoreStats{
+		Games:        2,
+		RunningGames: 1,
+		Frames:       int64(len(testFrames) + 1),
+		Locks:        1,
+	}, stats)
+}
+
+func TestFramesByTurn(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()), WithFramesByTurn())
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	game := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusComplete)}
+	err = store.CreateGame(ctx, game, testFrames[:2])
+	require.NoError(t, err)
+	err = store.PushGameFrame(ctx, game.ID, "", testFrames[2])
+	require.NoError(t, err)
+	err = store.PushGameFrame(ctx, game.ID, "", testFrames[2])
+	require.NoError(t, err, "pushing the last frame again is a no-op")
+	assert.False(t, server.Exists(framesKey(game.ID)))
+	stored, err := marshalFrame(testFrames[2])
+	require.NoError(t, err)
+	assert.Equal(t, string(stored), server.HGet(framesByTurnKey(game.ID), "2"))
+
+	frames, err := store.ListGameFrames(ctx, game.ID, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames, frames)
+	frames, err = store.ListGameFrames(ctx, game.ID, 1, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames[2:], frames)
+	frames, err = store.ListGameFramesReverse(ctx, game.ID, 2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.GameFrame{testFrames[2], testFrames[1]}, frames)
+	frames, err = store.ListGameFramesSince(ctx, game.ID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames[1:], frames)
+
+	_, last, err := store.GetGameAndLastFrame(ctx, game.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames[2], last)
+
+	stats, err := store.Stats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(testFrames)), stats.Frames)
+
+	err = store.RewindGame(ctx, game.ID, 3)
+	assert.Equal(t, controller.ErrInvalidTurn, err)
+	err = store.RewindGame(ctx, game.ID, 0)
+	assert.NoError(t, err)
+	frames, err = store.ListGameFrames(ctx, game.ID, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames[:1], frames)
+
+	err = store.UpdateGame(ctx, &pb.Game{ID: game.ID, Width: 7})
+	assert.NoError(t, err)
+	err = store.PushGameFrame(ctx, game.ID, "", testFrames[1])
+	assert.NoError(t, err)
+	err = store.UpdateGame(ctx, &pb.Game{ID: game.ID, Width: 9})
+	assert.Equal(t, controller.ErrInProgress, err)
+
+	err = store.CompactGame(ctx, game.ID)
+	assert.NoError(t, err)
+	frames, err = store.ListGameFrames(ctx, game.ID, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, testFrames[:2], frames)
+}
+
+func TestStatsCollect(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()))
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	collect := func() map[string]float64 {
+		stats, err := store.Stats(ctx)
+		require.NoError(t, err)
+		return stats.Collect()
+	}
+
+	// Play a game the way a worker would.
+	game := &pb.Game{ID: uuid.NewV4().String(), Status: string(rules.GameStatusStopped)}
+	err = store.CreateGame(ctx, game, testFrames[:1])
+	require.NoError(t, err)
+	err = store.SetGameStatus(ctx, game.ID, rules.GameStatusRunning)
+	require.NoError(t, err)
+	token, err := store.Lock(ctx, game.ID, "")
+	require.NoError(t, err)
+	for _, f := range testFrames[1:] {
+		require.NoError(t, store.PushGameFrame(ctx, game.ID, token, f))
+	}
+	assert.Equal(t, map[string]float64{
+		"games":         1,
+		"running_games": 1,
+		"frames":        3,
+		"locks":         1,
+	}, collect())
+
+	err = store.SetGameStatus(ctx, game.ID, rules.GameStatusComplete)
+	require.NoError(t, err)
+	require.NoError(t, store.Unlock(ctx, game.ID, token))
+	assert.Equal(t, map[string]float64{
+		"games":         1,
+		"running_games": 0,
+		"frames":        3,
+		"locks":         0,
+	}, collect())
+}
+
 func TestMain(m *testing.M) {
 	redisURL := os.Getenv("REDIS_URL")
 	if len(redisURL) == 0 {
@@ -275,6 +1284,9 @@ var gameCases = []testCase{
 	{
 		game: &pb.Game{ID: uuid.NewV4().String(), Status: "Snek 🐍🐍🐍🐍🐍", SnakeTimeout: 100, Mode: "🐍🐍🐍🐍🐍"},
 	},
+	{
+		game: &pb.Game{ID: uuid.NewV4().String(), Status: "Test status", Width: 10, Height: 10, Ruleset: &pb.Ruleset{Name: "custom", MaxHealth: 50}},
+	},
 }
 
 var testFrames = []*pb.GameFrame{
@@ -298,3 +1310,144 @@ var testFrames = []*pb.GameFrame{
 		},
 	},
 }
+
+func TestMigrate(t *testing.T) {
+	// Migrate covers the whole keyspace, so seed a server of its own.
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()))
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	// An old game: the state without the fields next to it, and frames
+	// without a checksum.
+	old := &pb.Game{ID: uuid.NewV4().String(), Name: "old game", Status: string(rules.GameStatusComplete)}
+	state, err := proto.Marshal(old)
+	require.NoError(t, err)
+	require.NoError(t, store.client.HSet(gameKey(old.ID), "state", state).Err())
+	for _, f := range testFrames {
+		data, err := proto.Marshal(f)
+		require.NoError(t, err)
+		require.NoError(t, store.client.RPush(framesKey(old.ID), data).Err())
+	}
+
+	current := &pb.Game{ID: uuid.NewV4().String(), Name: "current game", Status: string(rules.GameStatusRunning)}
+	require.NoError(t, store.CreateGame(ctx, current, testFrames))
+	raw := func(id string) (map[string]string, []string) {
+		fields, err := store.client.HGetAll(gameKey(id)).Result()
+		require.NoError(t, err)
+		frames, err := store.client.LRange(framesKey(id), 0, -1).Result()
+		require.NoError(t, err)
+		return fields, frames
+	}
+	currentFields, currentFrames := raw(current.ID)
+
+	require.NoError(t, store.Migrate(ctx))
+
+	fields, frames := raw(old.ID)
+	assert.Equal(t, old.ID, fields["id"])
+	assert.Equal(t, old.Status, fields["status"])
+	assert.Equal(t, old.Name, fields["name"])
+	for _, data := range frames {
+		assert.Equal(t, byte(checksumMarker), data[0])
+	}
+	list, err := store.ListGameFrames(ctx, old.ID, len(testFrames), 0)
+	require.NoError(t, err)
+	assert.Equal(t, testFrames, list)
+	ids, err := store.FindGamesByName(ctx, "old")
+	require.NoError(t, err)
+	assert.Equal(t, []string{old.ID}, ids)
+
+	fields, frames = raw(current.ID)
+	assert.Equal(t, currentFields, fields)
+	assert.Equal(t, currentFrames, frames)
+
+	// A second run leaves everything as it is.
+	oldFields, oldFrames := raw(old.ID)
+	require.NoError(t, store.Migrate(ctx))
+	fields, frames = raw(old.ID)
+	assert.Equal(t, oldFields, fields)
+	assert.Equal(t, oldFrames, frames)
+	fields, frames = raw(current.ID)
+	assert.Equal(t, currentFields, fields)
+	assert.Equal(t, currentFrames, frames)
+}
+
+func TestStreamGameFrames(t *testing.T) {
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	frames := make([]*pb.GameFrame, 25)
+	for i := range frames {
+		frames[i] = &pb.GameFrame{Turn: int32(i)}
+	}
+	require.NoError(t, store.CreateGame(context.Background(), game, frames))
+
+	for _, batch := range []int{10, 25, 100} {
+		stream, errs := store.(*Store).StreamGameFrames(context.Background(), game.ID, batch)
+		streamed := []*pb.GameFrame{}
+		for f := range stream {
+			streamed = append(streamed, f)
+		}
+		require.NoError(t, <-errs, "batch %d", batch)
+		require.Equal(t, frames, streamed, "batch %d", batch)
+	}
+}
+
+func TestStreamGameFramesCancel(t *testing.T) {
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	require.NoError(t, store.CreateGame(context.Background(), game, testFrames))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, errs := store.(*Store).StreamGameFrames(ctx, game.ID, 1)
+	require.Equal(t, testFrames[0], <-stream)
+	cancel()
+	for range stream {
+	}
+	require.Equal(t, context.Canceled, <-errs)
+}
+
+func TestStreamGameFramesInvalidBatch(t *testing.T) {
+	stream, errs := store.(*Store).StreamGameFrames(context.Background(), uuid.NewV4().String(), 0)
+	_, ok := <-stream
+	require.False(t, ok)
+	require.Equal(t, controller.ErrZeroLimit, <-errs)
+}
+
+func TestFrameLimits(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+	store, err := NewStore(fmt.Sprintf("redis://%s", server.Addr()), WithFrameLimits(200, 2))
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	game := &pb.Game{ID: uuid.NewV4().String()}
+	require.NoError(t, store.CreateGame(ctx, game, testFrames[:1]))
+	snake := func(id string, length int) *pb.Snake {
+		s := &pb.Snake{ID: id, Health: 100}
+		for i := 0; i < length; i++ {
+			s.Body = append(s.Body, &pb.Point{X: int32(i), Y: 0})
+		}
+		return s
+	}
+
+	large := &pb.GameFrame{Turn: 1, Snakes: []*pb.Snake{snake("1", 100)}}
+	require.Equal(t, ErrFrameTooLarge, store.PushGameFrame(ctx, game.ID, "", large))
+	crowded := &pb.GameFrame{Turn: 1, Snakes: []*pb.Snake{snake("1", 1), snake("2", 1), snake("3", 1)}}
+	require.Equal(t, ErrFrameTooLarge, store.PushGameFrame(ctx, game.ID, "", crowded))
+	fits := &pb.GameFrame{Turn: 1, Snakes: []*pb.Snake{snake("1", 3), snake("2", 3)}}
+	require.Equal(t, ErrFrameTooLarge, store.PushGameFrames(ctx, game.ID, "", []*pb.GameFrame{fits, {Turn: 2, Snakes: large.Snakes}}))
+	err = store.WithTx(ctx, func(tx *controller.StoreTx) error {
+		tx.PushGameFrame(game.ID, "", large)
+		return nil
+	})
+	require.Equal(t, ErrFrameTooLarge, err)
+
+	frames, err := store.ListGameFrames(ctx, game.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, frames, 1, "nothing is pushed")
+
+	require.NoError(t, store.PushGameFrame(ctx, game.ID, "", fits))
+}
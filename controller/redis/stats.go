@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"strings"
+
+	"github.com/battlesnakeio/engine/rules"
+	"github.com/go-redis/redis"
+	"github.com/pkg/errors"
+)
+
+// statsScanCount is the SCAN COUNT hint used when walking the keyspace.
+const statsScanCount = 1000
+
+// StoreStats is a snapshot of what is held in the store. The counts are
+// approximate: they are gathered with SCAN, so keys created, expired or
+// deleted while the scan runs may or may not be counted.
+type StoreStats struct {
+	// Games is the number of games in the store.
+	Games int64
+	// RunningGames is the number of games with the running status.
+	RunningGames int64
+	// Frames is the total number of frames across all games.
+	Frames int64
+	// Locks is the number of games currently locked by a worker.
+	Locks int64
+}
+
+// Collect returns the stats keyed by metric name, so any exporter can surface
+// them without the store depending on a metrics library.
+func (s StoreStats) Collect() map[string]float64 {
+	return map[string]float64{
+		"games":         float64(s.Games),
+		"running_games": float64(s.RunningGames),
+		"frames":        float64(s.Frames),
+		"locks":         float64(s.Locks),
+	}
+}
+
+// Stats walks the keyspace and returns counts of games, running games, frames
+// and locks. It uses SCAN rather than KEYS so redis is never blocked, but the
+// cost is still O(N) in the number of keys, with one round trip per SCAN page
+// plus a pipeline of HGET and LLEN or HLEN calls. It is meant for admin pages,
+// not for anything on the hot path.
+func (rs *Store) Stats(c context.Context) (StoreStats, error) {
+	stats := StoreStats{}
+	pipe := rs.client.Pipeline()
+	var statuses []*redis.StringCmd
+	var lengths []*redis.IntCmd
+
+	iter := rs.client.Scan(0, "game:*", statsScanCount).Iterator()
+	for iter.Next() {
+		key := iter.Val()
+		switch {
+		case strings.HasSuffix(key, ":state"):
+			stats.Games++
+			statuses = append(statuses, pipe.HGet(key, "status"))
+		case strings.HasSuffix(key, ":frames"):
+			lengths = append(lengths, pipe.LLen(key))
+		case strings.HasSuffix(key, ":framesByTurn"):
+			lengths = append(lengths, pipe.HLen(key))
+		case strings.HasSuffix(key, ":locks"):
+			stats.Locks++
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return StoreStats{}, errors.Wrap(err, "unexpected redis error while scanning keys")
+	}
+
+	if len(statuses) > 0 || len(lengths) > 0 {
+		// A key may expire between the scan and the pipeline, which shows up
+		// as redis.Nil on its command and is simply not counted.
+		_, err := pipe.Exec()
+		if err != nil && err != redis.Nil {
+			return StoreStats{}, errors.Wrap(err, "unexpected redis error while gathering stats")
+		}
+	}
+	for _, s := range statuses {
+		if s.Val() == string(rules.GameStatusRunning) {
+			stats.RunningGames++
+		}
+	}
+	for _, l := range lengths {
+		stats.Frames += l.Val()
+	}
+
+	return stats, nil
+}
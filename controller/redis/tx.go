@@ -0,0 +1,154 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/battlesnakeio/engine/controller"
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/battlesnakeio/engine/rules"
+	"github.com/go-redis/redis"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// txGame is the state of a game as the writes of a transaction leave it.
+type txGame struct {
+	status    string
+	setStatus bool
+	count     int64
+	last      *pb.GameFrame
+	frames    []interface{}
+}
+
+// WithTx watches the keys of every game the writes of fn touch, checks the
+// writes against their current state and applies them in a single MULTI/EXEC.
+// The transaction fails if any of the keys changes in between. Games that are
+// completed are handed to the archiver afterwards, as SetGameStatus does.
+func (rs *Store) WithTx(c context.Context, fn func(tx *controller.StoreTx) error) error {
+	ops, err := controller.CollectTx(fn)
+	if err != nil {
+		return err
+	}
+	var keys []string
+	for _, op := range ops {
+		keys = append(keys, gameKey(op.GameID), gameLockKey(op.GameID), rs.frameKey(op.GameID))
+	}
+
+	var games map[string]*txGame
+	var completed []string
+	err = rs.client.Watch(func(tx *redis.Tx) error {
+		games = map[string]*txGame{}
+		completed = nil
+		for _, op := range ops {
+			g, err := rs.txGame(tx, games, op.GameID)
+			if err != nil {
+				return err
+			}
+			if op.Frame != nil {
+				if err := rs.txPushFrame(tx, g, op); err != nil {
+					return err
+				}
+				continue
+			}
+			if !rules.CanTransition(rules.GameStatus(g.status), op.Status) {
+				return controller.ErrInvalidTransition
+			}
+			if op.Status == rules.GameStatusComplete && g.status != string(op.Status) {
+				completed = append(completed, op.GameID)
+			}
+			g.status = string(op.Status)
+			g.setStatus = true
+		}
+
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			for id, g := range games {
+				if g.setStatus {
+					pipe.HSet(gameKey(id), "status", g.status)
+				}
+				if len(g.frames) > 0 {
+					rs.appendFrames(pipe, id, g.count-int64(len(g.frames)), g.frames)
+				}
+			}
+			return nil
+		})
+		return err
+	}, keys...)
+	switch err {
+	case nil:
+	case controller.ErrLockExpired, controller.ErrIsLocked, controller.ErrInvalidTransition, controller.ErrFrameConflict,
+		controller.ErrInvalidSequence, ErrFrameTooLarge:
+		return err
+	default:
+		return errors.Wrap(err, "unexpected redis error while applying transaction")
+	}
+
+	if rs.archiver == nil {
+		return nil
+	}
+	for _, id := range completed {
+		if err := rs.archiveGame(c, id); err != nil {
+			log.WithError(err).WithField("GameID", id).Error("unable to archive game, keeping it in redis")
+		}
+	}
+	return nil
+}
+
+// txGame returns the state of a game in a transaction, reading it on first
+// use.
+func (rs *Store) txGame(tx *redis.Tx, games map[string]*txGame, id string) (*txGame, error) {
+	if g, ok := games[id]; ok {
+		return g, nil
+	}
+	status, err := tx.HGet(gameKey(id), "status").Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	count, err := rs.frameCount(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	g := &txGame{status: status, count: count}
+	if count > 0 {
+		data, err := rs.frameAt(tx, id, count-1)
+		if err != nil {
+			return nil, err
+		}
+		g.last = &pb.GameFrame{}
+		if err := rs.unmarshalFrame(data, g.last); err != nil {
+			return nil, err
+		}
+	}
+	games[id] = g
+	return g, nil
+}
+
+// txPushFrame checks a frame push of a transaction the way PushGameFrame
+// does and queues it on the game.
+func (rs *Store) txPushFrame(tx *redis.Tx, g *txGame, op controller.TxOp) error {
+	token, err := tx.Get(gameLockKey(op.GameID)).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	if token != op.Token {
+		return fenceError(op.Token)
+	}
+	if g.last != nil {
+		if dup, err := controller.DuplicateFrame(g.last, op.Frame); dup || err != nil {
+			return err
+		}
+	}
+	if int64(op.Frame.Turn) != g.count {
+		return controller.ErrInvalidSequence
+	}
+	data, err := marshalFrame(op.Frame)
+	if err != nil {
+		return errors.Wrap(err, "frame marshalling error")
+	}
+	if err := rs.checkFrameLimits(op.Frame, data); err != nil {
+		return err
+	}
+	g.frames = append(g.frames, data)
+	g.last = op.Frame
+	g.count++
+	return nil
+}
@@ -24,6 +24,34 @@ var (
 	// ErrInvalidSequence is returned when a game tick is written with an
 	// invalid sequence.
 	ErrInvalidSequence = status.Error(codes.ResourceExhausted, "controller: invalid game tick sequence")
+	// ErrInvalidTurn is returned when a game is asked for a turn it does not
+	// have a frame for.
+	ErrInvalidTurn = status.Error(codes.OutOfRange, "controller: turn out of range")
+	// ErrInvalidTransition is returned when a game status is set to a status
+	// it is not allowed to move to from its current status.
+	ErrInvalidTransition = status.Error(codes.FailedPrecondition, "controller: invalid game status transition")
+	// ErrIsPaused is returned when frames are added to a paused game.
+	ErrIsPaused = status.Error(codes.FailedPrecondition, "controller: game is paused")
+	// ErrIsAborted is returned when frames are added to a game that was
+	// aborted.
+	ErrIsAborted = status.Error(codes.FailedPrecondition, "controller: game was aborted")
+	// ErrInProgress is returned when a game is changed after it started
+	// producing frames.
+	ErrInProgress = status.Error(codes.FailedPrecondition, "controller: game is in progress")
+	// ErrFrameConflict is returned when a frame is pushed for the turn of the
+	// last frame, but with different content.
+	ErrFrameConflict = status.Error(codes.AlreadyExists, "controller: a different frame exists for this turn")
+	// ErrGameExists is returned when a game is stored under an ID another
+	// game already has.
+	ErrGameExists = status.Error(codes.AlreadyExists, "controller: game already exists")
+	// ErrLockExpired is returned when a frame is written with a lock token
+	// that no longer holds the lock of the game.
+	ErrLockExpired = status.Error(codes.Aborted, "controller: lock expired")
+	// ErrZeroLimit is returned when frames are listed with a limit of 0.
+	ErrZeroLimit = status.Error(codes.InvalidArgument, "controller: limit is zero, at least one frame must be asked for")
+	// ErrNegativeLimit is returned when frames are listed with a negative
+	// limit.
+	ErrNegativeLimit = status.Error(codes.InvalidArgument, "controller: limit is negative")
 )
 
 // Store is the interface to the game store. It implements locking for workers
@@ -36,21 +64,90 @@ type Store interface {
 	// Unlock will unlock a game if it is locked and the token used to lock it
 	// is correct.
 	Unlock(ctx context.Context, key, token string) error
+	// ForceUnlock removes the lock on a game without checking the token. It
+	// is meant for recovery tooling that clears the lock of a worker that
+	// died, workers must use Unlock. ErrNotFound is returned when the game
+	// was not locked.
+	ForceUnlock(ctx context.Context, key string) error
+	// LockInfo returns who holds the lock of a game, ErrNotFound is returned
+	// when the game is not locked.
+	LockInfo(ctx context.Context, key string) (*LockInfo, error)
 	// PopGameID returns a new game that is unlocked and running. Workers call
 	// this method through the controller to find games to process.
 	PopGameID(context.Context) (string, error)
+	// PeekRunningGames returns up to count of the games PopGameID picks from,
+	// running and unlocked, without locking any of them. An empty slice is
+	// returned when there are none.
+	PeekRunningGames(ctx context.Context, count int) ([]string, error)
 	// SetGameStatus is used to set a specific game status. This operation
-	// should be atomic.
+	// should be atomic. ErrInvalidTransition is returned when the game may
+	// not move from its current status to the new one, see
+	// rules.CanTransition.
 	SetGameStatus(c context.Context, id string, status rules.GameStatus) error
 	// CreateGame will insert a game with the default game frames.
 	CreateGame(context.Context, *pb.Game, []*pb.GameFrame) error
-	// PushGameFrame will push a game frame onto the list of frames.
-	PushGameFrame(c context.Context, id string, t *pb.GameFrame) error
+	// PushGameFrame will push a game frame onto the list of frames. Pushing
+	// the last frame again is a no-op, so a worker that restarts and re-runs
+	// a tick does not duplicate it. A different frame for the turn of the
+	// last frame returns ErrFrameConflict. The write is fenced by token: it is
+	// rejected with ErrLockExpired unless token holds the lock of the game, so
+	// a delayed write from a worker that lost its lock can not slip through.
+	// An empty token only writes while nobody holds the lock, for tools and
+	// tests writing to a game no worker runs, otherwise ErrIsLocked is
+	// returned.
+	PushGameFrame(c context.Context, id, token string, t *pb.GameFrame) error
+	// PushGameFrames pushes a batch of frames in one step, e.g. to import a
+	// game. The first frame must be for the turn after the last stored frame
+	// and every other frame for the turn after the one before it, otherwise
+	// ErrInvalidSequence is returned and none of the frames are written. The
+	// write is fenced by token as for PushGameFrame. Frames that are already
+	// stored are not skipped, a batch can only be pushed once.
+	PushGameFrames(c context.Context, id, token string, frames []*pb.GameFrame) error
 	// ListGameFrames will list frames by an offset and limit, it supports
 	// negative offset.
 	ListGameFrames(c context.Context, id string, limit, offset int) ([]*pb.GameFrame, error)
+	// ListGameFramesReverse will list frames newest first by an offset and
+	// limit. An offset of 0 starts at the latest frame, a negative offset
+	// counts from the first frame instead.
+	ListGameFramesReverse(c context.Context, id string, limit, offset int) ([]*pb.GameFrame, error)
+	// ListGameFramesSince will list all frames with a turn greater than
+	// afterTurn. Polling clients use this to fetch only the frames they have
+	// not seen yet.
+	ListGameFramesSince(c context.Context, id string, afterTurn int) ([]*pb.GameFrame, error)
+	// RewindGame will drop every frame after toTurn so that toTurn becomes
+	// the last frame. A complete game is set back to running so it can be
+	// played on from that point.
+	RewindGame(c context.Context, id string, toTurn int) error
+	// CompactGame re-encodes a game and its frames in the current storage
+	// format and replaces the stored data in one step, so readers see either
+	// the old or the new data. Games already stored in the current format are
+	// left alone.
+	CompactGame(c context.Context, id string) error
+	// UpdateGame replaces the stored game, e.g. to correct its ruleset
+	// before it is played. The status is not changed, use SetGameStatus for
+	// that. Games that have frames after their initial frame are in progress
+	// and can not be updated, ErrInProgress is returned for those.
+	UpdateGame(c context.Context, game *pb.Game) error
 	// GetGame will fetch the game.
 	GetGame(context.Context, string) (*pb.Game, error)
+	// GetGameStatus will fetch only the status of a game, without reading
+	// the rest of it. ErrNotFound is returned for unknown games.
+	GetGameStatus(c context.Context, id string) (string, error)
+	// GetGameAndLastFrame will fetch the game together with its latest
+	// frame in a single call. The frame is nil if the game has no frames yet.
+	GetGameAndLastFrame(c context.Context, id string) (*pb.Game, *pb.GameFrame, error)
+	// WithTx calls fn to collect frame pushes and status changes and applies
+	// them atomically once fn returns, so a crash between two writes can not
+	// leave a game half updated. Nothing is written when fn or any of the
+	// writes fails, the error is returned.
+	WithTx(ctx context.Context, fn func(tx *StoreTx) error) error
+}
+
+// Archiver persists finished games to external storage, so stores can let go
+// of them. Stores that support archiving call it once, when a game is
+// completed.
+type Archiver interface {
+	Archive(ctx context.Context, gameID string, game *pb.Game, frames []*pb.GameFrame) error
 }
 
 // InMemStore returns an in memory implementation of the Store interface.
@@ -114,6 +211,26 @@ func (in *inmem) isLocked(key string) bool {
 	return ok && l.expires.After(time.Now())
 }
 
+func (in *inmem) holdsLock(key, token string) bool {
+	l, ok := in.locks[key]
+	return ok && l.token == token && l.expires.After(time.Now())
+}
+
+// fence returns the error a write to key with token is rejected with, see
+// Store.PushGameFrame.
+func (in *inmem) fence(key, token string) error {
+	if token != "" {
+		if !in.holdsLock(key, token) {
+			return ErrLockExpired
+		}
+		return nil
+	}
+	if in.isLocked(key) {
+		return ErrIsLocked
+	}
+	return nil
+}
+
 func (in *inmem) Unlock(ctx context.Context, key, token string) error {
 	in.lock.Lock()
 	defer in.lock.Unlock()
@@ -135,6 +252,33 @@ func (in *inmem) Unlock(ctx context.Context, key, token string) error {
 	return ErrIsLocked
 }
 
+func (in *inmem) ForceUnlock(ctx context.Context, key string) error {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+
+	l, ok := in.locks[key]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(in.locks, key)
+	// An expired lock no longer holds the game.
+	if l.expires.Before(time.Now()) {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (in *inmem) LockInfo(ctx context.Context, key string) (*LockInfo, error) {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+
+	if !in.isLocked(key) {
+		return nil, ErrNotFound
+	}
+	l := in.locks[key]
+	return NewLockInfo(l.token, l.expires), nil
+}
+
 func (in *inmem) PopGameID(ctx context.Context) (string, error) {
 	in.lock.Lock()
 	defer in.lock.Unlock()
@@ -149,6 +293,22 @@ func (in *inmem) PopGameID(ctx context.Context) (string, error) {
 	return "", ErrNotFound
 }
 
+func (in *inmem) PeekRunningGames(ctx context.Context, count int) ([]string, error) {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+
+	ids := []string{}
+	for id, g := range in.games {
+		if len(ids) >= count {
+			break
+		}
+		if !in.isLocked(id) && g.Status == string(rules.GameStatusRunning) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
 func (in *inmem) CreateGame(ctx context.Context, g *pb.Game, frames []*pb.GameFrame) error {
 	in.lock.Lock()
 	defer in.lock.Unlock()
@@ -161,31 +321,113 @@ func (in *inmem) SetGameStatus(ctx context.Context, id string, status rules.Game
 	in.lock.Lock()
 	defer in.lock.Unlock()
 	if g, ok := in.games[id]; ok {
+		if !rules.CanTransition(rules.GameStatus(g.Status), status) {
+			return ErrInvalidTransition
+		}
 		g.Status = string(status)
 		return nil
 	}
 	return ErrNotFound
 }
 
-func (in *inmem) PushGameFrame(ctx context.Context, id string, g *pb.GameFrame) error {
+func (in *inmem) PushGameFrame(ctx context.Context, id, token string, g *pb.GameFrame) error {
 	in.lock.Lock()
 	defer in.lock.Unlock()
-	frames := in.frames[id]
+	frames, err := in.appendFrame(in.frames[id], id, token, g)
+	if err != nil {
+		return err
+	}
+	in.frames[id] = frames
+	return nil
+}
+
+// appendFrame returns frames with g pushed onto them, after the checks of
+// PushGameFrame.
+func (in *inmem) appendFrame(frames []*pb.GameFrame, id, token string, g *pb.GameFrame) ([]*pb.GameFrame, error) {
+	if err := in.fence(id, token); err != nil {
+		return nil, err
+	}
 	if len(frames) > 0 {
 		last := frames[len(frames)-1]
+		if dup, err := DuplicateFrame(last, g); dup || err != nil {
+			return frames, err
+		}
 		if last.Turn+1 != g.Turn {
-			return ErrInvalidSequence
+			return nil, ErrInvalidSequence
 		}
 	} else {
 		if g.Turn != 0 {
-			return ErrInvalidSequence
+			return nil, ErrInvalidSequence
 		}
 	}
-	in.frames[id] = append(in.frames[id], g)
+	return append(frames, g), nil
+}
+
+func (in *inmem) PushGameFrames(ctx context.Context, id, token string, frames []*pb.GameFrame) error {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	if err := in.fence(id, token); err != nil {
+		return err
+	}
+	if err := CheckFrameSequence(nextTurn(in.frames[id]), frames); err != nil {
+		return err
+	}
+	in.frames[id] = append(in.frames[id], frames...)
+	return nil
+}
+
+// WithTx checks every write of fn against the state left by the writes before
+// it and only applies them, under the store lock, once all of them pass.
+func (in *inmem) WithTx(ctx context.Context, fn func(tx *StoreTx) error) error {
+	ops, err := CollectTx(fn)
+	if err != nil {
+		return err
+	}
+	in.lock.Lock()
+	defer in.lock.Unlock()
+
+	frames := map[string][]*pb.GameFrame{}
+	statuses := map[string]string{}
+	for _, op := range ops {
+		if op.Frame != nil {
+			current, ok := frames[op.GameID]
+			if !ok {
+				current = in.frames[op.GameID]
+			}
+			next, err := in.appendFrame(current, op.GameID, op.Token, op.Frame)
+			if err != nil {
+				return err
+			}
+			frames[op.GameID] = next
+			continue
+		}
+		current, ok := statuses[op.GameID]
+		if !ok {
+			g, ok := in.games[op.GameID]
+			if !ok {
+				return ErrNotFound
+			}
+			current = g.Status
+		}
+		if !rules.CanTransition(rules.GameStatus(current), op.Status) {
+			return ErrInvalidTransition
+		}
+		statuses[op.GameID] = string(op.Status)
+	}
+
+	for id, f := range frames {
+		in.frames[id] = f
+	}
+	for id, status := range statuses {
+		in.games[id].Status = status
+	}
 	return nil
 }
 
 func (in *inmem) ListGameFrames(ctx context.Context, id string, limit, offset int) ([]*pb.GameFrame, error) {
+	if err := ValidateLimit(limit); err != nil {
+		return nil, err
+	}
 	in.lock.Lock()
 	defer in.lock.Unlock()
 	if _, ok := in.games[id]; !ok {
@@ -207,6 +449,71 @@ func (in *inmem) ListGameFrames(ctx context.Context, id string, limit, offset in
 	return frames[offset : offset+limit], nil
 }
 
+func (in *inmem) ListGameFramesReverse(ctx context.Context, id string, limit, offset int) ([]*pb.GameFrame, error) {
+	if err := ValidateLimit(limit); err != nil {
+		return nil, err
+	}
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	if _, ok := in.games[id]; !ok {
+		return nil, ErrNotFound
+	}
+	return framesReverse(in.frames[id], limit, offset), nil
+}
+
+func (in *inmem) ListGameFramesSince(ctx context.Context, id string, afterTurn int) ([]*pb.GameFrame, error) {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	if _, ok := in.games[id]; !ok {
+		return nil, ErrNotFound
+	}
+	return framesSince(in.frames[id], afterTurn), nil
+}
+
+func (in *inmem) RewindGame(ctx context.Context, id string, toTurn int) error {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	g, ok := in.games[id]
+	if !ok {
+		return ErrNotFound
+	}
+	frames, err := framesThrough(in.frames[id], toTurn)
+	if err != nil {
+		return err
+	}
+	in.frames[id] = frames
+	if g.Status == string(rules.GameStatusComplete) {
+		g.Status = string(rules.GameStatusRunning)
+	}
+	return nil
+}
+
+// CompactGame has nothing to do, games are kept in memory as is.
+func (in *inmem) CompactGame(ctx context.Context, id string) error {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	if _, ok := in.games[id]; !ok {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (in *inmem) UpdateGame(ctx context.Context, game *pb.Game) error {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	g, ok := in.games[game.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if len(in.frames[game.ID]) > 1 {
+		return ErrInProgress
+	}
+	updated := proto.Clone(game).(*pb.Game)
+	updated.Status = g.Status
+	in.games[game.ID] = updated
+	return nil
+}
+
 func (in *inmem) GetGame(ctx context.Context, id string) (*pb.Game, error) {
 	in.lock.Lock()
 	defer in.lock.Unlock()
@@ -219,3 +526,128 @@ func (in *inmem) GetGame(ctx context.Context, id string) (*pb.Game, error) {
 	}
 	return nil, ErrNotFound
 }
+
+func (in *inmem) GetGameStatus(ctx context.Context, id string) (string, error) {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+
+	if g, ok := in.games[id]; ok {
+		return g.Status, nil
+	}
+	return "", ErrNotFound
+}
+
+func (in *inmem) GetGameAndLastFrame(ctx context.Context, id string) (*pb.Game, *pb.GameFrame, error) {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+
+	g, ok := in.games[id]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	var lastFrame *pb.GameFrame
+	if frames := in.frames[id]; len(frames) > 0 {
+		lastFrame = frames[len(frames)-1]
+	}
+	return proto.Clone(g).(*pb.Game), lastFrame, nil
+}
+
+// framesSince returns the frames with a turn greater than afterTurn. Frames
+// are stored in turn order, so everything after the first match is returned.
+func framesSince(frames []*pb.GameFrame, afterTurn int) []*pb.GameFrame {
+	for i, f := range frames {
+		if int(f.Turn) > afterTurn {
+			return frames[i:]
+		}
+	}
+	return nil
+}
+
+// ValidateLimit checks the limit frames are listed with. Stores call it
+// before anything else in ListGameFrames and ListGameFramesReverse, so a
+// zero or negative limit fails the same way for every store.
+func ValidateLimit(limit int) error {
+	switch {
+	case limit == 0:
+		return ErrZeroLimit
+	case limit < 0:
+		return ErrNegativeLimit
+	}
+	return nil
+}
+
+// framesReverse returns up to limit frames newest first, skipping the offset
+// newest frames. A negative offset counts from the first frame instead.
+func framesReverse(frames []*pb.GameFrame, limit, offset int) []*pb.GameFrame {
+	// end is the index after the newest frame to return.
+	end := len(frames) - offset
+	if offset < 0 {
+		end = -offset
+	}
+	start := end - limit
+	if start < 0 {
+		start = 0
+	}
+	if end > len(frames) {
+		end = len(frames)
+	}
+	if end <= start {
+		return nil
+	}
+	reversed := make([]*pb.GameFrame, 0, end-start)
+	for i := end - 1; i >= start; i-- {
+		reversed = append(reversed, frames[i])
+	}
+	return reversed
+}
+
+// DuplicateFrame reports whether next repeats last, the last stored frame of
+// a game. ErrFrameConflict is returned when both are for the same turn but
+// their content differs, ErrInvalidSequence when next is for an earlier turn.
+func DuplicateFrame(last, next *pb.GameFrame) (bool, error) {
+	if last == nil {
+		return false, nil
+	}
+	if next.Turn < last.Turn {
+		return false, ErrInvalidSequence
+	}
+	if last.Turn != next.Turn {
+		return false, nil
+	}
+	if !last.Equal(next) {
+		return false, ErrFrameConflict
+	}
+	return true, nil
+}
+
+// CheckFrameSequence checks that the first of frames is for turn next and
+// every other frame for the turn after the one before it. ErrInvalidSequence
+// is returned otherwise.
+func CheckFrameSequence(next int32, frames []*pb.GameFrame) error {
+	for i, f := range frames {
+		if f.Turn != next+int32(i) {
+			return ErrInvalidSequence
+		}
+	}
+	return nil
+}
+
+// nextTurn returns the turn of the frame that follows frames, 0 for a game
+// without frames.
+func nextTurn(frames []*pb.GameFrame) int32 {
+	if len(frames) == 0 {
+		return 0
+	}
+	return frames[len(frames)-1].Turn + 1
+}
+
+// framesThrough returns the frames up to and including the frame for toTurn.
+// ErrInvalidTurn is returned when there is no frame for toTurn.
+func framesThrough(frames []*pb.GameFrame, toTurn int) ([]*pb.GameFrame, error) {
+	for i, f := range frames {
+		if int(f.Turn) == toTurn {
+			return frames[:i+1], nil
+		}
+	}
+	return nil, ErrInvalidTurn
+}
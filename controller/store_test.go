@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -38,6 +39,51 @@ func testStoreLock(t *testing.T, s Store) {
 	require.Nil(t, err)
 }
 
+func testStoreForceUnlock(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	// Nothing to unlock.
+	err := s.ForceUnlock(ctx, "test")
+	require.Equal(t, ErrNotFound, err)
+
+	// The lock is removed without knowing the token.
+	_, err = s.Lock(ctx, "test", "")
+	require.Nil(t, err)
+	err = s.ForceUnlock(ctx, "test")
+	require.Nil(t, err)
+
+	// Anyone can lock the game again.
+	_, err = s.Lock(ctx, "test", "")
+	require.Nil(t, err)
+
+	err = s.ForceUnlock(ctx, "test")
+	require.Nil(t, err)
+	err = s.ForceUnlock(ctx, "test")
+	require.Equal(t, ErrNotFound, err)
+}
+
+func testStoreLockInfo(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	_, err := s.LockInfo(ctx, "test")
+	require.Equal(t, ErrNotFound, err)
+
+	token := NewLockToken("host-1")
+	tok, err := s.Lock(ctx, "test", token)
+	require.Nil(t, err)
+	info, err := s.LockInfo(ctx, "test")
+	require.Nil(t, err)
+	require.Equal(t, token, info.Token)
+	require.Equal(t, "host-1", info.WorkerID)
+	require.True(t, info.Expires.After(time.Now()))
+
+	// Unlocking still needs the whole token.
+	require.Equal(t, ErrIsLocked, s.Unlock(ctx, "test", "host-1"))
+	require.Nil(t, s.Unlock(ctx, "test", tok))
+	_, err = s.LockInfo(ctx, "test")
+	require.Equal(t, ErrNotFound, err)
+}
+
 func testStoreLockExpiry(t *testing.T, s Store) {
 	ctx := context.Background()
 
@@ -96,6 +142,46 @@ func testStoreGames(t *testing.T, s Store) {
 	require.NotNil(t, err)
 }
 
+func testStorePeekRunningGames(t *testing.T, s Store) {
+	ctx := context.Background()
+	ids, err := s.PeekRunningGames(ctx, 10)
+	require.Nil(t, err)
+	require.Equal(t, []string{}, ids)
+
+	for _, g := range []*pb.Game{
+		{ID: "a", Status: string(rules.GameStatusRunning)},
+		{ID: "b", Status: string(rules.GameStatusRunning)},
+		{ID: "c", Status: string(rules.GameStatusPaused)},
+	} {
+		require.Nil(t, s.CreateGame(ctx, g, nil))
+	}
+	ids, err = s.PeekRunningGames(ctx, 10)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"a", "b"}, ids)
+	ids, err = s.PeekRunningGames(ctx, 1)
+	require.Nil(t, err)
+	require.Len(t, ids, 1)
+
+	// Peeking leaves the games unlocked, locked games are left out.
+	_, err = s.Lock(ctx, "a", "")
+	require.Nil(t, err)
+	ids, err = s.PeekRunningGames(ctx, 10)
+	require.Nil(t, err)
+	require.Equal(t, []string{"b"}, ids)
+}
+
+func testStoreGameRuleset(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	ruleset := &pb.Ruleset{Name: "custom", MaxHealth: 50}
+	err := s.CreateGame(ctx, &pb.Game{ID: "test", Ruleset: ruleset}, nil)
+	require.Nil(t, err)
+
+	g, err := s.GetGame(ctx, "test")
+	require.Nil(t, err)
+	require.Equal(t, ruleset, g.Ruleset)
+}
+
 func testStoreGameFrames(t *testing.T, s Store) {
 	ctx := context.Background()
 
@@ -117,7 +203,7 @@ func testStoreGameFrames(t *testing.T, s Store) {
 	require.Equal(t, 0, len(frames))
 
 	// Push a game frame.
-	err = s.PushGameFrame(ctx, "test", &pb.GameFrame{})
+	err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{})
 	require.Nil(t, err)
 
 	// Read the game frames.
@@ -136,6 +222,348 @@ func testStoreGameFrames(t *testing.T, s Store) {
 	require.Equal(t, 0, len(frames))
 }
 
+func testStoreGameFramesSince(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	err := s.CreateGame(ctx, &pb.Game{ID: "test", Status: string(rules.GameStatusRunning)}, nil)
+	require.Nil(t, err)
+
+	// Unknown game.
+	_, err = s.ListGameFramesSince(ctx, "test22", 0)
+	require.Equal(t, ErrNotFound, err)
+
+	for turn := int32(0); turn < 3; turn++ {
+		err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: turn})
+		require.Nil(t, err)
+	}
+
+	// Everything from the start.
+	frames, err := s.ListGameFramesSince(ctx, "test", -1)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(frames))
+
+	// Only the new frames.
+	frames, err = s.ListGameFramesSince(ctx, "test", 0)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(frames))
+	require.Equal(t, int32(1), frames[0].Turn)
+	require.Equal(t, int32(2), frames[1].Turn)
+
+	// Caught up.
+	frames, err = s.ListGameFramesSince(ctx, "test", 2)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(frames))
+}
+
+func testStoreGameFramesReverse(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	_, err := s.ListGameFramesReverse(ctx, "test22", 1, 0)
+	require.Equal(t, ErrNotFound, err)
+
+	err = s.CreateGame(ctx, &pb.Game{ID: "test", Status: string(rules.GameStatusRunning)}, nil)
+	require.Nil(t, err)
+	frames, err := s.ListGameFramesReverse(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(frames))
+
+	for turn := int32(0); turn < 5; turn++ {
+		err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: turn})
+		require.Nil(t, err)
+	}
+	turns := func(frames []*pb.GameFrame) []int32 {
+		var turns []int32
+		for _, f := range frames {
+			turns = append(turns, f.Turn)
+		}
+		return turns
+	}
+
+	frames, err = s.ListGameFramesReverse(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Equal(t, []int32{4, 3, 2, 1, 0}, turns(frames))
+
+	// Page back from the newest frame.
+	frames, err = s.ListGameFramesReverse(ctx, "test", 2, 0)
+	require.Nil(t, err)
+	require.Equal(t, []int32{4, 3}, turns(frames))
+	frames, err = s.ListGameFramesReverse(ctx, "test", 2, 2)
+	require.Nil(t, err)
+	require.Equal(t, []int32{2, 1}, turns(frames))
+	frames, err = s.ListGameFramesReverse(ctx, "test", 2, 4)
+	require.Nil(t, err)
+	require.Equal(t, []int32{0}, turns(frames))
+	frames, err = s.ListGameFramesReverse(ctx, "test", 2, 5)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(frames))
+
+	// A negative offset counts from the first frame.
+	frames, err = s.ListGameFramesReverse(ctx, "test", 2, -2)
+	require.Nil(t, err)
+	require.Equal(t, []int32{1, 0}, turns(frames))
+	frames, err = s.ListGameFramesReverse(ctx, "test", 2, -7)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(frames))
+}
+
+func testStoreUpdateGame(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	err := s.UpdateGame(ctx, &pb.Game{ID: "test"})
+	require.Equal(t, ErrNotFound, err)
+
+	err = s.CreateGame(ctx, &pb.Game{ID: "test", Status: string(rules.GameStatusStopped), Width: 5}, []*pb.GameFrame{{Turn: 0}})
+	require.Nil(t, err)
+
+	// The status is kept, it only changes through SetGameStatus.
+	err = s.UpdateGame(ctx, &pb.Game{ID: "test", Status: string(rules.GameStatusComplete), Width: 7})
+	require.Nil(t, err)
+	g, err := s.GetGame(ctx, "test")
+	require.Nil(t, err)
+	require.Equal(t, int32(7), g.Width)
+	require.Equal(t, string(rules.GameStatusStopped), g.Status)
+
+	// Once the game played a turn it is in progress.
+	err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: 1})
+	require.Nil(t, err)
+	err = s.UpdateGame(ctx, &pb.Game{ID: "test", Width: 9})
+	require.Equal(t, ErrInProgress, err)
+	g, err = s.GetGame(ctx, "test")
+	require.Nil(t, err)
+	require.Equal(t, int32(7), g.Width)
+}
+
+func testStoreDuplicateFrame(t *testing.T, s Store) {
+	ctx := context.Background()
+	err := s.CreateGame(ctx, &pb.Game{ID: "test"}, []*pb.GameFrame{{Turn: 0}})
+	require.Nil(t, err)
+	err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: 1, Food: []*pb.Point{{X: 1, Y: 1}}})
+	require.Nil(t, err)
+
+	// Pushing the last frame again is a no-op.
+	err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: 1, Food: []*pb.Point{{X: 1, Y: 1}}})
+	require.Nil(t, err)
+	frames, err := s.ListGameFrames(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Len(t, frames, 2)
+
+	// A different frame for the same turn is a conflict.
+	err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: 1, Food: []*pb.Point{{X: 2, Y: 2}}})
+	require.Equal(t, ErrFrameConflict, err)
+	frames, err = s.ListGameFrames(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Len(t, frames, 2)
+	require.Equal(t, int32(1), frames[1].Food[0].X)
+
+	// An earlier turn is not appended again.
+	err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: 0})
+	require.Equal(t, ErrInvalidSequence, err)
+	frames, err = s.ListGameFrames(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Len(t, frames, 2)
+}
+
+func testStoreFencedPush(t *testing.T, s Store) {
+	ctx := context.Background()
+	err := s.CreateGame(ctx, &pb.Game{ID: "test"}, []*pb.GameFrame{{Turn: 0}})
+	require.Nil(t, err)
+
+	stale, err := s.Lock(ctx, "test", "")
+	require.Nil(t, err)
+	err = s.PushGameFrame(ctx, "test", stale, &pb.GameFrame{Turn: 1})
+	require.Nil(t, err)
+
+	// The lock is lost and taken by another writer, a delayed write from the
+	// old holder is rejected.
+	require.Nil(t, s.ForceUnlock(ctx, "test"))
+	current, err := s.Lock(ctx, "test", "")
+	require.Nil(t, err)
+	err = s.PushGameFrame(ctx, "test", stale, &pb.GameFrame{Turn: 2})
+	require.Equal(t, ErrLockExpired, err)
+	// A write without a token can not skip the lock either.
+	err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: 2})
+	require.Equal(t, ErrIsLocked, err)
+	err = s.PushGameFrames(ctx, "test", "", []*pb.GameFrame{{Turn: 2}})
+	require.Equal(t, ErrIsLocked, err)
+	err = s.PushGameFrame(ctx, "test", current, &pb.GameFrame{Turn: 2})
+	require.Nil(t, err)
+
+	// Without any lock a token write is rejected too.
+	require.Nil(t, s.Unlock(ctx, "test", current))
+	err = s.PushGameFrame(ctx, "test", current, &pb.GameFrame{Turn: 3})
+	require.Equal(t, ErrLockExpired, err)
+
+	frames, err := s.ListGameFrames(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Len(t, frames, 3)
+}
+
+func testStoreTx(t *testing.T, s Store) {
+	ctx := context.Background()
+	game := &pb.Game{ID: "test", Status: string(rules.GameStatusRunning)}
+	err := s.CreateGame(ctx, game, []*pb.GameFrame{{Turn: 0}})
+	require.Nil(t, err)
+	token, err := s.Lock(ctx, "test", "")
+	require.Nil(t, err)
+
+	requireUnchanged := func() {
+		g, err := s.GetGame(ctx, "test")
+		require.Nil(t, err)
+		require.Equal(t, string(rules.GameStatusRunning), g.Status)
+		frames, err := s.ListGameFrames(ctx, "test", 10, 0)
+		require.Nil(t, err)
+		require.Len(t, frames, 1)
+	}
+
+	// A failure part way through fn writes nothing.
+	failure := errors.New("failed")
+	err = s.WithTx(ctx, func(tx *StoreTx) error {
+		tx.PushGameFrame("test", token, &pb.GameFrame{Turn: 1})
+		tx.SetGameStatus("test", rules.GameStatusComplete)
+		return failure
+	})
+	require.Equal(t, failure, err)
+	requireUnchanged()
+
+	// Neither does a write that is rejected after an accepted one.
+	err = s.WithTx(ctx, func(tx *StoreTx) error {
+		tx.PushGameFrame("test", token, &pb.GameFrame{Turn: 1})
+		tx.SetGameStatus("test", rules.GameStatusComplete)
+		tx.SetGameStatus("test", rules.GameStatusRunning)
+		return nil
+	})
+	require.Equal(t, ErrInvalidTransition, err)
+	requireUnchanged()
+	err = s.WithTx(ctx, func(tx *StoreTx) error {
+		tx.SetGameStatus("test", rules.GameStatusComplete)
+		tx.PushGameFrame("test", "stale", &pb.GameFrame{Turn: 1})
+		return nil
+	})
+	require.Equal(t, ErrLockExpired, err)
+	requireUnchanged()
+	err = s.WithTx(ctx, func(tx *StoreTx) error {
+		tx.PushGameFrame("test", "", &pb.GameFrame{Turn: 1})
+		return nil
+	})
+	require.Equal(t, ErrIsLocked, err)
+	requireUnchanged()
+
+	err = s.WithTx(ctx, func(tx *StoreTx) error {
+		tx.PushGameFrame("test", token, &pb.GameFrame{Turn: 1})
+		tx.SetGameStatus("test", rules.GameStatusComplete)
+		return nil
+	})
+	require.Nil(t, err)
+	g, err := s.GetGame(ctx, "test")
+	require.Nil(t, err)
+	require.Equal(t, string(rules.GameStatusComplete), g.Status)
+	frames, err := s.ListGameFrames(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Len(t, frames, 2)
+}
+
+func testStoreRewindGame(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	err := s.RewindGame(ctx, "test", 0)
+	require.Equal(t, ErrNotFound, err)
+
+	err = s.CreateGame(ctx, &pb.Game{ID: "test", Status: string(rules.GameStatusComplete)}, nil)
+	require.Nil(t, err)
+	for turn := int32(0); turn < 3; turn++ {
+		err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: turn})
+		require.Nil(t, err)
+	}
+
+	err = s.RewindGame(ctx, "test", 3)
+	require.Equal(t, ErrInvalidTurn, err)
+	err = s.RewindGame(ctx, "test", -1)
+	require.Equal(t, ErrInvalidTurn, err)
+
+	err = s.RewindGame(ctx, "test", 1)
+	require.Nil(t, err)
+	frames, err := s.ListGameFrames(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(frames))
+	require.Equal(t, int32(1), frames[1].Turn)
+
+	g, err := s.GetGame(ctx, "test")
+	require.Nil(t, err)
+	require.Equal(t, string(rules.GameStatusRunning), g.Status)
+
+	// Play on from the rewound turn.
+	err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: 2})
+	require.Nil(t, err)
+}
+
+func testStoreGamePause(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	err := s.CreateGame(ctx, &pb.Game{ID: "test", Status: string(rules.GameStatusRunning)}, nil)
+	require.Nil(t, err)
+
+	err = s.SetGameStatus(ctx, "test", rules.GameStatusPaused)
+	require.Nil(t, err)
+	_, err = s.PopGameID(ctx)
+	require.Equal(t, ErrNotFound, err)
+
+	err = s.SetGameStatus(ctx, "test", rules.GameStatusRunning)
+	require.Nil(t, err)
+	id, err := s.PopGameID(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "test", id)
+
+	err = s.SetGameStatus(ctx, "test", rules.GameStatusComplete)
+	require.Nil(t, err)
+	err = s.SetGameStatus(ctx, "test", rules.GameStatusPaused)
+	require.Equal(t, ErrInvalidTransition, err)
+	err = s.SetGameStatus(ctx, "test", rules.GameStatusRunning)
+	require.Equal(t, ErrInvalidTransition, err)
+}
+
+func testStoreGameStatus(t *testing.T, s Store) {
+	ctx := context.Background()
+	_, err := s.GetGameStatus(ctx, "test")
+	require.Equal(t, ErrNotFound, err)
+
+	err = s.CreateGame(ctx, &pb.Game{ID: "test", Status: string(rules.GameStatusStopped)}, nil)
+	require.Nil(t, err)
+	for _, status := range []rules.GameStatus{
+		rules.GameStatusRunning,
+		rules.GameStatusPaused,
+		rules.GameStatusComplete,
+	} {
+		require.Nil(t, s.SetGameStatus(ctx, "test", status))
+		current, err := s.GetGameStatus(ctx, "test")
+		require.Nil(t, err)
+		require.Equal(t, string(status), current)
+	}
+}
+
+func testStoreGameAndLastFrame(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	_, _, err := s.GetGameAndLastFrame(ctx, "test")
+	require.Equal(t, ErrNotFound, err)
+
+	err = s.CreateGame(ctx, &pb.Game{ID: "test", Status: string(rules.GameStatusRunning)}, nil)
+	require.Nil(t, err)
+
+	g, f, err := s.GetGameAndLastFrame(ctx, "test")
+	require.Nil(t, err)
+	require.Equal(t, "test", g.ID)
+	require.Nil(t, f)
+
+	for turn := int32(0); turn < 3; turn++ {
+		err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: turn})
+		require.Nil(t, err)
+	}
+	g, f, err = s.GetGameAndLastFrame(ctx, "test")
+	require.Nil(t, err)
+	require.Equal(t, string(rules.GameStatusRunning), g.Status)
+	require.Equal(t, int32(2), f.Turn)
+}
+
 func testStoreConcurrentWriters(t *testing.T, s Store) {
 	ctx := context.Background()
 
@@ -165,8 +593,92 @@ func testStoreConcurrentWriters(t *testing.T, s Store) {
 	require.Equal(t, uint32(1), ok)
 }
 
+func testStoreCompactGame(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	err := s.CompactGame(ctx, "test")
+	require.Equal(t, ErrNotFound, err)
+
+	err = s.CreateGame(ctx, &pb.Game{ID: "test", Status: string(rules.GameStatusRunning)}, nil)
+	require.Nil(t, err)
+	for turn := int32(0); turn < 3; turn++ {
+		err = s.PushGameFrame(ctx, "test", "", &pb.GameFrame{Turn: turn})
+		require.Nil(t, err)
+	}
+
+	// Compacting twice is fine, the second time there is nothing to do.
+	require.Nil(t, s.CompactGame(ctx, "test"))
+	require.Nil(t, s.CompactGame(ctx, "test"))
+	frames, err := s.ListGameFrames(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(frames))
+	require.Equal(t, int32(2), frames[2].Turn)
+}
+
+func testStorePushGameFrames(t *testing.T, s Store) {
+	ctx := context.Background()
+	err := s.CreateGame(ctx, &pb.Game{ID: "test"}, []*pb.GameFrame{{Turn: 0}})
+	require.Nil(t, err)
+
+	// A batch with a gap is rejected entirely.
+	err = s.PushGameFrames(ctx, "test", "", []*pb.GameFrame{{Turn: 1}, {Turn: 2}, {Turn: 4}})
+	require.Equal(t, ErrInvalidSequence, err)
+	err = s.PushGameFrames(ctx, "test", "", []*pb.GameFrame{{Turn: 2}, {Turn: 3}})
+	require.Equal(t, ErrInvalidSequence, err)
+	frames, err := s.ListGameFrames(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Len(t, frames, 1)
+
+	token, err := s.Lock(ctx, "test", "")
+	require.Nil(t, err)
+	err = s.PushGameFrames(ctx, "test", "stale", []*pb.GameFrame{{Turn: 1}})
+	require.Equal(t, ErrLockExpired, err)
+
+	err = s.PushGameFrames(ctx, "test", token, []*pb.GameFrame{{Turn: 1}, {Turn: 2}, {Turn: 3}})
+	require.Nil(t, err)
+	frames, err = s.ListGameFrames(ctx, "test", 10, 0)
+	require.Nil(t, err)
+	require.Len(t, frames, 4)
+	require.Equal(t, int32(3), frames[3].Turn)
+}
+
+func testStoreListLimit(t *testing.T, s Store) {
+	ctx := context.Background()
+	err := s.CreateGame(ctx, &pb.Game{ID: "test"}, []*pb.GameFrame{{Turn: 0}})
+	require.Nil(t, err)
+
+	_, err = s.ListGameFrames(ctx, "test", 0, 0)
+	require.Equal(t, ErrZeroLimit, err)
+	_, err = s.ListGameFrames(ctx, "test", -5, 0)
+	require.Equal(t, ErrNegativeLimit, err)
+	_, err = s.ListGameFramesReverse(ctx, "test", 0, 0)
+	require.Equal(t, ErrZeroLimit, err)
+	_, err = s.ListGameFramesReverse(ctx, "test", -5, 0)
+	require.Equal(t, ErrNegativeLimit, err)
+	// The limit is checked before the game is looked up.
+	_, err = s.ListGameFrames(ctx, "missing", -5, 0)
+	require.Equal(t, ErrNegativeLimit, err)
+}
+
 func TestStore_InMem_Lock(t *testing.T)              { testStoreLock(t, InMemStore()) }
+func TestStore_InMem_ForceUnlock(t *testing.T)       { testStoreForceUnlock(t, InMemStore()) }
 func TestStore_InMem_LockExpiry(t *testing.T)        { testStoreLockExpiry(t, InMemStore()) }
+func TestStore_InMem_LockInfo(t *testing.T)          { testStoreLockInfo(t, InMemStore()) }
 func TestStore_InMem_Games(t *testing.T)             { testStoreGames(t, InMemStore()) }
+func TestStore_InMem_PeekRunningGames(t *testing.T)  { testStorePeekRunningGames(t, InMemStore()) }
+func TestStore_InMem_GameRuleset(t *testing.T)       { testStoreGameRuleset(t, InMemStore()) }
 func TestStore_InMem_GameFrames(t *testing.T)        { testStoreGameFrames(t, InMemStore()) }
+func TestStore_InMem_GameFramesSince(t *testing.T)   { testStoreGameFramesSince(t, InMemStore()) }
+func TestStore_InMem_GameFramesReverse(t *testing.T) { testStoreGameFramesReverse(t, InMemStore()) }
+func TestStore_InMem_RewindGame(t *testing.T)        { testStoreRewindGame(t, InMemStore()) }
+func TestStore_InMem_DuplicateFrame(t *testing.T)    { testStoreDuplicateFrame(t, InMemStore()) }
+func TestStore_InMem_FencedPush(t *testing.T)        { testStoreFencedPush(t, InMemStore()) }
+func TestStore_InMem_UpdateGame(t *testing.T)        { testStoreUpdateGame(t, InMemStore()) }
+func TestStore_InMem_CompactGame(t *testing.T)       { testStoreCompactGame(t, InMemStore()) }
+func TestStore_InMem_GamePause(t *testing.T)         { testStoreGamePause(t, InMemStore()) }
+func TestStore_InMem_GameAndLastFrame(t *testing.T)  { testStoreGameAndLastFrame(t, InMemStore()) }
+func TestStore_InMem_GameStatus(t *testing.T)        { testStoreGameStatus(t, InMemStore()) }
 func TestStore_InMem_ConcurrentWriters(t *testing.T) { testStoreConcurrentWriters(t, InMemStore()) }
+func TestStore_InMem_Tx(t *testing.T)                { testStoreTx(t, InMemStore()) }
+func TestStore_InMem_ListLimit(t *testing.T)         { testStoreListLimit(t, InMemStore()) }
+func TestStore_InMem_PushGameFrames(t *testing.T)    { testStorePushGameFrames(t, InMemStore()) }
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/battlesnakeio/engine/rules"
+)
+
+// StoreTx collects the writes of a Store.WithTx call. Nothing is written
+// while the function passed to WithTx runs, the store applies the writes
+// together once it returns without error, or not at all.
+type StoreTx struct {
+	ops []TxOp
+}
+
+// TxOp is a single write of a transaction. Exactly one of Frame and Status is
+// set.
+type TxOp struct {
+	GameID string
+	// Token fences a frame push the same way it does for PushGameFrame.
+	Token  string
+	Frame  *pb.GameFrame
+	Status rules.GameStatus
+}
+
+// PushGameFrame adds a frame push to the transaction, see
+// Store.PushGameFrame.
+func (tx *StoreTx) PushGameFrame(id, token string, f *pb.GameFrame) {
+	tx.ops = append(tx.ops, TxOp{GameID: id, Token: token, Frame: f})
+}
+
+// SetGameStatus adds a status change to the transaction, see
+// Store.SetGameStatus.
+func (tx *StoreTx) SetGameStatus(id string, status rules.GameStatus) {
+	tx.ops = append(tx.ops, TxOp{GameID: id, Status: status})
+}
+
+// Ops returns the writes of the transaction in the order they were added.
+func (tx *StoreTx) Ops() []TxOp {
+	return tx.ops
+}
+
+// CollectTx runs fn on a new transaction and returns its writes. Stores call
+// it at the start of WithTx, an error from fn is returned as is.
+func CollectTx(fn func(tx *StoreTx) error) ([]TxOp, error) {
+	tx := &StoreTx{}
+	if err := fn(tx); err != nil {
+		return nil, err
+	}
+	return tx.Ops(), nil
+}
@@ -2,9 +2,11 @@ package rules
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"io/ioutil"
+	"net/http"
 	"sync"
 	"time"
 
@@ -40,10 +42,6 @@ func gatherAllSnakeResponses(multiReq multiSnakeRequest) []snakeResponse {
 	return gatherSnakeResponses(multiReq, multiReq.frame.Snakes)
 }
 
-func gatherAliveSnakeResponses(multiReq multiSnakeRequest) []snakeResponse {
-	return gatherSnakeResponses(multiReq, multiReq.frame.AliveSnakes())
-}
-
 func gatherSnakeResponses(multiReq multiSnakeRequest, snakes []*pb.Snake) []snakeResponse {
 	respChan := make(chan snakeResponse, len(multiReq.frame.Snakes))
 	wg := sync.WaitGroup{}
@@ -52,7 +50,7 @@ func gatherSnakeResponses(multiReq multiSnakeRequest, snakes []*pb.Snake) []snak
 		if !isValidURL(snake.URL) {
 			respChan <- snakeResponse{
 				snake: snake,
-				err:   errors.New("invalid snake URL: " + snake.URL),
+				err:   fmt.Errorf("%w: %s", ErrInvalidSnakeURL, snake.URL),
 			}
 			continue
 		}
@@ -80,28 +78,38 @@ func gatherSnakeResponses(multiReq multiSnakeRequest, snakes []*pb.Snake) []snak
 }
 
 func postToSnakeServer(req snakePostRequest, resp chan<- snakeResponse) {
-	buf := bytes.NewBuffer(req.data)
-	netClient := createClient(req.options.timeout)
-	postURL := getURL(req.options.snake.URL, req.options.url)
-	postResponse, err := netClient.Post(postURL, "application/json", buf)
+	responseData, _, err := postSnakeData(context.Background(), req.options.snake, req.options.url, req.options.timeout, req.data)
+	resp <- snakeResponse{
+		snake: req.options.snake,
+		data:  responseData,
+		err:   err,
+	}
+}
+
+// postSnakeData POSTs data to the given path of the snake's server and returns
+// the response body and status code. The request is cancelled when ctx is
+// done or, when it is not zero, once timeout has passed.
+func postSnakeData(ctx context.Context, snake *pb.Snake, path string, timeout time.Duration, data []byte) ([]byte, int, error) {
+	postURL := getURL(snake.URL, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	netClient := createClient(timeout)
+	postResponse, err := netClient.Do(req)
 	if err != nil {
 		log.WithError(err).WithFields(log.Fields{
 			"url": postURL,
-			"id":  req.options.snake.ID,
+			"id":  snake.ID,
 		}).Error("error POSTing to snake")
-		resp <- snakeResponse{
-			snake: req.options.snake,
-			err:   err,
-		}
-		return
+		return nil, 0, err
 	}
+	// The body is read to the end and closed, so the connection is reused.
+	defer postResponse.Body.Close()
 
-	responseData, err := ioutil.ReadAll(postResponse.Body)
-	resp <- snakeResponse{
-		snake: req.options.snake,
-		data:  responseData,
-		err:   err,
-	}
+	body, err := ioutil.ReadAll(postResponse.Body)
+	return body, postResponse.StatusCode, err
 }
 
 func getSnakeResponse(options snakePostOptions, game *pb.Game, frame *pb.GameFrame, resp chan<- snakeResponse) {
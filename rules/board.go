@@ -0,0 +1,181 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+)
+
+// Boards are written as text by RenderFrame and read back by ParseBoard, so
+// tests can set up a scenario by drawing it. Every line is a row of the board,
+// from the top, and holds one token per square, separated by spaces:
+//
+//	.  an empty square
+//	*  food
+//	x  a hazard
+//	A0 segment 0, the head, of snake A. A1 is the segment after it, and so
+//	   on. Snakes are named by a single capital letter.
+//
+// A token lists everything on its square: A1A2 is a tail stacked on the
+// segment before it, x* is food on a hazard and xx a stacked hazard. Blank
+// lines around the board are ignored, so a spec can be written as a raw
+// string literal:
+//
+//	. . * .
+//	. A0 A1 .
+//	x . B0B1B2 .
+
+// RenderFrame writes the board of frame as text, see ParseBoard for the
+// format. Alive snakes are named A, B, C and so on in the order of the frame,
+// dead snakes are left out. Columns are padded to the same width.
+func RenderFrame(game *pb.Game, frame *pb.GameFrame) string {
+	cells := make([][]string, game.Height)
+	for y := range cells {
+		cells[y] = make([]string, game.Width)
+	}
+	add := func(p *pb.Point, item string) {
+		if !deathByOutOfBounds(p, game.Width, game.Height) {
+			cells[p.Y][p.X] += item
+		}
+	}
+	for i, s := range frame.AliveSnakes() {
+		name := string(rune('A' + i))
+		for j, p := range s.Body {
+			add(p, name+strconv.Itoa(j))
+		}
+	}
+	for _, h := range frame.Hazards {
+		add(h, "x")
+	}
+	for _, f := range frame.Food {
+		add(f, "*")
+	}
+
+	width := 1
+	for _, row := range cells {
+		for _, c := range row {
+			if len(c) > width {
+				width = len(c)
+			}
+		}
+	}
+	lines := make([]string, len(cells))
+	for y, row := range cells {
+		tokens := make([]string, len(row))
+		for x, c := range row {
+			if c == "" {
+				c = "."
+			}
+			tokens[x] = c + strings.Repeat(" ", width-len(c))
+		}
+		lines[y] = strings.TrimRight(strings.Join(tokens, " "), " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// ParseBoard reads a board written as text, the inverse of RenderFrame, into
+// a game and its frame. The game is as wide and high as the board and is
+// played with the standard ruleset. Snakes get their letter as ID, full
+// health, and are listed in order of their letter. ErrInvalidBoard is
+// returned for a spec that is not a board: rows of different widths, unknown
+// tokens, or snakes with missing or disconnected segments.
+func ParseBoard(spec string) (*pb.Game, *pb.GameFrame, error) {
+	lines := strings.Split(strings.Trim(spec, "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) == "" {
+		return nil, nil, fmt.Errorf("%w: empty board", ErrInvalidBoard)
+	}
+	game := &pb.Game{
+		Height:         int32(len(lines)),
+		Status:         string(GameStatusStopped),
+		Ruleset:        StandardRuleset(),
+		RulesetVersion: CurrentRulesetVersion,
+	}
+	frame := &pb.GameFrame{}
+	segments := map[string]map[int]*pb.Point{}
+	for y, line := range lines {
+		tokens := strings.Fields(line)
+		if y == 0 {
+			game.Width = int32(len(tokens))
+		}
+		if int32(len(tokens)) != game.Width {
+			return nil, nil, fmt.Errorf("%w: row %d is %d squares wide, not %d", ErrInvalidBoard, y, len(tokens), game.Width)
+		}
+		for x, token := range tokens {
+			p := &pb.Point{X: int32(x), Y: int32(y)}
+			if err := parseSquare(token, p, frame, segments); err != nil {
+				return nil, nil, fmt.Errorf("%w: (%d,%d): %v", ErrInvalidBoard, x, y, err)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(segments))
+	for name := range segments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		snake := &pb.Snake{ID: name, Health: DefaultMaxHealth}
+		for i := 0; i < len(segments[name]); i++ {
+			p, ok := segments[name][i]
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: snake %s has no segment %d", ErrInvalidBoard, name, i)
+			}
+			snake.Body = append(snake.Body, p)
+		}
+		if err := snake.Validate(game.Width, game.Height, false, false); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidBoard, err)
+		}
+		frame.Snakes = append(frame.Snakes, snake)
+	}
+	game.Ruleset.Mode = string(GameModeMultiPlayer)
+	if len(frame.Snakes) == 1 {
+		game.Ruleset.Mode = string(GameModeSinglePlayer)
+	}
+	return game, frame, nil
+}
+
+// parseSquare adds everything token puts on p to frame, snake segments are
+// collected in segments by snake name and index.
+func parseSquare(token string, p *pb.Point, frame *pb.GameFrame, segments map[string]map[int]*pb.Point) error {
+	if token == "." {
+		return nil
+	}
+	for i := 0; i < len(token); {
+		c := token[i]
+		switch {
+		case c == '*':
+			frame.Food = append(frame.Food, p.Clone())
+			i++
+		case c == 'x':
+			frame.Hazards = append(frame.Hazards, p.Clone())
+			i++
+		case c >= 'A' && c <= 'Z':
+			j := i + 1
+			for j < len(token) && token[j] >= '0' && token[j] <= '9' {
+				j++
+			}
+			if j == i+1 {
+				return fmt.Errorf("snake %c has no segment number in %q", c, token)
+			}
+			index, err := strconv.Atoi(token[i+1 : j])
+			if err != nil {
+				return err
+			}
+			name := string(c)
+			if segments[name] == nil {
+				segments[name] = map[int]*pb.Point{}
+			}
+			if _, ok := segments[name][index]; ok {
+				return fmt.Errorf("snake %s has segment %d twice", name, index)
+			}
+			segments[name][index] = p.Clone()
+			i = j
+		default:
+			return fmt.Errorf("unknown token %q", token)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBoard(t *testing.T) {
+	game, frame, err := ParseBoard(`
+.  .  *  .
+.  A0 A1 .
+x  .  A2 x*
+B0 B1 B2B3 xx
+`)
+	require.NoError(t, err)
+	require.Equal(t, int32(4), game.Width)
+	require.Equal(t, int32(4), game.Height)
+	require.Equal(t, CurrentRulesetVersion, game.RulesetVersion)
+	require.Equal(t, []*pb.Point{{X: 2, Y: 0}, {X: 3, Y: 2}}, frame.Food)
+	require.Equal(t, []*pb.Point{{X: 0, Y: 2}, {X: 3, Y: 2}, {X: 3, Y: 3}, {X: 3, Y: 3}}, frame.Hazards)
+	require.Equal(t, []*pb.Snake{
+		{ID: "A", Health: DefaultMaxHealth, Body: []*pb.Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}}},
+		{ID: "B", Health: DefaultMaxHealth, Body: []*pb.Point{{X: 0, Y: 3}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 2, Y: 3}}},
+	}, frame.Snakes)
+}
+
+func TestParseBoardPlays(t *testing.T) {
+	game, frame, err := ParseBoard(`
+. . .
+. A0 *
+. A1 .
+`)
+	require.NoError(t, err)
+	next, err := advanceFrame(context.Background(), game, frame, []*SnakeUpdate{{Snake: frame.Snakes[0], Move: "right"}}, noFoodPlacer{})
+	require.NoError(t, err)
+	// A ate, so its tail stays.
+	require.Equal(t, ".  .  .\n.  A1 A0\n.  A2 .\n", RenderFrame(game, next))
+}
+
+func TestParseBoardInvalid(t *testing.T) {
+	for name, spec := range map[string]string{
+		"empty":              "\n\n",
+		"ragged":             ". .\n.\n",
+		"unknown token":      ". o\n. .\n",
+		"lowercase snake":    "a0 .\n. .\n",
+		"no segment number":  "A .\n. .\n",
+		"missing head":       "A1 .\n. .\n",
+		"missing segment":    "A0 .\nA2 .\n",
+		"duplicate segment":  "A0 A1\nA1 .\n",
+		"disconnected snake": "A0 .\n. A1\n",
+	} {
+		_, _, err := ParseBoard(spec)
+		require.True(t, errors.Is(err, ErrInvalidBoard), "%s: %v", name, err)
+	}
+}
+
+func TestRenderFrameRoundTrip(t *testing.T) {
+	for _, spec := range []string{
+		".  .  *\n.  A0 .\nx  .  .\n",
+		"A0   A1   .\nB0B1 A2A3 x*\n.    xx   .\n",
+		".\n",
+	} {
+		game, frame, err := ParseBoard(spec)
+		require.NoError(t, err)
+		require.Equal(t, spec, RenderFrame(game, frame))
+
+		_, again, err := ParseBoard(RenderFrame(game, frame))
+		require.NoError(t, err)
+		require.Equal(t, frame, again)
+	}
+}
+
+func TestRenderFrameSkipsDeadSnakes(t *testing.T) {
+	game := &pb.Game{Width: 2, Height: 1}
+	frame := &pb.GameFrame{Snakes: []*pb.Snake{
+		{ID: "dead", Body: []*pb.Point{{X: 0, Y: 0}}, Death: &pb.Death{Turn: 1}},
+		{ID: "alive", Body: []*pb.Point{{X: 1, Y: 0}}},
+	}}
+	require.Equal(t, ".  A0\n", RenderFrame(game, frame))
+}
@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+)
+
+// FrameDiff is a single difference between two frames of the same turn.
+type FrameDiff struct {
+	Turn int32
+	// SnakeID is set when the difference is about a snake.
+	SnakeID string
+	// Field names what differs: frame, turn, food, events, snake, body,
+	// health or death.
+	Field string
+	// A and B describe the value in each game.
+	A, B string
+}
+
+func (d FrameDiff) String() string {
+	if d.SnakeID != "" {
+		return fmt.Sprintf("turn %d: snake %s %s: %s != %s", d.Turn, d.SnakeID, d.Field, d.A, d.B)
+	}
+	return fmt.Sprintf("turn %d: %s: %s != %s", d.Turn, d.Field, d.A, d.B)
+}
+
+// CompareGames compares two runs of a game frame by frame and returns the turn
+// of the first frame that differs, together with every difference in that
+// frame. When one run ends earlier, the first frame it is missing is where the
+// runs diverge. Runs that are the same return -1 and no differences.
+func CompareGames(a, b []*pb.GameFrame) (int, []FrameDiff) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		switch {
+		case i >= len(a):
+			return int(b[i].Turn), []FrameDiff{{Turn: b[i].Turn, Field: "frame", A: "missing", B: "present"}}
+		case i >= len(b):
+			return int(a[i].Turn), []FrameDiff{{Turn: a[i].Turn, Field: "frame", A: "present", B: "missing"}}
+		}
+		if diff := diffFrames(a[i], b[i]); len(diff) > 0 {
+			return int(a[i].Turn), diff
+		}
+	}
+	return -1, nil
+}
+
+func diffFrames(a, b *pb.GameFrame) []FrameDiff {
+	diff := []FrameDiff{}
+	add := func(snakeID, field string, x, y interface{}) {
+		diff = append(diff, FrameDiff{
+			Turn:    a.Turn,
+			SnakeID: snakeID,
+			Field:   field,
+			A:       fmt.Sprint(x),
+			B:       fmt.Sprint(y),
+		})
+	}
+
+	if a.Turn != b.Turn {
+		add("", "turn", a.Turn, b.Turn)
+	}
+	if !pointsEqual(a.Food, b.Food) {
+		add("", "food", formatPoints(a.Food), formatPoints(b.Food))
+	}
+	if !eventsEqual(a.Events, b.Events) {
+		add("", "events", len(a.Events), len(b.Events))
+	}
+
+	for _, sa := range a.Snakes {
+		sb := findSnake(b, sa.ID)
+		if sb == nil {
+			add(sa.ID, "snake", "present", "missing")
+			continue
+		}
+		if !pointsEqual(sa.Body, sb.Body) {
+			add(sa.ID, "body", formatPoints(sa.Body), formatPoints(sb.Body))
+		}
+		if sa.Health != sb.Health {
+			add(sa.ID, "health", sa.Health, sb.Health)
+		}
+		if !sa.Death.Equal(sb.Death) {
+			add(sa.ID, "death", formatDeath(sa.Death), formatDeath(sb.Death))
+		}
+	}
+	for _, sb := range b.Snakes {
+		if findSnake(a, sb.ID) == nil {
+			add(sb.ID, "snake", "missing", "present")
+		}
+	}
+	return diff
+}
+
+func pointsEqual(a, b []*pb.Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func eventsEqual(a, b []*pb.Event) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatPoints(points []*pb.Point) string {
+	s := make([]string, len(points))
+	for i, p := range points {
+		s[i] = fmt.Sprintf("(%d,%d)", p.X, p.Y)
+	}
+	return "[" + strings.Join(s, " ") + "]"
+}
+
+func formatDeath(d *pb.Death) string {
+	if d == nil {
+		return "alive"
+	}
+	return fmt.Sprintf("%s on turn %d", d.Cause, d.Turn)
+}
@@ -0,0 +1,72 @@
+package rules
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func cloneFrames(frames []*pb.GameFrame) []*pb.GameFrame {
+	clones := make([]*pb.GameFrame, len(frames))
+	for i, f := range frames {
+		clones[i] = proto.Clone(f).(*pb.GameFrame)
+	}
+	return clones
+}
+
+func TestCompareGamesSame(t *testing.T) {
+	_, frames := playGame(t)
+
+	turn, diff := CompareGames(frames, cloneFrames(frames))
+	require.Equal(t, -1, turn)
+	require.Empty(t, diff)
+}
+
+func TestCompareGamesDivergence(t *testing.T) {
+	_, frames := playGame(t)
+	other := cloneFrames(frames)
+	other[4].Snakes[1].Health--
+	other[4].Snakes[1].Body[0].X++
+	// Later frames differ as well, only the first divergence is reported.
+	other[6].Snakes[0].Health--
+
+	turn, diff := CompareGames(frames, other)
+	require.Equal(t, 4, turn)
+	require.Len(t, diff, 2)
+	require.Equal(t, "2", diff[0].SnakeID)
+	require.Equal(t, "body", diff[0].Field)
+	require.Equal(t, "health", diff[1].Field)
+	require.Equal(t, FrameDiff{
+		Turn:    4,
+		SnakeID: "2",
+		Field:   "health",
+		A:       fmt.Sprint(frames[4].Snakes[1].Health),
+		B:       fmt.Sprint(other[4].Snakes[1].Health),
+	}, diff[1])
+}
+
+func TestCompareGamesDifferentLength(t *testing.T) {
+	_, frames := playGame(t)
+
+	turn, diff := CompareGames(frames, frames[:7])
+	require.Equal(t, 7, turn)
+	require.Equal(t, []FrameDiff{{Turn: 7, Field: "frame", A: "present", B: "missing"}}, diff)
+
+	turn, diff = CompareGames(frames[:3], frames)
+	require.Equal(t, 3, turn)
+	require.Equal(t, "missing", diff[0].A)
+}
+
+func TestCompareGamesMissingSnake(t *testing.T) {
+	_, frames := playGame(t)
+	other := cloneFrames(frames)
+	other[2].Snakes = other[2].Snakes[:1]
+
+	turn, diff := CompareGames(frames, other)
+	require.Equal(t, 2, turn)
+	require.Equal(t, []FrameDiff{{Turn: 2, SnakeID: "2", Field: "snake", A: "present", B: "missing"}}, diff)
+	require.Equal(t, "turn 2: snake 2 snake: present != missing", diff[0].String())
+}
@@ -1,15 +1,21 @@
 package rules
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
 	uuid "github.com/satori/go.uuid"
 )
 
 // GameMode represents the mode the game is running in
 type GameMode string
 
+// GameModeOf returns the mode game is running in, see Ruleset.Mode.
+func GameModeOf(game *pb.Game) GameMode {
+	return GameMode(gameRuleset(game).Mode)
+}
+
 const (
 	// GameModeSinglePlayer represents the game running in single player mode, this means the game will
 	// run until the only snake in the game dies
@@ -19,69 +25,209 @@ const (
 	GameModeMultiPlayer GameMode = "multi-player"
 )
 
+// DefaultSnakeTimeout is the time in milliseconds snakes have to answer a move
+// when neither the create request nor its ruleset set one.
+const DefaultSnakeTimeout = 1000
+
+// startingLength is the length of a snake that starts a game.
+const startingLength = 3
+
+// ValidateGame checks a create request for configurations that can never be
+// played, such as more snakes than there are squares on the board or a
+// negative snake timeout or grace. Food is not checked, initial food is only
+// placed where there is room left.
+func ValidateGame(req *pb.CreateRequest) error {
+	for _, timeout := range []int32{req.SnakeTimeout, req.Ruleset.GetSnakeTimeout(), req.Ruleset.GetSnakeTimeoutGrace()} {
+		if timeout < 0 {
+			return fmt.Errorf("%w: %dms", ErrInvalidTimeout, timeout)
+		}
+	}
+	if req.Width < 0 || req.Height < 0 {
+		return fmt.Errorf("%w: size %dx%d", ErrInvalidBoard, req.Width, req.Height)
+	}
+	if squares := int(req.Width) * int(req.Height); len(req.Snakes) > squares {
+		return fmt.Errorf("%w: %d snakes do not fit on %dx%d", ErrInvalidBoard, len(req.Snakes), req.Width, req.Height)
+	}
+	return nil
+}
+
 // CreateInitialGame creates a new game based on the create request passed in
 func CreateInitialGame(req *pb.CreateRequest) (*pb.Game, []*pb.GameFrame, error) {
-	snakes, err := getSnakes(req)
+	if err := ValidateGame(req); err != nil {
+		return nil, nil, err
+	}
+	ruleset := newRuleset(req.Ruleset)
+	if req.SnakeTimeout != 0 {
+		ruleset.SnakeTimeout = req.SnakeTimeout
+	}
+	if ruleset.SnakeTimeout == 0 {
+		ruleset.SnakeTimeout = DefaultSnakeTimeout
+	}
+	ruleset.Mode = string(GameModeMultiPlayer)
+	if len(req.Snakes) == 1 {
+		ruleset.Mode = string(GameModeSinglePlayer)
+	}
+	snakes, err := getSnakes(req, ruleset)
 	if err != nil {
 		return nil, nil, err
 	}
-	food, err := generateFood(req, snakes)
+	if err := validateSpawns(snakes); err != nil {
+		return nil, nil, err
+	}
+	hazards := initialHazards(req, ruleset)
+	food, err := generateFood(req, snakes, hazards, ruleset)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	id := uuid.NewV4().String()
 	game := &pb.Game{
-		ID:           id,
-		Width:        req.Width,
-		Height:       req.Height,
-		Status:       string(GameStatusStopped),
-		SnakeTimeout: 1000, // TODO: make this configurable
-		TurnTimeout:  200,  // TODO: make this configurable
-		Mode:         string(GameModeMultiPlayer),
-	}
-
-	if len(snakes) == 1 {
-		game.Mode = string(GameModeSinglePlayer)
+		ID:             id,
+		Width:          req.Width,
+		Height:         req.Height,
+		Status:         string(GameStatusStopped),
+		TurnTimeout:    200, // TODO: make this configurable
+		Ruleset:        ruleset,
+		RulesetVersion: CurrentRulesetVersion,
+		Seed:           req.Seed,
+		FoodTarget:     req.Food,
+		Obstacles:      req.Obstacles,
+		Name:           req.Name,
 	}
 
 	frames := []*pb.GameFrame{
 		{
-			Turn:   0,
-			Food:   food,
-			Snakes: snakes,
+			Turn:    0,
+			Food:    food,
+			Snakes:  snakes,
+			Hazards: hazards,
 		},
 	}
 
 	return game, frames, nil
 }
 
-func getSnakes(req *pb.CreateRequest) ([]*pb.Snake, error) {
+// ValidateFrame checks that frame can be played on the board of game: the
+// turn is not negative, the food and hazards are on the board and every alive
+// snake passes pb.Snake.Validate, has an ID no other snake has and shares no
+// square with an obstacle or another alive snake. Dead snakes are kept as
+// they are, a snake that ran off the board is still dead off the board.
+func ValidateFrame(game *pb.Game, frame *pb.GameFrame) error {
+	if game.Width <= 0 || game.Height <= 0 {
+		return fmt.Errorf("%w: size %dx%d", ErrInvalidBoard, game.Width, game.Height)
+	}
+	if frame.Turn < 0 {
+		return fmt.Errorf("%w: turn %d", ErrInvalidBoard, frame.Turn)
+	}
+	for _, p := range append(append([]*pb.Point{}, frame.Food...), frame.Hazards...) {
+		if deathByOutOfBounds(p, game.Width, game.Height) {
+			return fmt.Errorf("%w: (%d,%d) is off the %dx%d board", ErrInvalidBoard, p.X, p.Y, game.Width, game.Height)
+		}
+	}
+	ruleset := gameRuleset(game)
+	ids := map[string]bool{}
+	for _, s := range frame.Snakes {
+		if ids[s.ID] {
+			return fmt.Errorf("%w: %s", ErrDuplicateSnakeID, s.ID)
+		}
+		ids[s.ID] = true
+		if s.Death != nil {
+			continue
+		}
+		if err := s.Validate(game.Width, game.Height, ruleset.WrapHorizontal, ruleset.WrapVertical); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidBoard, err)
+		}
+		for _, p := range s.Body {
+			if containsPoint(game.Obstacles, p) {
+				return fmt.Errorf("%w: snake %s is on the obstacle at (%d,%d)", ErrInvalidBoard, s.ID, p.X, p.Y)
+			}
+		}
+	}
+	if a, b, p := overlappingSnakes(frame.AliveSnakes()); p != nil {
+		return fmt.Errorf("%w: snakes %s and %s are both on (%d,%d)", ErrInvalidBoard, a, b, p.X, p.Y)
+	}
+	return nil
+}
+
+// CreateGameFromFrame creates a new game that starts from frame, e.g. to fork
+// a replay at any of its turns. The game takes its board, ruleset and other
+// settings from game and gets a new ID. The frame is checked with
+// ValidateFrame and becomes the first frame, turn 0, of the new game. The
+// turns dead snakes died on are moved along with it, so they keep how long
+// ago they died, e.g. for respawning. Its events belong to the game it was
+// taken from and are dropped.
+func CreateGameFromFrame(game *pb.Game, frame *pb.GameFrame) (*pb.Game, []*pb.GameFrame, error) {
+	if frame == nil {
+		return nil, nil, ErrNilFrame
+	}
+	if err := ValidateRulesetVersion(game); err != nil {
+		return nil, nil, err
+	}
+	if err := ValidateFrame(game, frame); err != nil {
+		return nil, nil, err
+	}
+
+	forked := proto.Clone(game).(*pb.Game)
+	forked.ID = uuid.NewV4().String()
+	forked.Status = string(GameStatusStopped)
+	first := proto.Clone(frame).(*pb.GameFrame)
+	for _, s := range first.DeadSnakes() {
+		s.Death.Turn -= first.Turn
+	}
+	first.Turn = 0
+	first.Events = nil
+	return forked, []*pb.GameFrame{first}, nil
+}
+
+// validateSpawns checks that no two snakes start on the same square.
+func validateSpawns(snakes []*pb.Snake) error {
+	if a, b, p := overlappingSnakes(snakes); p != nil {
+		return fmt.Errorf("%w: snakes %s and %s both start on (%d,%d)", ErrInvalidBoard, a, b, p.X, p.Y)
+	}
+	return nil
+}
+
+// overlappingSnakes returns the IDs of two snakes that share a square and the
+// square, or a nil point when every snake has its squares to itself. A snake
+// may be stacked on its own squares, so only points of different snakes are
+// compared.
+func overlappingSnakes(snakes []*pb.Snake) (string, string, *pb.Point) {
+	owners := map[pb.Point]string{}
+	for _, s := range snakes {
+		for _, p := range s.Body {
+			if owner, ok := owners[*p]; ok && owner != s.ID {
+				return owner, s.ID, p
+			}
+			owners[*p] = s.ID
+		}
+	}
+	return "", "", nil
+}
+
+func getSnakes(req *pb.CreateRequest, ruleset *pb.Ruleset) ([]*pb.Snake, error) {
 	snakes := []*pb.Snake{}
 
-	for _, opts := range req.Snakes {
-		startPoint := getUnoccupiedPoint(req.Width, req.Height, []*pb.Point{}, snakes)
+	for i, opts := range req.Snakes {
+		startPoint := getUnoccupiedPoint(nil, req.Width, req.Height, req.Obstacles, snakes)
 		if startPoint == nil {
-			return nil, errors.New("no unoccupied spots left for new snake")
+			return nil, fmt.Errorf("%w: no unoccupied spots left for new snake", ErrInvalidBoard)
 		}
 		snake := &pb.Snake{
 			ID:     opts.ID,
 			Name:   opts.Name,
 			URL:    opts.URL,
-			Health: 100,
-			Body: []*pb.Point{
-				startPoint,
-				startPoint.Clone(),
-				startPoint.Clone(),
-			},
+			Squad:  opts.Squad,
+			Health: ruleset.StartingHealth,
+			Body:   stackedBody(startPoint, startingLength),
+			Facing: startingFacing(StartingDirection(ruleset.StartingDirection), startPoint, req.Width, req.Height),
 		}
 		if len(snake.ID) == 0 {
-			snake.ID = uuid.NewV4().String()
+			snake.ID = newSnakeID(req.Seed, i)
 		}
 
 		for _, s := range snakes {
 			if s.ID == snake.ID {
-				return nil, errors.New("duplicate snake id found, create aborted")
+				return nil, fmt.Errorf("%w: %s", ErrDuplicateSnakeID, snake.ID)
 			}
 		}
 
@@ -91,14 +237,43 @@ func getSnakes(req *pb.CreateRequest) ([]*pb.Snake, error) {
 	return snakes, nil
 }
 
-func generateFood(req *pb.CreateRequest, snakes []*pb.Snake) ([]*pb.Point, error) {
+// stackedBody returns the body of a snake of the given length placed on p,
+// every segment is stacked on the same square.
+func stackedBody(p *pb.Point, length int) []*pb.Point {
+	body := []*pb.Point{p}
+	for len(body) < length {
+		body = append(body, p.Clone())
+	}
+	return body
+}
+
+// newSnakeID returns the ID for a snake that joined without one. With a seed
+// the ID is derived from the seed and the position the snake joined in, so
+// creating the game again gives every snake the same ID.
+func newSnakeID(seed int64, joined int) string {
+	if seed == 0 {
+		return uuid.NewV4().String()
+	}
+	return uuid.NewV5(uuid.NamespaceOID, fmt.Sprintf("snake/%d/%d", seed, joined)).String()
+}
+
+// generateFood places up to req.Food pieces of food, relative to the hazards
+// as the ruleset's HazardFoodPlacement says. Once the board is full no more
+// food is placed. No food is placed when the ruleset disables food.
+func generateFood(req *pb.CreateRequest, snakes []*pb.Snake, hazards []*pb.Point, ruleset *pb.Ruleset) ([]*pb.Point, error) {
 	food := []*pb.Point{}
+	if ruleset.DisableFood {
+		return food, nil
+	}
+	r := seededRand(req.Seed, 0)
+	placer := withObstacles(withHazardPlacement(RandomFoodPlacer{Rand: r}, ruleset, hazards, r), req.Obstacles)
 
 	for i := int32(0); i < req.Food; i++ {
-		p := getUnoccupiedPoint(req.Width, req.Height, food, snakes)
-		if p != nil {
-			food = append(food, p)
+		p := placer.PlaceFood(req.Width, req.Height, food, snakes)
+		if p == nil {
+			break
 		}
+		food = append(food, p)
 	}
 
 	return food, nil
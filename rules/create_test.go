@@ -1,7 +1,13 @@
 package rules
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/battlesnakeio/engine/controller/pb"
 	"github.com/stretchr/testify/require"
@@ -22,7 +28,7 @@ func TestCreateInitialGame_DuplicateSnakeIDs(t *testing.T) {
 			{ID: "snake_123"},
 		},
 	})
-	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrDuplicateSnakeID))
 }
 
 func TestCreateInitialGame_GeneratedSnakeID(t *testing.T) {
@@ -38,6 +44,71 @@ func TestCreateInitialGame_GeneratedSnakeID(t *testing.T) {
 	require.NotEmpty(t, frames[0].Snakes[0].ID)
 }
 
+func TestCreateInitialGame_GameMode(t *testing.T) {
+	g, _, err := CreateInitialGame(&pb.CreateRequest{
+		Width:  20,
+		Height: 20,
+		Snakes: []*pb.SnakeOptions{{ID: "a"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(GameModeSinglePlayer), g.Ruleset.Mode)
+	require.Equal(t, GameModeSinglePlayer, GameModeOf(g))
+
+	g, _, err = CreateInitialGame(&pb.CreateRequest{
+		Width:  20,
+		Height: 20,
+		Snakes: []*pb.SnakeOptions{{ID: "a"}, {ID: "b"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(GameModeMultiPlayer), g.Ruleset.Mode)
+}
+
+func TestCreateInitialGame_SeededSnakeIDs(t *testing.T) {
+	snakeIDs := func(seed int64) []string {
+		g, frames, err := CreateInitialGame(&pb.CreateRequest{
+			Width:  20,
+			Height: 20,
+			Seed:   seed,
+			Snakes: []*pb.SnakeOptions{
+				{ID: ""},
+				{ID: "preset"},
+				{ID: ""},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, seed, g.Seed)
+		ids := []string{}
+		for _, s := range frames[0].Snakes {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	ids := snakeIDs(42)
+	require.Equal(t, "preset", ids[1])
+	require.NotEmpty(t, ids[0])
+	require.NotEqual(t, ids[0], ids[2])
+	require.Equal(t, ids, snakeIDs(42), "same seed, same IDs")
+	require.NotEqual(t, ids, snakeIDs(43))
+
+	// Without a seed the IDs are random.
+	random := snakeIDs(0)
+	require.Equal(t, "preset", random[1])
+	require.NotEqual(t, random, snakeIDs(0))
+}
+
+func TestValidateSpawns(t *testing.T) {
+	spawn := func(id string, x, y int32) *pb.Snake {
+		p := &pb.Point{X: x, Y: y}
+		return &pb.Snake{ID: id, Body: []*pb.Point{p, p.Clone(), p.Clone()}}
+	}
+	require.NoError(t, validateSpawns([]*pb.Snake{spawn("a", 1, 1), spawn("b", 2, 1)}))
+
+	err := validateSpawns([]*pb.Snake{spawn("a", 1, 1), spawn("b", 2, 1), spawn("c", 1, 1)})
+	require.True(t, errors.Is(err, ErrInvalidBoard))
+	require.Contains(t, err.Error(), "snakes a and c both start on (1,1)")
+}
+
 func TestCreateInitialGame_MoreSnakesThanSpace(t *testing.T) {
 	_, _, err := CreateInitialGame(&pb.CreateRequest{
 		Width:  2,
@@ -51,5 +122,325 @@ func TestCreateInitialGame_MoreSnakesThanSpace(t *testing.T) {
 		},
 	})
 
-	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrInvalidBoard))
+}
+
+func TestCreateInitialGame_FullBoard(t *testing.T) {
+	req := &pb.CreateRequest{
+		Width:  3,
+		Height: 3,
+		Food:   5,
+	}
+	for i := 0; i < 9; i++ {
+		req.Snakes = append(req.Snakes, &pb.SnakeOptions{})
+	}
+	require.NoError(t, ValidateGame(req))
+
+	_, frames, err := CreateInitialGame(req)
+	require.NoError(t, err)
+	require.Len(t, frames[0].Snakes, 9)
+	require.Len(t, frames[0].Food, 0)
+}
+
+func TestCreateInitialGame_SnakeTimeout(t *testing.T) {
+	g, _, err := CreateInitialGame(&pb.CreateRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(DefaultSnakeTimeout), g.Ruleset.SnakeTimeout)
+
+	g, _, err = CreateInitialGame(&pb.CreateRequest{SnakeTimeout: 250})
+	require.NoError(t, err)
+	require.Equal(t, int32(250), g.Ruleset.SnakeTimeout)
+
+	g, _, err = CreateInitialGame(&pb.CreateRequest{Ruleset: &pb.Ruleset{SnakeTimeout: 300}})
+	require.NoError(t, err)
+	require.Equal(t, int32(300), g.Ruleset.SnakeTimeout)
+
+	_, _, err = CreateInitialGame(&pb.CreateRequest{SnakeTimeout: -1})
+	require.True(t, errors.Is(err, ErrInvalidTimeout))
+	_, _, err = CreateInitialGame(&pb.CreateRequest{Ruleset: &pb.Ruleset{SnakeTimeout: -1}})
+	require.True(t, errors.Is(err, ErrInvalidTimeout))
+	_, _, err = CreateInitialGame(&pb.CreateRequest{Ruleset: &pb.Ruleset{SnakeTimeoutGrace: -1}})
+	require.True(t, errors.Is(err, ErrInvalidTimeout))
+}
+
+func TestValidateGame(t *testing.T) {
+	req := &pb.CreateRequest{
+		Width:  3,
+		Height: 3,
+	}
+	for i := 0; i < 10; i++ {
+		req.Snakes = append(req.Snakes, &pb.SnakeOptions{})
+	}
+	require.True(t, errors.Is(ValidateGame(req), ErrInvalidBoard))
+
+	_, _, err := CreateInitialGame(req)
+	require.True(t, errors.Is(err, ErrInvalidBoard))
+
+	require.True(t, errors.Is(ValidateGame(&pb.CreateRequest{Width: -1, Height: 3}), ErrInvalidBoard))
+	require.NoError(t, ValidateGame(&pb.CreateRequest{}))
+}
+
+func TestCreateInitialGame_StandardRuleset(t *testing.T) {
+	g, frames, err := CreateInitialGame(&pb.CreateRequest{
+		Width:  20,
+		Height: 20,
+		Snakes: []*pb.SnakeOptions{
+			{ID: "snake_123"},
+		},
+	})
+	require.NoError(t, err)
+	ruleset := StandardRuleset()
+	ruleset.Mode = string(GameModeSinglePlayer)
+	ruleset.SnakeTimeout = DefaultSnakeTimeout
+	require.Equal(t, ruleset, g.Ruleset)
+	require.Equal(t, CurrentRulesetVersion, g.RulesetVersion)
+	require.Equal(t, int32(DefaultMaxHealth), frames[0].Snakes[0].Health)
+}
+
+func TestCreateInitialGame_CustomRuleset(t *testing.T) {
+	g, frames, err := CreateInitialGame(&pb.CreateRequest{
+		Width:   20,
+		Height:  20,
+		Ruleset: &pb.Ruleset{Name: "custom", MaxHealth: 50},
+		Snakes: []*pb.SnakeOptions{
+			{ID: "snake_123"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "custom", g.Ruleset.Name)
+	require.Equal(t, int32(50), frames[0].Snakes[0].Health)
+}
+
+func TestCreateInitialGame_StartingHealth(t *testing.T) {
+	RegisterMoveRequester("bot", inProcessBot{move: "up"})
+	defer RegisterMoveRequester("bot", nil)
+
+	g, frames, err := CreateInitialGame(&pb.CreateRequest{
+		Width:   20,
+		Height:  20,
+		Ruleset: &pb.Ruleset{StartingHealth: 50, MaxHealth: 100},
+		Snakes: []*pb.SnakeOptions{
+			{ID: "snake_123", URL: "bot://1"},
+		},
+	})
+	require.NoError(t, err)
+	snake := frames[0].Snakes[0]
+	require.Equal(t, int32(50), snake.Health)
+
+	// Eating refills to the max health, not the starting health.
+	snake.Body = []*pb.Point{{X: 5, Y: 5}, {X: 5, Y: 5}, {X: 5, Y: 5}}
+	frames[0].Food = []*pb.Point{{X: 5, Y: 4}}
+	next, err := GameTick(context.Background(), g, frames[0])
+	require.NoError(t, err)
+	require.Nil(t, snake.Death)
+	require.Equal(t, int32(100), next.Snakes[0].Health)
+}
+
+func TestCreateInitialGame_Squads(t *testing.T) {
+	_, frames, err := CreateInitialGame(&pb.CreateRequest{
+		Width:   20,
+		Height:  20,
+		Ruleset: &pb.Ruleset{SquadMode: true},
+		Snakes: []*pb.SnakeOptions{
+			{ID: "snake_1", Squad: "red"},
+			{ID: "snake_2", Squad: "blue"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "red", frames[0].Snakes[0].Squad)
+	require.Equal(t, "blue", frames[0].Snakes[1].Squad)
+}
+
+func TestCreateInitialGame_Obstacles(t *testing.T) {
+	obstacles := []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}
+	game, frames, err := CreateInitialGame(&pb.CreateRequest{
+		Width:     2,
+		Height:    2,
+		Food:      1,
+		Obstacles: obstacles,
+		Snakes:    []*pb.SnakeOptions{{ID: "snake_1"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, obstacles, game.Obstacles)
+	// The only free square goes to the snake, no food fits.
+	require.Equal(t, &pb.Point{X: 1, Y: 1}, frames[0].Snakes[0].Head())
+	require.Empty(t, frames[0].Food)
+
+	_, _, err = CreateInitialGame(&pb.CreateRequest{
+		Width:     2,
+		Height:    2,
+		Obstacles: obstacles,
+		Snakes:    []*pb.SnakeOptions{{ID: "snake_1"}, {ID: "snake_2"}},
+	})
+	require.True(t, errors.Is(err, ErrInvalidBoard))
+}
+
+func TestCreateInitialGame_FoodAvoidsSpawnSquares(t *testing.T) {
+	// 4 stacked snakes and 5 food exactly fill a 3x3 board, extra food is
+	// dropped rather than placed on a spawn square.
+	_, frames, err := CreateInitialGame(&pb.CreateRequest{
+		Width:  3,
+		Height: 3,
+		Food:   6,
+		Snakes: []*pb.SnakeOptions{
+			{ID: "snake_1"},
+			{ID: "snake_2"},
+			{ID: "snake_3"},
+			{ID: "snake_4"},
+		},
+	})
+	require.NoError(t, err)
+	frame := frames[0]
+	require.Len(t, frame.Food, 5)
+
+	occupied := getUniqOccupiedPoints(frame.Food, frame.Snakes)
+	require.Len(t, occupied, 9)
+	for _, s := range frame.Snakes {
+		require.Len(t, s.Body, 3)
+		require.True(t, s.Body[0].Equal(s.Body[1]))
+		require.True(t, s.Body[0].Equal(s.Body[2]))
+		require.False(t, containsPoint(frame.Food, s.Head()))
+	}
+}
+
+func TestCreateInitialGame_FirstTicks(t *testing.T) {
+	game, frames, err := CreateInitialGame(&pb.CreateRequest{
+		Width:  11,
+		Height: 11,
+		Food:   10,
+		Snakes: []*pb.SnakeOptions{
+			{ID: "snake_1"},
+		},
+	})
+	require.NoError(t, err)
+
+	// Steer the snake away from the nearest wall and put food in its way so
+	// food gets replaced while the snake is still stacked.
+	snake := frames[0].Snakes[0]
+	spawn := snake.Head().Clone()
+	next := &pb.Point{X: spawn.X, Y: spawn.Y + 1}
+	snake.URL = "http://down"
+	if spawn.Y >= game.Height/2 {
+		next.Y = spawn.Y - 1
+		snake.URL = "http://up"
+	}
+	if !containsPoint(frames[0].Food, next) {
+		frames[0].Food[0] = next
+	}
+	createClient = directionMockClient
+
+	frame := frames[0]
+	for turn := int32(1); turn <= 2; turn++ {
+		frame, err = GameTick(context.Background(), game, frame)
+		require.NoError(t, err)
+		require.Equal(t, turn, frame.Turn)
+		snake = frame.Snakes[0]
+		require.Nil(t, snake.Death, "Snake should not be dead on turn %d", turn)
+		for _, f := range frame.Food {
+			require.False(t, containsPoint(snake.Body, f), "food on snake on turn %d", turn)
+		}
+	}
+	// The snake grew from eating, the rest of its stacked body stays behind
+	// on the spawn square.
+	require.True(t, len(snake.Body) >= 4)
+	require.True(t, snake.Body[len(snake.Body)-1].Equal(spawn))
+}
+
+// directionMockClient answers /move with the direction named by the snake's
+// host, e.g. http://up/move moves up.
+func directionMockClient(time.Duration) httpClient {
+	return mockHTTPClient{
+		resp: func(url string) *http.Response {
+			direction := strings.TrimSuffix(strings.TrimPrefix(url, "http://"), "/move")
+			body := readCloser{Buffer: &bytes.Buffer{}}
+			body.WriteString("{\"move\":\"" + direction + "\"}")
+			return &http.Response{
+				Body:       body,
+				StatusCode: 200,
+			}
+		},
+	}
+}
+
+func TestCreateGameFromFrame(t *testing.T) {
+	game := &pb.Game{
+		ID:             "original",
+		Width:          7,
+		Height:         7,
+		Status:         string(GameStatusRunning),
+		Ruleset:        &pb.Ruleset{MinimumFood: 2},
+		RulesetVersion: CurrentRulesetVersion,
+	}
+	frame := &pb.GameFrame{
+		Turn:    30,
+		Food:    []*pb.Point{{X: 3, Y: 3}},
+		Hazards: []*pb.Point{{X: 0, Y: 0}},
+		Events:  []*pb.Event{{Type: FrameEventFoodSpawned, Point: &pb.Point{X: 3, Y: 3}}},
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 70, Body: []*pb.Point{{X: 1, Y: 1}, {X: 1, Y: 2}}},
+			// Dead snakes are not checked, this one ran off the board.
+			{ID: "2", Body: []*pb.Point{{X: -1, Y: 4}, {X: 0, Y: 4}}, Death: &pb.Death{Cause: DeathCauseWallCollision, Turn: 20}},
+		},
+	}
+
+	forked, frames, err := CreateGameFromFrame(game, frame)
+	require.NoError(t, err)
+	require.NotEqual(t, game.ID, forked.ID)
+	require.Equal(t, string(GameStatusStopped), forked.Status)
+	require.Equal(t, game.Ruleset, forked.Ruleset)
+	require.Len(t, frames, 1)
+	require.Equal(t, int32(0), frames[0].Turn)
+	require.Empty(t, frames[0].Events)
+	require.Equal(t, frame.Snakes[0], frames[0].Snakes[0])
+	// The snake died 10 turns before the frame the game is forked from.
+	require.Equal(t, int32(-10), frames[0].Snakes[1].Death.Turn)
+	require.Equal(t, "original", game.ID)
+	require.Equal(t, int32(30), frame.Turn)
+	require.Equal(t, int32(20), frame.Snakes[1].Death.Turn)
+}
+
+func TestCreateGameFromFrameInvalid(t *testing.T) {
+	game := &pb.Game{Width: 5, Height: 5, Obstacles: []*pb.Point{{X: 2, Y: 2}}}
+	snake := func(id string, body ...*pb.Point) *pb.Snake {
+		return &pb.Snake{ID: id, Health: 100, Body: body}
+	}
+	tests := []struct {
+		frame *pb.GameFrame
+		err   error
+		msg   string
+	}{
+		{&pb.GameFrame{Turn: -1}, ErrInvalidBoard, "turn -1"},
+		{&pb.GameFrame{Food: []*pb.Point{{X: 5, Y: 0}}}, ErrInvalidBoard, "(5,0) is off the 5x5 board"},
+		{&pb.GameFrame{Hazards: []*pb.Point{{X: 0, Y: -1}}}, ErrInvalidBoard, "(0,-1) is off the 5x5 board"},
+		{&pb.GameFrame{Snakes: []*pb.Snake{snake("1")}}, ErrInvalidBoard, "snake 1 has no body"},
+		{&pb.GameFrame{Snakes: []*pb.Snake{snake("1", &pb.Point{X: 1, Y: 1}, &pb.Point{X: 3, Y: 1})}}, ErrInvalidBoard, "is not next to"},
+		{&pb.GameFrame{Snakes: []*pb.Snake{snake("1", &pb.Point{X: 1, Y: 1}), snake("1", &pb.Point{X: 2, Y: 2})}}, ErrDuplicateSnakeID, "1"},
+		{&pb.GameFrame{Snakes: []*pb.Snake{snake("1", &pb.Point{X: 2, Y: 1}, &pb.Point{X: 2, Y: 2})}}, ErrInvalidBoard, "snake 1 is on the obstacle at (2,2)"},
+		{&pb.GameFrame{Snakes: []*pb.Snake{snake("1", &pb.Point{X: 1, Y: 1}, &pb.Point{X: 1, Y: 2}), snake("2", &pb.Point{X: 0, Y: 2}, &pb.Point{X: 1, Y: 2})}}, ErrInvalidBoard, "snakes 1 and 2 are both on (1,2)"},
+		{nil, ErrNilFrame, ""},
+	}
+	for _, test := range tests {
+		_, _, err := CreateGameFromFrame(game, test.frame)
+		require.True(t, errors.Is(err, test.err), "%v", err)
+		require.Contains(t, err.Error(), test.msg)
+	}
+
+	_, _, err := CreateGameFromFrame(&pb.Game{}, &pb.GameFrame{})
+	require.True(t, errors.Is(err, ErrInvalidBoard))
+}
+
+func TestCreateGameFromFrameWrapped(t *testing.T) {
+	game := &pb.Game{Width: 5, Height: 5, Ruleset: &pb.Ruleset{WrapHorizontal: true}}
+	across := &pb.GameFrame{Snakes: []*pb.Snake{
+		{ID: "1", Health: 100, Body: []*pb.Point{{X: 0, Y: 1}, {X: 4, Y: 1}}},
+	}}
+	_, _, err := CreateGameFromFrame(game, across)
+	require.NoError(t, err)
+
+	// The board only wraps horizontally, the top and bottom edges are apart.
+	down := &pb.GameFrame{Snakes: []*pb.Snake{
+		{ID: "1", Health: 100, Body: []*pb.Point{{X: 1, Y: 0}, {X: 1, Y: 4}}},
+	}}
+	_, _, err = CreateGameFromFrame(game, down)
+	require.True(t, errors.Is(err, ErrInvalidBoard), "%v", err)
 }
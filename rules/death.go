@@ -1,6 +1,10 @@
 package rules
 
-import "github.com/battlesnakeio/engine/controller/pb"
+import (
+	"sort"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+)
 
 type deathUpdate struct {
 	Snake *pb.Snake
@@ -9,9 +13,32 @@ type deathUpdate struct {
 
 // checkForDeath looks through the snakes with the updated coords and checks to see if any have died
 // possible death options are starvation (health has reached 0), wall collision, snake body collision
-// snake head collision (other snake is same size or greater)
-func checkForDeath(width, height int32, frame *pb.GameFrame) []deathUpdate {
+// snake head collision (other snake is same size or greater), obstacle collision, and not
+// responding when the ruleset eliminates unresponsive snakes. Snake and head collisions record the ID of the other snake as
+// EliminatedBy.
+//
+// ate holds the IDs of the snakes that eat this turn. Their tails stay on their square as they
+// grow, while the tails of the other snakes move out of the way. When ate is nil every tail
+// stays, as it did before RulesetVersion3.
+//
+// When resolve is set, collisions are resolved once every head has moved, in
+// an order that does not depend on the order of the snakes, see
+// collisionDeath. Otherwise every collision of a snake is listed in the order
+// of the snakes in frame, as it was before RulesetVersion5, and the first one
+// applied decides the death.
+//
+// In squad mode with SquadWipe set, the other members of a squad die along with
+// the first member that dies.
+func checkForDeath(width, height int32, obstacles []*pb.Point, frame *pb.GameFrame, ruleset *pb.Ruleset, ate map[string]bool, resolve bool) []deathUpdate {
 	updates := []deathUpdate{}
+	// All heads are in place before any collision is classified.
+	snakes := snakesInIDOrder(frame.AliveSnakes())
+	heads := map[pb.Point][]*pb.Snake{}
+	for _, s := range snakes {
+		if head := s.Head(); head != nil {
+			heads[*head] = append(heads[*head], s)
+		}
+	}
 	for _, s := range frame.AliveSnakes() {
 		if deathByHealth(s.Health) {
 			updates = append(updates, deathUpdate{
@@ -23,8 +50,26 @@ func checkForDeath(width, height int32, frame *pb.GameFrame) []deathUpdate {
 			})
 			continue
 		}
+		if deathByNotResponding(s, frame.Turn, ruleset) {
+			updates = append(updates, deathUpdate{
+				Snake: s,
+				Death: &pb.Death{
+					Turn:  frame.Turn,
+					Cause: DeathCauseNotResponding,
+				},
+			})
+			continue
+		}
 		head := s.Head()
 		if head == nil {
+			// A snake without a body can not be on the board.
+			updates = append(updates, deathUpdate{
+				Snake: s,
+				Death: &pb.Death{
+					Turn:  frame.Turn,
+					Cause: DeathCauseNoBody,
+				},
+			})
 			continue
 		}
 		if deathByOutOfBounds(head, width, height) {
@@ -37,50 +82,197 @@ func checkForDeath(width, height int32, frame *pb.GameFrame) []deathUpdate {
 			})
 			continue
 		}
+		if containsPoint(obstacles, head) {
+			updates = append(updates, deathUpdate{
+				Snake: s,
+				Death: &pb.Death{
+					Turn:  frame.Turn,
+					Cause: DeathCauseObstacleCollision,
+				},
+			})
+			continue
+		}
 
-		for _, other := range frame.AliveSnakes() {
-			if deathByHeadCollision(s, other) {
-				updates = append(updates, deathUpdate{
-					Snake: s,
-					Death: &pb.Death{
-						Turn:  frame.Turn,
-						Cause: DeathCauseHeadToHeadCollision,
-					},
-				})
-			}
+		if !resolve {
+			updates = append(updates, collisionDeathsInFrameOrder(s, frame, ruleset, ate)...)
+			continue
+		}
+		if death := collisionDeath(s, snakes, heads, ruleset, ate); death != nil {
+			death.Turn = frame.Turn
+			updates = append(updates, deathUpdate{Snake: s, Death: death})
+		}
+	}
+	return append(updates, squadWipes(frame, ruleset, updates)...)
+}
 
-			for i, b := range other.Body {
-				if i == 0 {
-					continue
-				}
-
-				if deathByBodyCollision(s.Head(), b) {
-					var cause string
-					if s.ID == other.ID {
-						cause = DeathCauseSnakeSelfCollision
-					} else {
-						cause = DeathCauseSnakeCollision
-					}
-
-					updates = append(updates, deathUpdate{
-						Snake: s,
-						Death: &pb.Death{
-							Turn:  frame.Turn,
-							Cause: cause,
-						},
-					})
-					break
-				}
+// collisionDeathsInFrameOrder returns a death for every snake that snake runs
+// into, checking the snakes in the order of frame, head-to-head before the
+// body of each. This is how collisions were checked before RulesetVersion5.
+func collisionDeathsInFrameOrder(snake *pb.Snake, frame *pb.GameFrame, ruleset *pb.Ruleset, ate map[string]bool) []deathUpdate {
+	var updates []deathUpdate
+	head := snake.Head()
+	for _, other := range frame.AliveSnakes() {
+		if deathByHeadCollision(snake, other, HeadToHeadOutcome(ruleset.GetEqualHeadToHead())) {
+			updates = append(updates, deathUpdate{
+				Snake: snake,
+				Death: &pb.Death{
+					Turn:         frame.Turn,
+					Cause:        DeathCauseHeadToHeadCollision,
+					EliminatedBy: other.ID,
+				},
+			})
+		}
+
+		if squadBodyCollisionAllowed(snake, other, ruleset) {
+			continue
+		}
+
+		if bodyCollision(head, other, ate) {
+			var cause, eliminatedBy string
+			if snake.ID == other.ID {
+				cause = DeathCauseSnakeSelfCollision
+			} else {
+				cause = DeathCauseSnakeCollision
+				eliminatedBy = other.ID
 			}
+			updates = append(updates, deathUpdate{
+				Snake: snake,
+				Death: &pb.Death{
+					Turn:         frame.Turn,
+					Cause:        cause,
+					EliminatedBy: eliminatedBy,
+				},
+			})
 		}
 	}
 	return updates
 }
 
+// collisionDeath returns how snake dies by running into a snake, or nil when
+// it does not. The death has no turn set. snakes are the alive snakes in order
+// of ID and heads holds them by the square of their head.
+//
+// A head that lands on a body is a body collision, whatever else is on the
+// square: snakes whose heads meet on a body all die by running into it. The
+// body of the snake itself is checked first, then the others in order of ID.
+// Only heads that meet on a square without a body collide head to head, the
+// snake is then eliminated by the longest snake that beats it there, the one
+// with the lowest ID among equally long snakes.
+func collisionDeath(snake *pb.Snake, snakes []*pb.Snake, heads map[pb.Point][]*pb.Snake, ruleset *pb.Ruleset, ate map[string]bool) *pb.Death {
+	head := snake.Head()
+	if bodyCollision(head, snake, ate) {
+		return &pb.Death{Cause: DeathCauseSnakeSelfCollision}
+	}
+	for _, other := range snakes {
+		if other.ID == snake.ID || squadBodyCollisionAllowed(snake, other, ruleset) {
+			continue
+		}
+		if bodyCollision(head, other, ate) {
+			return &pb.Death{Cause: DeathCauseSnakeCollision, EliminatedBy: other.ID}
+		}
+	}
+
+	var by *pb.Snake
+	outcome := HeadToHeadOutcome(ruleset.GetEqualHeadToHead())
+	for _, other := range heads[*head] {
+		if deathByHeadCollision(snake, other, outcome) && (by == nil || len(other.Body) > len(by.Body)) {
+			by = other
+		}
+	}
+	if by == nil {
+		return nil
+	}
+	return &pb.Death{Cause: DeathCauseHeadToHeadCollision, EliminatedBy: by.ID}
+}
+
+// bodyCollision reports whether head is on the body of other, its head not
+// included. The tail only counts when it stays on its square, see
+// checkForDeath.
+func bodyCollision(head *pb.Point, other *pb.Snake, ate map[string]bool) bool {
+	for i, b := range other.Body {
+		if i == 0 {
+			continue
+		}
+		if i == len(other.Body)-1 && ate != nil && !ate[other.ID] {
+			continue
+		}
+		if deathByBodyCollision(head, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// snakesInIDOrder returns a copy of snakes sorted by ID.
+func snakesInIDOrder(snakes []*pb.Snake) []*pb.Snake {
+	ordered := append([]*pb.Snake{}, snakes...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].ID < ordered[j].ID
+	})
+	return ordered
+}
+
+// squadWipes returns the deaths of the surviving members of every squad that
+// lost a member in updates, when the ruleset wipes out whole squads. The
+// member that died first in updates is recorded as EliminatedBy.
+func squadWipes(frame *pb.GameFrame, ruleset *pb.Ruleset, updates []deathUpdate) []deathUpdate {
+	if !ruleset.GetSquadMode() || !ruleset.GetSquadWipe() {
+		return nil
+	}
+	dead := map[string]bool{}
+	wipedBy := map[string]string{}
+	for _, u := range updates {
+		dead[u.Snake.ID] = true
+		if _, ok := wipedBy[u.Snake.Squad]; !ok && u.Snake.Squad != "" {
+			wipedBy[u.Snake.Squad] = u.Snake.ID
+		}
+	}
+
+	wipes := []deathUpdate{}
+	for _, s := range frame.AliveSnakes() {
+		by, ok := wipedBy[s.Squad]
+		if !ok || dead[s.ID] {
+			continue
+		}
+		wipes = append(wipes, deathUpdate{
+			Snake: s,
+			Death: &pb.Death{
+				Turn:         frame.Turn,
+				Cause:        DeathCauseSquadWipe,
+				EliminatedBy: by,
+			},
+		})
+	}
+	return wipes
+}
+
 func deathByHealth(health int32) bool {
 	return health <= 0
 }
 
+// deathByNotResponding reports whether a snake has failed too many requests.
+// On the first turn any failure counts, which covers both a failed /start and a
+// failed first /move.
+func deathByNotResponding(snake *pb.Snake, turn int32, ruleset *pb.Ruleset) bool {
+	if snake.ConsecutiveFailures == 0 {
+		return false
+	}
+	if turn == 1 && ruleset.GetEliminateUnresponsive() {
+		return true
+	}
+	max := ruleset.GetMaxConsecutiveFailures()
+	return max > 0 && snake.ConsecutiveFailures >= max
+}
+
+// squadBodyCollisionAllowed reports whether a snake may move through the body
+// of another snake because they are teammates in squad mode.
+func squadBodyCollisionAllowed(snake, other *pb.Snake, ruleset *pb.Ruleset) bool {
+	if !ruleset.GetSquadMode() || !ruleset.GetAllowBodyCollisions() {
+		return false
+	}
+	return snake.ID != other.ID && snake.Squad != "" && snake.Squad == other.Squad
+}
+
 func deathByBodyCollision(head, body *pb.Point) bool {
 	return head.Equal(body)
 }
@@ -89,6 +281,16 @@ func deathByOutOfBounds(head *pb.Point, width, height int32) bool {
 	return (head.X < 0) || (head.X >= width) || (head.Y < 0) || (head.Y >= height)
 }
 
-func deathByHeadCollision(snake, other *pb.Snake) bool {
-	return (other.ID != snake.ID) && (snake.Head().Equal(other.Head())) && (len(snake.Body) <= len(other.Body))
+func deathByHeadCollision(snake, other *pb.Snake, equal HeadToHeadOutcome) bool {
+	if other.ID == snake.ID || other.Head() == nil || !snake.Head().Equal(other.Head()) {
+		return false
+	}
+	switch equal {
+	case HeadToHeadBothSurvive:
+		return false
+	case HeadToHeadLongerOrDraw:
+		return len(snake.Body) < len(other.Body)
+	default:
+		return len(snake.Body) <= len(other.Body)
+	}
 }
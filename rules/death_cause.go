@@ -11,4 +11,14 @@ const (
 	DeathCauseHeadToHeadCollision = "head-collision"
 	// DeathCauseWallCollision is when a snake runs off the board
 	DeathCauseWallCollision = "wall-collision"
+	// DeathCauseObstacleCollision is when a snake runs into an obstacle inside the board
+	DeathCauseObstacleCollision = "obstacle-collision"
+	// DeathCauseNotResponding is when a snake is eliminated for failing to answer the engine
+	DeathCauseNotResponding = "not-responding"
+	// DeathCauseNoBody is when a snake has no body left to move, which only
+	// happens to snakes stored in an invalid state
+	DeathCauseNoBody = "no-body"
+	// DeathCauseSquadWipe is when a snake is eliminated because a member of
+	// its squad died and the ruleset wipes out whole squads
+	DeathCauseSquadWipe = "squad-wipe"
 )
@@ -8,14 +8,14 @@ import (
 )
 
 func TestDeathCauseStarvation(t *testing.T) {
-	updates := checkForDeath(20, 20, &pb.GameFrame{
+	updates := checkForDeath(20, 20, nil, &pb.GameFrame{
 		Turn: 3,
 		Snakes: []*pb.Snake{
 			&pb.Snake{
 				Health: 0,
 			},
 		},
-	})
+	}, StandardRuleset(), nil, true)
 	require.Len(t, updates, 1)
 	require.Equal(t, DeathCauseStarvation, updates[0].Death.Cause)
 	require.Equal(t, int32(3), updates[0].Death.Turn)
@@ -30,7 +30,7 @@ func TestDeathCauseWallCollision(t *testing.T) {
 		{X: 1, Y: 20},
 	}
 	for _, p := range points {
-		updates := checkForDeath(20, 20, &pb.GameFrame{
+		updates := checkForDeath(20, 20, nil, &pb.GameFrame{
 			Turn: 3,
 			Snakes: []*pb.Snake{
 				&pb.Snake{
@@ -38,15 +38,16 @@ func TestDeathCauseWallCollision(t *testing.T) {
 					Body:   []*pb.Point{p},
 				},
 			},
-		})
+		}, StandardRuleset(), nil, true)
 		require.Len(t, updates, 1)
 		require.Equal(t, DeathCauseWallCollision, updates[0].Death.Cause)
 		require.Equal(t, int32(3), updates[0].Death.Turn)
+		require.Empty(t, updates[0].Death.EliminatedBy)
 	}
 }
 
 func TestDeathCauseSnakeCollision(t *testing.T) {
-	updates := checkForDeath(20, 20, &pb.GameFrame{
+	updates := checkForDeath(20, 20, nil, &pb.GameFrame{
 		Turn: 3,
 		Snakes: []*pb.Snake{
 			&pb.Snake{
@@ -65,14 +66,16 @@ func TestDeathCauseSnakeCollision(t *testing.T) {
 				},
 			},
 		},
-	})
+	}, StandardRuleset(), nil, true)
 	require.Len(t, updates, 1)
 	require.Equal(t, DeathCauseSnakeCollision, updates[0].Death.Cause)
 	require.Equal(t, int32(3), updates[0].Death.Turn)
+	require.Equal(t, "1", updates[0].Snake.ID)
+	require.Equal(t, "2", updates[0].Death.EliminatedBy)
 }
 
 func TestDeathCauseHeadToHeadCollision(t *testing.T) {
-	updates := checkForDeath(20, 20, &pb.GameFrame{
+	updates := checkForDeath(20, 20, nil, &pb.GameFrame{
 		Turn: 3,
 		Snakes: []*pb.Snake{
 			&pb.Snake{
@@ -92,16 +95,18 @@ func TestDeathCauseHeadToHeadCollision(t *testing.T) {
 				},
 			},
 		},
-	})
+	}, StandardRuleset(), nil, true)
 	require.Len(t, updates, 2)
 	require.Equal(t, DeathCauseHeadToHeadCollision, updates[0].Death.Cause)
 	require.Equal(t, int32(3), updates[0].Death.Turn)
 	require.Equal(t, DeathCauseHeadToHeadCollision, updates[1].Death.Cause)
 	require.Equal(t, int32(3), updates[1].Death.Turn)
+	require.Equal(t, "2", updates[0].Death.EliminatedBy)
+	require.Equal(t, "1", updates[1].Death.EliminatedBy)
 }
 
 func TestDeathCauseSnakeSelfCollision(t *testing.T) {
-	updates := checkForDeath(20, 20, &pb.GameFrame{
+	updates := checkForDeath(20, 20, nil, &pb.GameFrame{
 		Turn: 3,
 		Snakes: []*pb.Snake{
 			&pb.Snake{
@@ -116,8 +121,346 @@ func TestDeathCauseSnakeSelfCollision(t *testing.T) {
 				},
 			},
 		},
-	})
+	}, StandardRuleset(), nil, true)
 	require.Len(t, updates, 1)
 	require.Equal(t, DeathCauseSnakeSelfCollision, updates[0].Death.Cause)
 	require.Equal(t, int32(3), updates[0].Death.Turn)
+	require.Empty(t, updates[0].Death.EliminatedBy)
+}
+
+func TestDeathCauseNotResponding(t *testing.T) {
+	ruleset := &pb.Ruleset{MaxConsecutiveFailures: 3}
+	updates := checkForDeath(20, 20, nil, &pb.GameFrame{
+		Turn: 7,
+		Snakes: []*pb.Snake{
+			&pb.Snake{
+				ID:                  "1",
+				Health:              45,
+				Body:                []*pb.Point{{X: 1, Y: 1}},
+				ConsecutiveFailures: 3,
+			},
+			&pb.Snake{
+				ID:                  "2",
+				Health:              45,
+				Body:                []*pb.Point{{X: 5, Y: 5}},
+				ConsecutiveFailures: 2,
+			},
+		},
+	}, ruleset, nil, true)
+	require.Len(t, updates, 1)
+	require.Equal(t, "1", updates[0].Snake.ID)
+	require.Equal(t, DeathCauseNotResponding, updates[0].Death.Cause)
+	require.Equal(t, int32(7), updates[0].Death.Turn)
+}
+
+func TestDeathCauseNotRespondingFirstTurn(t *testing.T) {
+	frame := &pb.GameFrame{
+		Turn: 1,
+		Snakes: []*pb.Snake{
+			&pb.Snake{
+				Health:              45,
+				Body:                []*pb.Point{{X: 1, Y: 1}},
+				ConsecutiveFailures: 1,
+			},
+		},
+	}
+	updates := checkForDeath(20, 20, nil, frame, StandardRuleset(), nil, true)
+	require.Len(t, updates, 0)
+
+	updates = checkForDeath(20, 20, nil, frame, &pb.Ruleset{EliminateUnresponsive: true}, nil, true)
+	require.Len(t, updates, 1)
+	require.Equal(t, DeathCauseNotResponding, updates[0].Death.Cause)
+	require.Equal(t, int32(1), updates[0].Death.Turn)
+}
+
+func headToHeadFrame(otherLength int) *pb.GameFrame {
+	other := &pb.Snake{
+		ID:     "2",
+		Health: 56,
+		Body:   []*pb.Point{{X: 6, Y: 5}},
+	}
+	for i := 1; i < otherLength; i++ {
+		other.Body = append(other.Body, &pb.Point{X: 6, Y: int32(5 + i)})
+	}
+	return &pb.GameFrame{
+		Turn: 3,
+		Snakes: []*pb.Snake{
+			&pb.Snake{
+				ID:     "1",
+				Health: 45,
+				Body: []*pb.Point{
+					{X: 6, Y: 5},
+					{X: 5, Y: 5},
+				},
+			},
+			other,
+		},
+	}
+}
+
+func headToHeadRuleset(outcome HeadToHeadOutcome) *pb.Ruleset {
+	return newRuleset(&pb.Ruleset{EqualHeadToHead: string(outcome)})
+}
+
+func TestHeadToHeadBothDie(t *testing.T) {
+	updates := checkForDeath(20, 20, nil, headToHeadFrame(2), headToHeadRuleset(HeadToHeadBothDie), nil, true)
+	require.Len(t, updates, 2)
+
+	updates = checkForDeath(20, 20, nil, headToHeadFrame(3), headToHeadRuleset(HeadToHeadBothDie), nil, true)
+	require.Len(t, updates, 1)
+	require.Equal(t, "1", updates[0].Snake.ID)
+	require.Equal(t, DeathCauseHeadToHeadCollision, updates[0].Death.Cause)
+}
+
+func TestHeadToHeadBothSurvive(t *testing.T) {
+	updates := checkForDeath(20, 20, nil, headToHeadFrame(2), headToHeadRuleset(HeadToHeadBothSurvive), nil, true)
+	require.Len(t, updates, 0)
+
+	updates = checkForDeath(20, 20, nil, headToHeadFrame(3), headToHeadRuleset(HeadToHeadBothSurvive), nil, true)
+	require.Len(t, updates, 0)
+}
+
+func TestHeadToHeadLongerOrDraw(t *testing.T) {
+	updates := checkForDeath(20, 20, nil, headToHeadFrame(2), headToHeadRuleset(HeadToHeadLongerOrDraw), nil, true)
+	require.Len(t, updates, 0)
+
+	updates = checkForDeath(20, 20, nil, headToHeadFrame(3), headToHeadRuleset(HeadToHeadLongerOrDraw), nil, true)
+	require.Len(t, updates, 1)
+	require.Equal(t, "1", updates[0].Snake.ID)
+	require.Equal(t, DeathCauseHeadToHeadCollision, updates[0].Death.Cause)
+}
+
+func TestDeathCauseObstacleCollision(t *testing.T) {
+	obstacles := []*pb.Point{{X: 5, Y: 4}, {X: 5, Y: 5}, {X: 5, Y: 6}}
+	frame := &pb.GameFrame{
+		Turn: 3,
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 45, Body: []*pb.Point{{X: 5, Y: 5}, {X: 4, Y: 5}}},
+			{ID: "2", Health: 45, Body: []*pb.Point{{X: 6, Y: 7}, {X: 5, Y: 7}}},
+		},
+	}
+	updates := checkForDeath(10, 10, obstacles, frame, StandardRuleset(), nil, true)
+	require.Len(t, updates, 1)
+	require.Equal(t, "1", updates[0].Snake.ID)
+	require.Equal(t, DeathCauseObstacleCollision, updates[0].Death.Cause)
+}
+
+func squadFrame(squad1, squad2 string) *pb.GameFrame {
+	return &pb.GameFrame{
+		Turn: 3,
+		Snakes: []*pb.Snake{
+			&pb.Snake{
+				ID:     "1",
+				Squad:  squad1,
+				Health: 45,
+				Body: []*pb.Point{
+					{X: 5, Y: 5},
+					{X: 5, Y: 6},
+				},
+			},
+			&pb.Snake{
+				ID:     "2",
+				Squad:  squad2,
+				Health: 56,
+				Body: []*pb.Point{
+					{X: 6, Y: 5},
+					{X: 5, Y: 5},
+					{X: 4, Y: 5},
+				},
+			},
+		},
+	}
+}
+
+func TestSquadTeammatePassesThroughBody(t *testing.T) {
+	ruleset := &pb.Ruleset{SquadMode: true, AllowBodyCollisions: true}
+	updates := checkForDeath(20, 20, nil, squadFrame("red", "red"), ruleset, nil, true)
+	require.Len(t, updates, 0)
+}
+
+func TestSquadOpponentBodyCollision(t *testing.T) {
+	ruleset := &pb.Ruleset{SquadMode: true, AllowBodyCollisions: true}
+	updates := checkForDeath(20, 20, nil, squadFrame("red", "blue"), ruleset, nil, true)
+	require.Len(t, updates, 1)
+	require.Equal(t, DeathCauseSnakeCollision, updates[0].Death.Cause)
+}
+
+func TestSquadBodyCollisionsNotAllowed(t *testing.T) {
+	updates := checkForDeath(20, 20, nil, squadFrame("red", "red"), &pb.Ruleset{SquadMode: true}, nil, true)
+	require.Len(t, updates, 1)
+	require.Equal(t, DeathCauseSnakeCollision, updates[0].Death.Cause)
+
+	// Squads are ignored outside squad mode.
+	updates = checkForDeath(20, 20, nil, squadFrame("red", "red"), &pb.Ruleset{AllowBodyCollisions: true}, nil, true)
+	require.Len(t, updates, 1)
+}
+
+// squadsFrame returns a 2v2 frame where snake a1 of the red squad has run
+// off the board.
+func squadsFrame() *pb.GameFrame {
+	snake := func(id, squad string, x int32) *pb.Snake {
+		return &pb.Snake{
+			ID:     id,
+			Squad:  squad,
+			Health: 50,
+			Body:   []*pb.Point{{X: x, Y: 5}, {X: x, Y: 6}},
+		}
+	}
+	frame := &pb.GameFrame{
+		Turn: 3,
+		Snakes: []*pb.Snake{
+			snake("a1", "red", 1),
+			snake("a2", "red", 3),
+			snake("b1", "blue", 5),
+			snake("b2", "blue", 7),
+		},
+	}
+	frame.Snakes[0].Body[0].X = -1
+	return frame
+}
+
+func TestSquadWipe(t *testing.T) {
+	ruleset := &pb.Ruleset{SquadMode: true, SquadWipe: true}
+	updates := checkForDeath(10, 10, nil, squadsFrame(), ruleset, nil, true)
+	require.Len(t, updates, 2)
+	require.Equal(t, "a1", updates[0].Snake.ID)
+	require.Equal(t, DeathCauseWallCollision, updates[0].Death.Cause)
+	require.Equal(t, "a2", updates[1].Snake.ID)
+	require.Equal(t, DeathCauseSquadWipe, updates[1].Death.Cause)
+	require.Equal(t, "a1", updates[1].Death.EliminatedBy)
+}
+
+func TestSquadMembersSurviveIndependently(t *testing.T) {
+	for _, ruleset := range []*pb.Ruleset{
+		{SquadMode: true},
+		// Squads are ignored outside squad mode.
+		{SquadWipe: true},
+	} {
+		updates := checkForDeath(10, 10, nil, squadsFrame(), ruleset, nil, true)
+		require.Len(t, updates, 1)
+		require.Equal(t, "a1", updates[0].Snake.ID)
+	}
+}
+
+// tailFrame returns a frame where snake b has moved onto the square the tail
+// of snake a was on before a moved.
+func tailFrame() *pb.GameFrame {
+	return &pb.GameFrame{
+		Turn: 3,
+		Snakes: []*pb.Snake{
+			{
+				ID:     "a",
+				Health: 45,
+				Body:   []*pb.Point{{X: 5, Y: 4}, {X: 5, Y: 5}, {X: 6, Y: 5}, {X: 7, Y: 5}},
+			},
+			{
+				ID:     "b",
+				Health: 45,
+				Body:   []*pb.Point{{X: 7, Y: 5}, {X: 8, Y: 5}, {X: 9, Y: 5}, {X: 10, Y: 5}},
+			},
+		},
+	}
+}
+
+func TestDeathTailMovesOutOfTheWay(t *testing.T) {
+	updates := checkForDeath(20, 20, nil, tailFrame(), StandardRuleset(), map[string]bool{}, true)
+	require.Len(t, updates, 0)
+}
+
+func TestDeathTailOfSnakeThatAte(t *testing.T) {
+	updates := checkForDeath(20, 20, nil, tailFrame(), StandardRuleset(), map[string]bool{"a": true}, true)
+	require.Len(t, updates, 1)
+	require.Equal(t, "b", updates[0].Snake.ID)
+	require.Equal(t, DeathCauseSnakeCollision, updates[0].Death.Cause)
+	require.Equal(t, "a", updates[0].Death.EliminatedBy)
+}
+
+func TestDeathTailBeforeRulesetVersion3(t *testing.T) {
+	updates := checkForDeath(20, 20, nil, tailFrame(), StandardRuleset(), nil, true)
+	require.Len(t, updates, 1)
+	require.Equal(t, "b", updates[0].Snake.ID)
+}
+
+// pileupFrame has A and B, 3 long, and C, 2 long, with their heads on (5,5).
+// D, whose head is elsewhere, lies across the square when body is set.
+func pileupFrame(body bool) *pb.GameFrame {
+	frame := &pb.GameFrame{
+		Turn: 3,
+		Snakes: []*pb.Snake{
+			{ID: "C", Health: 45, Body: []*pb.Point{{X: 5, Y: 5}, {X: 5, Y: 6}}},
+			{ID: "B", Health: 45, Body: []*pb.Point{{X: 5, Y: 5}, {X: 6, Y: 5}, {X: 7, Y: 5}}},
+			{ID: "A", Health: 45, Body: []*pb.Point{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}}},
+		},
+	}
+	if body {
+		frame.Snakes = append(frame.Snakes, &pb.Snake{ID: "D", Health: 45, Body: []*pb.Point{{X: 5, Y: 3}, {X: 5, Y: 4}, {X: 5, Y: 5}, {X: 5, Y: 6}}})
+	}
+	return frame
+}
+
+// pileupDeaths returns the deaths checkForDeath finds in frame by snake ID,
+// with the snakes of frame in every order, checking each order gives the same.
+func pileupDeaths(t *testing.T, frame *pb.GameFrame, ruleset *pb.Ruleset) map[string]*pb.Death {
+	var first map[string]*pb.Death
+	snakes := frame.Snakes
+	for i := range snakes {
+		// Rotate the snakes and check both directions.
+		for _, reverse := range []bool{false, true} {
+			ordered := append(append([]*pb.Snake{}, snakes[i:]...), snakes[:i]...)
+			if reverse {
+				for l, r := 0, len(ordered)-1; l < r; l, r = l+1, r-1 {
+					ordered[l], ordered[r] = ordered[r], ordered[l]
+				}
+			}
+			deaths := map[string]*pb.Death{}
+			for _, u := range checkForDeath(20, 20, nil, &pb.GameFrame{Turn: frame.Turn, Snakes: ordered}, ruleset, nil, true) {
+				deaths[u.Snake.ID] = u.Death
+			}
+			if first == nil {
+				first = deaths
+			}
+			require.Equal(t, first, deaths)
+		}
+	}
+	return first
+}
+
+func TestThreeWayPileup(t *testing.T) {
+	deaths := pileupDeaths(t, pileupFrame(false), headToHeadRuleset(HeadToHeadBothDie))
+	require.Equal(t, map[string]*pb.Death{
+		"A": {Turn: 3, Cause: DeathCauseHeadToHeadCollision, EliminatedBy: "B"},
+		"B": {Turn: 3, Cause: DeathCauseHeadToHeadCollision, EliminatedBy: "A"},
+		// A and B are equally long, the lowest ID wins.
+		"C": {Turn: 3, Cause: DeathCauseHeadToHeadCollision, EliminatedBy: "A"},
+	}, deaths)
+
+	deaths = pileupDeaths(t, pileupFrame(false), headToHeadRuleset(HeadToHeadLongerOrDraw))
+	require.Equal(t, map[string]*pb.Death{
+		"C": {Turn: 3, Cause: DeathCauseHeadToHeadCollision, EliminatedBy: "A"},
+	}, deaths)
+}
+
+func TestThreeWayPileupOnBody(t *testing.T) {
+	// Heads that meet on a body run into the body, not into each other.
+	deaths := pileupDeaths(t, pileupFrame(true), headToHeadRuleset(HeadToHeadBothDie))
+	require.Equal(t, map[string]*pb.Death{
+		"A": {Turn: 3, Cause: DeathCauseSnakeCollision, EliminatedBy: "D"},
+		"B": {Turn: 3, Cause: DeathCauseSnakeCollision, EliminatedBy: "D"},
+		"C": {Turn: 3, Cause: DeathCauseSnakeCollision, EliminatedBy: "D"},
+	}, deaths)
+}
+
+func TestSelfCollisionBeforeHeadToHead(t *testing.T) {
+	// A runs into its own body on the square where B's head is.
+	deaths := pileupDeaths(t, &pb.GameFrame{
+		Turn: 3,
+		Snakes: []*pb.Snake{
+			{ID: "B", Health: 45, Body: []*pb.Point{{X: 4, Y: 4}, {X: 4, Y: 5}, {X: 4, Y: 6}, {X: 4, Y: 7}, {X: 4, Y: 8}}},
+			{ID: "A", Health: 45, Body: []*pb.Point{{X: 4, Y: 4}, {X: 3, Y: 4}, {X: 3, Y: 3}, {X: 4, Y: 3}, {X: 4, Y: 4}, {X: 5, Y: 4}}},
+		},
+	}, headToHeadRuleset(HeadToHeadBothDie))
+	require.Equal(t, map[string]*pb.Death{
+		"A": {Turn: 3, Cause: DeathCauseSnakeSelfCollision},
+		"B": {Turn: 3, Cause: DeathCauseSnakeCollision, EliminatedBy: "A"},
+	}, deaths)
 }
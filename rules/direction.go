@@ -0,0 +1,77 @@
+package rules
+
+import "github.com/battlesnakeio/engine/controller/pb"
+
+// StartingDirection decides which way a snake placed on the board faces. A
+// placed snake is stacked on a single square and has no direction of its own,
+// it keeps moving the way it faces, see pb.Snake.Facing, until it makes a move
+// of its own.
+type StartingDirection string
+
+const (
+	// StartingDirectionTowardCenter faces snakes along the axis on which they
+	// are furthest from the center of the board, toward the center. This is
+	// the standard direction.
+	StartingDirectionTowardCenter StartingDirection = "toward-center"
+	// StartingDirectionAwayFromWall faces snakes away from the wall nearest
+	// to them.
+	StartingDirectionAwayFromWall StartingDirection = "away-from-wall"
+	// StartingDirectionNone leaves snakes without a direction, they move up
+	// until they make a move of their own.
+	StartingDirectionNone StartingDirection = "none"
+)
+
+// startingFacing returns the direction a snake placed on p faces, "" for
+// none. Ties are broken in a fixed order, so the same square always gives the
+// same direction.
+func startingFacing(direction StartingDirection, p *pb.Point, width, height int32) string {
+	switch direction {
+	case StartingDirectionNone:
+		return ""
+	case StartingDirectionAwayFromWall:
+		return string(awayFromWall(p, width, height))
+	}
+	return string(towardCenter(p, width, height))
+}
+
+func towardCenter(p *pb.Point, width, height int32) pb.Move {
+	// Distances to the center are doubled, so boards of even size have a
+	// center on whole numbers too.
+	dx := width - 1 - 2*p.X
+	dy := height - 1 - 2*p.Y
+	switch {
+	case abs(dx) > abs(dy) && dx > 0:
+		return pb.MoveRight
+	case abs(dx) > abs(dy):
+		return pb.MoveLeft
+	case dy > 0:
+		return pb.MoveDown
+	}
+	return pb.MoveUp
+}
+
+func awayFromWall(p *pb.Point, width, height int32) pb.Move {
+	walls := []struct {
+		distance int32
+		away     pb.Move
+	}{
+		{p.X, pb.MoveRight},
+		{width - 1 - p.X, pb.MoveLeft},
+		{p.Y, pb.MoveDown},
+		{height - 1 - p.Y, pb.MoveUp},
+	}
+	nearest := walls[0]
+	for _, w := range walls[1:] {
+		if w.distance < nearest.distance {
+			nearest = w
+		}
+	}
+	return nearest.away
+}
+
+func abs(x int32) int32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,53 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartingFacing(t *testing.T) {
+	for _, tc := range []struct {
+		direction StartingDirection
+		p         *pb.Point
+		want      string
+	}{
+		{StartingDirectionTowardCenter, &pb.Point{X: 0, Y: 3}, "right"},
+		{StartingDirectionTowardCenter, &pb.Point{X: 6, Y: 2}, "left"},
+		{StartingDirectionTowardCenter, &pb.Point{X: 3, Y: 0}, "down"},
+		{StartingDirectionTowardCenter, &pb.Point{X: 2, Y: 6}, "up"},
+		// Corners are as far from the center either way.
+		{StartingDirectionTowardCenter, &pb.Point{X: 0, Y: 0}, "down"},
+		{StartingDirectionTowardCenter, &pb.Point{X: 6, Y: 6}, "up"},
+		{StartingDirectionTowardCenter, &pb.Point{X: 3, Y: 3}, "up"},
+		{StartingDirectionAwayFromWall, &pb.Point{X: 1, Y: 3}, "right"},
+		{StartingDirectionAwayFromWall, &pb.Point{X: 5, Y: 3}, "left"},
+		{StartingDirectionAwayFromWall, &pb.Point{X: 3, Y: 1}, "down"},
+		{StartingDirectionAwayFromWall, &pb.Point{X: 3, Y: 5}, "up"},
+		{StartingDirectionAwayFromWall, &pb.Point{X: 0, Y: 0}, "right"},
+		{StartingDirectionNone, &pb.Point{X: 0, Y: 0}, ""},
+		// Unset and unknown directions are taken as the standard one.
+		{"", &pb.Point{X: 0, Y: 3}, "right"},
+		{"diagonal", &pb.Point{X: 0, Y: 3}, "right"},
+	} {
+		require.Equal(t, tc.want, startingFacing(tc.direction, tc.p, 7, 7), "%s %v", tc.direction, tc.p)
+	}
+}
+
+func TestCornerSpawnsMoveInward(t *testing.T) {
+	for _, direction := range []StartingDirection{StartingDirectionTowardCenter, StartingDirectionAwayFromWall} {
+		// Four snakes fill a 2x2 board, every snake starts in a corner.
+		_, frames, err := CreateInitialGame(&pb.CreateRequest{
+			Width:   2,
+			Height:  2,
+			Snakes:  []*pb.SnakeOptions{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}},
+			Ruleset: &pb.Ruleset{StartingDirection: string(direction)},
+		})
+		require.NoError(t, err)
+		for _, s := range frames[0].Snakes {
+			s.DefaultMove()
+			require.False(t, deathByOutOfBounds(s.Head(), 2, 2), "%s: snake %s moved %s off the board", direction, s.ID, s.Facing)
+		}
+	}
+}
@@ -6,9 +6,31 @@ import (
 	"time"
 
 	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
 	log "github.com/sirupsen/logrus"
 )
 
+// AbortFrame returns the frame an aborted game ends on. It repeats the board of
+// last on the next turn with a single FrameEventAborted event carrying the
+// reason, so viewers can tell the game did not end by its rules. No moves are
+// made on it.
+func AbortFrame(last *pb.GameFrame, reason string) *pb.GameFrame {
+	frame := proto.Clone(last).(*pb.GameFrame)
+	frame.Turn = last.Turn + 1
+	frame.Events = []*pb.Event{{Type: FrameEventAborted, Cause: reason}}
+	return frame
+}
+
+// IsAbortFrame reports whether frame is the frame an aborted game ended on.
+func IsAbortFrame(frame *pb.GameFrame) bool {
+	for _, e := range frame.Events {
+		if e.Type == FrameEventAborted {
+			return true
+		}
+	}
+	return false
+}
+
 // NotifyGameEnd sends the /end requests to all the snakes.
 func NotifyGameEnd(game *pb.Game, frame *pb.GameFrame) {
 	netClient := createClient(200 * time.Millisecond)
@@ -24,9 +46,11 @@ func NotifyGameEnd(game *pb.Game, frame *pb.GameFrame) {
 		}
 
 		buf := bytes.NewBuffer(data)
-		_, err = netClient.Post(getURL(s.URL, "end"), "application/json", buf)
+		resp, err := netClient.Post(getURL(s.URL, "end"), "application/json", buf)
 		if err != nil {
 			log.WithError(err).WithField("snakeID", s.ID).Error("error POSTing to /end")
+			continue
 		}
+		resp.Body.Close()
 	}
 }
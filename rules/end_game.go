@@ -3,6 +3,8 @@ package rules
 import "github.com/battlesnakeio/engine/controller/pb"
 
 // CheckForGameOver checks if the game has ended. End condition is dependent on game mode.
+// A single player game runs until its snake dies, a multi player game until
+// at most one snake is left.
 func CheckForGameOver(mode GameMode, gt *pb.GameFrame) bool {
 	aliveSnakes := gt.AliveSnakes()
 	if mode == GameModeSinglePlayer {
@@ -10,3 +12,41 @@ func CheckForGameOver(mode GameMode, gt *pb.GameFrame) bool {
 	}
 	return len(aliveSnakes) == 1 || len(aliveSnakes) == 0
 }
+
+// GameResult describes how a finished game ended.
+type GameResult struct {
+	// Solo is set for single player games. They have no winner, the snake is
+	// scored on the number of turns it survived instead.
+	Solo bool
+	// Score is the last turn of a single player game.
+	Score int32
+	// WinnerID is the ID of the last snake alive in a multi player game.
+	WinnerID string
+	// Draw is set when the last snakes of a multi player game died on the same
+	// turn.
+	Draw bool
+}
+
+// GetGameResult returns the result of a game that has ended on frame gt.
+func GetGameResult(mode GameMode, gt *pb.GameFrame) *GameResult {
+	if mode == GameModeSinglePlayer {
+		return &GameResult{Solo: true, Score: gt.Turn}
+	}
+	alive := gt.AliveSnakes()
+	if len(alive) == 1 {
+		return &GameResult{WinnerID: alive[0].ID}
+	}
+	return &GameResult{Draw: true}
+}
+
+// BoardFill returns the fraction of the board's cells occupied by alive
+// snakes. Stacked segments count once. A stalemated game where snakes have
+// boxed each other in shows up as a fill close to 1.
+func BoardFill(frame *pb.GameFrame, width, height int32) float64 {
+	cells := int(width) * int(height)
+	if cells <= 0 {
+		return 0
+	}
+	occupied := getUniqOccupiedPoints(nil, frame.AliveSnakes())
+	return float64(len(occupied)) / float64(cells)
+}
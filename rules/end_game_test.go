@@ -22,3 +22,52 @@ func TestCheckForGameOver_SinglePlayer(t *testing.T) {
 	res = CheckForGameOver(GameModeSinglePlayer, gameFrame)
 	require.False(t, res)
 }
+
+func TestGetGameResult_SinglePlayer(t *testing.T) {
+	gameFrame := &pb.GameFrame{
+		Turn: 42,
+		Snakes: []*pb.Snake{
+			{ID: "solo", Death: &pb.Death{Cause: DeathCauseWallCollision, Turn: 42}},
+		},
+	}
+	require.True(t, CheckForGameOver(GameModeSinglePlayer, gameFrame))
+	res := GetGameResult(GameModeSinglePlayer, gameFrame)
+	require.Equal(t, &GameResult{Solo: true, Score: 42}, res)
+}
+
+func TestGetGameResult_MultiPlayer(t *testing.T) {
+	gameFrame := &pb.GameFrame{
+		Turn: 10,
+		Snakes: []*pb.Snake{
+			{ID: "a"},
+			{ID: "b", Death: &pb.Death{Turn: 10}},
+		},
+	}
+	res := GetGameResult(GameModeMultiPlayer, gameFrame)
+	require.Equal(t, &GameResult{WinnerID: "a"}, res)
+
+	gameFrame.Snakes[0].Death = &pb.Death{Turn: 10}
+	res = GetGameResult(GameModeMultiPlayer, gameFrame)
+	require.Equal(t, &GameResult{Draw: true}, res)
+}
+
+func TestBoardFill(t *testing.T) {
+	gameFrame := &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			{Body: []*pb.Point{{X: 0, Y: 0}, {X: 0, Y: 0}, {X: 1, Y: 0}}},
+			{Body: []*pb.Point{{X: 1, Y: 1}}, Death: &pb.Death{}},
+		},
+	}
+	require.Equal(t, 0.5, BoardFill(gameFrame, 2, 2))
+	require.Equal(t, 0.0, BoardFill(gameFrame, 0, 0))
+}
+
+func TestBoardFillFullyPacked(t *testing.T) {
+	gameFrame := &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			{Body: []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}},
+			{Body: []*pb.Point{{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 0, Y: 1}}},
+		},
+	}
+	require.Equal(t, 1.0, BoardFill(gameFrame, 3, 2))
+}
@@ -0,0 +1,42 @@
+package rules
+
+import "errors"
+
+var (
+	// ErrNilFrame is returned when a tick is run without a previous frame.
+	// The game can not continue, this is fatal.
+	ErrNilFrame = errors.New("rules: invalid state, previous frame is nil")
+	// ErrTickTimeout is returned when a tick does not finish in time. The
+	// previous frame is still valid, so the tick may be retried.
+	ErrTickTimeout = errors.New("rules: tick did not finish in time")
+	// ErrInvalidBoard is returned when a game can not be played on the
+	// requested board, e.g. because the snakes do not fit.
+	ErrInvalidBoard = errors.New("rules: invalid board")
+	// ErrInvalidTimeout is returned when a game is created with a snake
+	// timeout that leaves snakes no time to move.
+	ErrInvalidTimeout = errors.New("rules: invalid snake timeout")
+	// ErrDuplicateSnakeID is returned when a game is created with two snakes
+	// that have the same ID.
+	ErrDuplicateSnakeID = errors.New("rules: duplicate snake id")
+	// ErrUnknownPreset is returned for board presets that do not exist.
+	ErrUnknownPreset = errors.New("rules: unknown board preset")
+	// ErrInvalidSnakeURL is returned when a snake can not be reached because
+	// its URL is not valid.
+	ErrInvalidSnakeURL = errors.New("rules: invalid snake URL")
+	// ErrUnknownRulesetVersion is returned for games created with a ruleset
+	// version this engine does not know. The game can not be run, this is
+	// fatal.
+	ErrUnknownRulesetVersion = errors.New("rules: unknown ruleset version")
+	// ErrReplayMismatch is returned when a replayed game does not match the
+	// stored frames.
+	ErrReplayMismatch = errors.New("rules: replay does not match")
+	// ErrMoveCancelled is set on the update of a snake that was not waited
+	// for, the snake makes its default move.
+	ErrMoveCancelled = errors.New("rules: move cancelled")
+	// ErrBadStatus is returned when a snake answers a move request with a
+	// status other than 200 OK.
+	ErrBadStatus = errors.New("rules: snake responded with a bad status")
+	// ErrInvalidMove is returned for move strings that do not name one of
+	// the moves a snake can make.
+	ErrInvalidMove = errors.New("rules: invalid move")
+)
@@ -0,0 +1,101 @@
+package rules
+
+import (
+	"sync"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+)
+
+// Event is something that happened in a game that external services may want
+// to know about.
+type Event interface {
+	// EventType names the kind of event, e.g. "snake-eliminated".
+	EventType() string
+}
+
+// EventSink receives the events emitted while games are played. Events are
+// emitted by EmitFrameEvents once the frame they happened on is stored, sinks
+// should not block since the game waits for them.
+type EventSink interface {
+	Emit(Event)
+}
+
+// Types of the events recorded on a frame. The events of a turn are recorded in
+// the order the tick processes them: deaths, eating, food spawning and finally
+// respawns. Within a phase snakes are handled in the order of the frame.
+const (
+	FrameEventDied        = "died"
+	FrameEventAteFood     = "ate-food"
+	FrameEventFoodSpawned = "food-spawned"
+	FrameEventRespawned   = "respawned"
+	// FrameEventAborted is the only event of the frame an aborted game ends
+	// on, its Cause is the reason the game was aborted. See AbortFrame.
+	FrameEventAborted = "aborted"
+)
+
+// EventTypeSnakeEliminated is the type of SnakeEliminatedEvent.
+const EventTypeSnakeEliminated = "snake-eliminated"
+
+// SnakeEliminatedEvent is emitted once for every snake, for the frame of the
+// turn it died on.
+type SnakeEliminatedEvent struct {
+	GameID  string
+	SnakeID string
+	Turn    int32
+	Cause   string
+	Length  int
+}
+
+// EventType returns EventTypeSnakeEliminated.
+func (SnakeEliminatedEvent) EventType() string {
+	return EventTypeSnakeEliminated
+}
+
+var (
+	eventSink     EventSink = nopEventSink{}
+	eventSinkLock sync.RWMutex
+)
+
+// SetEventSink replaces the sink events are emitted to. Passing nil discards
+// events, which is the default.
+func SetEventSink(sink EventSink) {
+	eventSinkLock.Lock()
+	defer eventSinkLock.Unlock()
+	if sink == nil {
+		sink = nopEventSink{}
+	}
+	eventSink = sink
+}
+
+// EmitFrameEvents emits the events of frame, a frame of game, to the event
+// sink. It is called once the frame is stored, so a tick that is given up on
+// or a frame that is not accepted emits nothing.
+func EmitFrameEvents(game *pb.Game, frame *pb.GameFrame) {
+	for _, e := range frame.Events {
+		if e.Type != FrameEventDied {
+			continue
+		}
+		s := findSnake(frame, e.SnakeID)
+		if s == nil || s.Death == nil {
+			continue
+		}
+		emitEvent(SnakeEliminatedEvent{
+			GameID:  game.ID,
+			SnakeID: s.ID,
+			Turn:    s.Death.Turn,
+			Cause:   s.Death.Cause,
+			Length:  len(s.Body),
+		})
+	}
+}
+
+func emitEvent(e Event) {
+	eventSinkLock.RLock()
+	sink := eventSink
+	eventSinkLock.RUnlock()
+	sink.Emit(e)
+}
+
+type nopEventSink struct{}
+
+func (nopEventSink) Emit(Event) {}
@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSink struct {
+	events []Event
+	lock   sync.Mutex
+}
+
+func (s *recordingSink) Emit(e Event) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.events = append(s.events, e)
+}
+
+func TestSnakeEliminatedEmittedOnce(t *testing.T) {
+	sink := &recordingSink{}
+	SetEventSink(sink)
+	defer SetEventSink(nil)
+
+	game := &pb.Game{ID: "game", Width: 20, Height: 20}
+	frame := &pb.GameFrame{
+		Turn: 5,
+		Snakes: []*pb.Snake{
+			{
+				ID:     "wall",
+				Health: 50,
+				Body: []*pb.Point{
+					{X: 1, Y: 0},
+					{X: 1, Y: 1},
+					{X: 1, Y: 2},
+				},
+			},
+			{
+				ID:     "alive",
+				Health: 50,
+				Body: []*pb.Point{
+					{X: 5, Y: 5},
+					{X: 5, Y: 6},
+					{X: 5, Y: 7},
+				},
+			},
+		},
+	}
+
+	var err error
+	for i := 0; i < 3; i++ {
+		emitted := len(sink.events)
+		frame, err = GameTick(context.Background(), game, frame)
+		require.NoError(t, err)
+		// The tick itself emits nothing, the events are emitted once the
+		// frame is stored.
+		require.Len(t, sink.events, emitted)
+		EmitFrameEvents(game, frame)
+	}
+
+	require.Len(t, sink.events, 1)
+	require.Equal(t, SnakeEliminatedEvent{
+		GameID:  "game",
+		SnakeID: "wall",
+		Turn:    6,
+		Cause:   DeathCauseWallCollision,
+		Length:  4,
+	}, sink.events[0])
+	require.Equal(t, EventTypeSnakeEliminated, sink.events[0].EventType())
+}
@@ -0,0 +1,196 @@
+package rules
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+)
+
+// FoodPlacer picks the point new food is spawned at. It returns nil when there
+// is no room left for food on the board.
+type FoodPlacer interface {
+	PlaceFood(width, height int32, food []*pb.Point, snakes []*pb.Snake) *pb.Point
+}
+
+// noFoodPlacer never places food. It is used for the turns before food starts
+// spawning.
+type noFoodPlacer struct{}
+
+func (noFoodPlacer) PlaceFood(width, height int32, food []*pb.Point, snakes []*pb.Snake) *pb.Point {
+	return nil
+}
+
+// foodPlacerForTurn returns the placer used to replace eaten food on a turn.
+// Before the ruleset's FoodSpawnStartTurn only the initial food is kept on the
+// board. Food is placed relative to the hazards according to the ruleset's
+// HazardFoodPlacement. Food is placed by placer when it is set, see
+// WithFoodPlacer, otherwise at random, drawing from the seed of games created
+// with one, see seededRand.
+func foodPlacerForTurn(ruleset *pb.Ruleset, seed int64, turn int32, hazards []*pb.Point, placer FoodPlacer) FoodPlacer {
+	if turn < ruleset.GetFoodSpawnStartTurn() {
+		return noFoodPlacer{}
+	}
+	r := seededRand(seed, turn)
+	if placer == nil {
+		placer = RandomFoodPlacer{Rand: r}
+	}
+	return withHazardPlacement(placer, ruleset, hazards, r)
+}
+
+// seededRand returns the source food is placed from on a turn of a game
+// created with seed, or nil for games without a seed. Every turn gets its own
+// source, so the food of a turn does not depend on the worker that played
+// the turns before it.
+func seededRand(seed int64, turn int32) *rand.Rand {
+	if seed == 0 {
+		return nil
+	}
+	return rand.New(rand.NewSource(seed ^ int64(turn)<<32))
+}
+
+// HazardFoodPlacement decides where food spawns relative to hazards. When it
+// is not set hazards are ignored and food spawns on any unoccupied square.
+type HazardFoodPlacement string
+
+const (
+	// HazardFoodExclude never spawns food under a hazard.
+	HazardFoodExclude HazardFoodPlacement = "exclude"
+	// HazardFoodAvoid only spawns food under a hazard when every other square
+	// is occupied.
+	HazardFoodAvoid HazardFoodPlacement = "avoid"
+	// HazardFoodPrefer spawns food under a hazard whenever a hazard square is
+	// unoccupied.
+	HazardFoodPrefer HazardFoodPlacement = "prefer"
+)
+
+// withHazardPlacement returns placer, restricted to the ruleset's
+// HazardFoodPlacement. Hazards are picked from r, or from the global source
+// when r is nil.
+func withHazardPlacement(placer FoodPlacer, ruleset *pb.Ruleset, hazards []*pb.Point, r *rand.Rand) FoodPlacer {
+	placement := HazardFoodPlacement(ruleset.GetHazardFoodPlacement())
+	if placement == "" || len(hazards) == 0 {
+		return placer
+	}
+	return hazardFoodPlacer{placer: placer, placement: placement, hazards: hazards, rand: r}
+}
+
+// hazardFoodPlacer places food relative to hazards. Hazards are passed to the
+// wrapped placer as food, so it treats them as occupied squares.
+type hazardFoodPlacer struct {
+	placer    FoodPlacer
+	placement HazardFoodPlacement
+	hazards   []*pb.Point
+	rand      *rand.Rand
+}
+
+func (hp hazardFoodPlacer) PlaceFood(width, height int32, food []*pb.Point, snakes []*pb.Snake) *pb.Point {
+	occupied := append(append([]*pb.Point{}, food...), hp.hazards...)
+	switch hp.placement {
+	case HazardFoodExclude:
+		return hp.placer.PlaceFood(width, height, occupied, snakes)
+	case HazardFoodAvoid:
+		if p := hp.placer.PlaceFood(width, height, occupied, snakes); p != nil {
+			return p
+		}
+	case HazardFoodPrefer:
+		if p := getUnoccupiedPointIn(hp.rand, hp.hazards, width, height, food, snakes); p != nil {
+			return p
+		}
+	}
+	return hp.placer.PlaceFood(width, height, food, snakes)
+}
+
+// withObstacles returns placer, never placing food on obstacles.
+func withObstacles(placer FoodPlacer, obstacles []*pb.Point) FoodPlacer {
+	if len(obstacles) == 0 {
+		return placer
+	}
+	return obstacleFoodPlacer{placer: placer, obstacles: obstacles}
+}
+
+// obstacleFoodPlacer passes obstacles to the wrapped placer as food, so it
+// treats them as occupied squares.
+type obstacleFoodPlacer struct {
+	placer    FoodPlacer
+	obstacles []*pb.Point
+}
+
+func (op obstacleFoodPlacer) PlaceFood(width, height int32, food []*pb.Point, snakes []*pb.Snake) *pb.Point {
+	occupied := append(append([]*pb.Point{}, food...), op.obstacles...)
+	return op.placer.PlaceFood(width, height, occupied, snakes)
+}
+
+// RandomFoodPlacer places food on a random unoccupied point.
+type RandomFoodPlacer struct {
+	// Rand is the source points are drawn from, the global source is used
+	// when it is nil.
+	Rand *rand.Rand
+}
+
+// PlaceFood returns a random unoccupied point.
+func (rp RandomFoodPlacer) PlaceFood(width, height int32, food []*pb.Point, snakes []*pb.Snake) *pb.Point {
+	return getUnoccupiedPoint(rp.Rand, width, height, food, snakes)
+}
+
+// ScriptedFoodPlacer places food at predetermined points, in order. Scripted
+// points that are occupied when they come up are skipped. Once the script is
+// exhausted the fallback placer is used. This is useful for reproducing games
+// and writing scenarios that depend on where food shows up.
+type ScriptedFoodPlacer struct {
+	points   []*pb.Point
+	fallback FoodPlacer
+	lock     sync.Mutex
+}
+
+// NewScriptedFoodPlacer returns a placer that dequeues the given points before
+// falling back to random placement.
+func NewScriptedFoodPlacer(points []*pb.Point) *ScriptedFoodPlacer {
+	return &ScriptedFoodPlacer{
+		points:   points,
+		fallback: RandomFoodPlacer{},
+	}
+}
+
+// PlaceFood returns the next unoccupied scripted point, or defers to the
+// fallback placer when no scripted points remain.
+func (sp *ScriptedFoodPlacer) PlaceFood(width, height int32, food []*pb.Point, snakes []*pb.Snake) *pb.Point {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	occupied := getUniqOccupiedPoints(food, snakes)
+	for len(sp.points) > 0 {
+		p := sp.points[0]
+		sp.points = sp.points[1:]
+		if !containsPoint(occupied, p) {
+			return p.Clone()
+		}
+	}
+	return sp.fallback.PlaceFood(width, height, food, snakes)
+}
+
+// Remaining returns the number of scripted points not yet placed.
+func (sp *ScriptedFoodPlacer) Remaining() int {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+	return len(sp.points)
+}
+
+// OccupancyRatio returns the fraction of the board of game covered by the
+// alive snakes in frame, see BoardFill. It can be used to tune food spawning
+// as the board fills up.
+func OccupancyRatio(game *pb.Game, frame *pb.GameFrame) float64 {
+	if frame == nil {
+		return 0
+	}
+	return BoardFill(frame, game.Width, game.Height)
+}
+
+func containsPoint(points []*pb.Point, p *pb.Point) bool {
+	for _, o := range points {
+		if o.Equal(p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,393 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptedFoodPlacerPlacesInOrder(t *testing.T) {
+	placer := NewScriptedFoodPlacer([]*pb.Point{
+		{X: 3, Y: 3},
+		{X: 4, Y: 4},
+	})
+
+	p := placer.PlaceFood(10, 10, []*pb.Point{}, []*pb.Snake{})
+	require.Equal(t, &pb.Point{X: 3, Y: 3}, p)
+	p = placer.PlaceFood(10, 10, []*pb.Point{}, []*pb.Snake{})
+	require.Equal(t, &pb.Point{X: 4, Y: 4}, p)
+	require.Equal(t, 0, placer.Remaining())
+}
+
+func TestScriptedFoodPlacerSkipsOccupied(t *testing.T) {
+	placer := NewScriptedFoodPlacer([]*pb.Point{
+		{X: 1, Y: 1},
+		{X: 2, Y: 2},
+	})
+
+	p := placer.PlaceFood(10, 10, []*pb.Point{}, []*pb.Snake{
+		{Body: []*pb.Point{{X: 1, Y: 1}}},
+	})
+	require.Equal(t, &pb.Point{X: 2, Y: 2}, p)
+}
+
+func TestScriptedFoodPlacerFallsBack(t *testing.T) {
+	placer := NewScriptedFoodPlacer([]*pb.Point{})
+
+	p := placer.PlaceFood(2, 1, []*pb.Point{{X: 0, Y: 0}}, []*pb.Snake{})
+	require.Equal(t, &pb.Point{X: 1, Y: 0}, p)
+
+	p = placer.PlaceFood(1, 1, []*pb.Point{{X: 0, Y: 0}}, []*pb.Snake{})
+	require.Nil(t, p)
+}
+
+func TestUpdateFoodWithScriptedPlacer(t *testing.T) {
+	updated, err := updateFood(context.Background(), 20, 20, &pb.GameFrame{
+		Food: []*pb.Point{
+			{X: 1, Y: 1},
+			{X: 1, Y: 2},
+		},
+		Snakes: []*pb.Snake{
+			{
+				Body: []*pb.Point{
+					{X: 1, Y: 2},
+					{X: 2, Y: 2},
+				},
+			},
+		},
+	}, []*pb.Point{
+		{X: 1, Y: 2},
+	}, 1, NewScriptedFoodPlacer([]*pb.Point{
+		{X: 2, Y: 2},
+		{X: 5, Y: 7},
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []*pb.Point{{X: 1, Y: 1}, {X: 5, Y: 7}}, updated)
+}
+
+func TestFoodSpawnStartTurn(t *testing.T) {
+	game := &pb.Game{
+		Width:   20,
+		Height:  20,
+		Ruleset: &pb.Ruleset{FoodSpawnStartTurn: 5},
+	}
+	eatingFrame := func(turn int32) *pb.GameFrame {
+		return &pb.GameFrame{
+			Turn: turn,
+			Snakes: []*pb.Snake{
+				{
+					Health: 50,
+					Body: []*pb.Point{
+						{X: 1, Y: 1},
+						{X: 1, Y: 2},
+						{X: 1, Y: 3},
+					},
+				},
+			},
+			Food: []*pb.Point{{X: 1, Y: 0}},
+		}
+	}
+
+	for turn := int32(0); turn < 4; turn++ {
+		next, err := GameTick(context.Background(), game, eatingFrame(turn))
+		require.NoError(t, err)
+		require.Len(t, next.Food, 0, "food spawned on turn %d", next.Turn)
+	}
+
+	next, err := GameTick(context.Background(), game, eatingFrame(4))
+	require.NoError(t, err)
+	require.Equal(t, int32(5), next.Turn)
+	require.Len(t, next.Food, 1)
+}
+
+func TestMaxFoodSpawnPerTurn(t *testing.T) {
+	game := &pb.Game{
+		Width:      20,
+		Height:     20,
+		FoodTarget: 3,
+		Ruleset:    &pb.Ruleset{MaxFoodSpawnPerTurn: 1},
+	}
+	frame := &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			{
+				Health: 50,
+				Body: []*pb.Point{
+					{X: 0, Y: 17},
+					{X: 0, Y: 18},
+					{X: 0, Y: 19},
+				},
+			},
+		},
+	}
+
+	placer := NewScriptedFoodPlacer([]*pb.Point{
+		{X: 10, Y: 10},
+		{X: 11, Y: 11},
+		{X: 12, Y: 12},
+		{X: 13, Y: 13},
+	})
+
+	for turn := 1; turn <= 5; turn++ {
+		moves := []*SnakeUpdate{{Snake: frame.Snakes[0], Move: "up"}}
+		next, err := advanceFrame(context.Background(), game, frame, moves, placer)
+		require.NoError(t, err)
+		require.True(t, len(next.Food) <= len(frame.Food)+1, "more than one food spawned on turn %d", next.Turn)
+		frame = next
+	}
+	require.Len(t, frame.Food, 3)
+}
+
+func TestDisableFood(t *testing.T) {
+	game := &pb.Game{
+		Width:      10,
+		Height:     10,
+		FoodTarget: 2,
+		Ruleset:    &pb.Ruleset{DisableFood: true, MaxFoodSpawnPerTurn: 1},
+	}
+	// Food left on the board, e.g. from a scripted frame, is not eaten.
+	frame := &pb.GameFrame{
+		Food: []*pb.Point{{X: 5, Y: 4}, {X: 5, Y: 3}},
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 3, Body: []*pb.Point{{X: 5, Y: 5}, {X: 5, Y: 6}, {X: 5, Y: 7}}},
+		},
+	}
+
+	// Health runs out on turn 3, the snake dies when it is checked on turn 4.
+	for turn := 1; turn <= 4; turn++ {
+		moves := []*SnakeUpdate{{Snake: frame.Snakes[0], Move: "up"}}
+		next, err := advanceFrame(context.Background(), game, frame, moves, RandomFoodPlacer{})
+		require.NoError(t, err)
+		require.Equal(t, frame.Food, next.Food)
+		if next.Snakes[0].Death == nil {
+			require.Len(t, next.Snakes[0].Body, 3)
+		}
+		frame = next
+	}
+	require.NotNil(t, frame.Snakes[0].Death)
+	require.Equal(t, DeathCauseStarvation, frame.Snakes[0].Death.Cause)
+	require.Equal(t, int32(4), frame.Snakes[0].Death.Turn)
+}
+
+func TestCreateInitialGame_DisableFood(t *testing.T) {
+	_, frames, err := CreateInitialGame(&pb.CreateRequest{
+		Width:   10,
+		Height:  10,
+		Food:    5,
+		Ruleset: &pb.Ruleset{DisableFood: true},
+		Snakes:  []*pb.SnakeOptions{{ID: "snake_1"}},
+	})
+	require.NoError(t, err)
+	require.Empty(t, frames[0].Food)
+}
+
+func TestFoodToSpawn(t *testing.T) {
+	eaten := []*pb.Point{{X: 1, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 3}}
+	frame := &pb.GameFrame{Food: []*pb.Point{{X: 1, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 3}}}
+	unlimited := &pb.Ruleset{}
+	capped := &pb.Ruleset{MaxFoodSpawnPerTurn: 1}
+
+	// Without a cap every eaten item is replaced at once.
+	require.Equal(t, 3, foodToSpawn(&pb.Game{FoodTarget: 3}, frame, eaten, unlimited))
+	// With a cap the board is refilled towards the target one item a turn.
+	require.Equal(t, 1, foodToSpawn(&pb.Game{FoodTarget: 3}, frame, eaten, capped))
+	require.Equal(t, 1, foodToSpawn(&pb.Game{FoodTarget: 3}, frame, eaten[:1], capped))
+	require.Equal(t, 0, foodToSpawn(&pb.Game{FoodTarget: 3}, frame, nil, capped))
+	// Games without a target only replace what was eaten.
+	require.Equal(t, 0, foodToSpawn(&pb.Game{}, frame, nil, capped))
+}
+
+func TestFoodToSpawnMinimumFood(t *testing.T) {
+	eaten := []*pb.Point{{X: 1, Y: 1}}
+	three := &pb.GameFrame{Food: []*pb.Point{{X: 1, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 3}}}
+	five := &pb.GameFrame{Food: append(append([]*pb.Point{}, three.Food...), &pb.Point{X: 4, Y: 4}, &pb.Point{X: 0, Y: 4})}
+	game := &pb.Game{}
+
+	// Replacing without a minimum puts back what was eaten and nothing else.
+	replace := &pb.Ruleset{}
+	require.Equal(t, 1, foodToSpawn(game, three, eaten, replace))
+	require.Equal(t, 0, foodToSpawn(game, &pb.GameFrame{}, nil, replace))
+
+	// Without replacing, only the minimum is kept up.
+	minimum := &pb.Ruleset{DisableFoodReplacement: true, MinimumFood: 3}
+	require.Equal(t, 1, foodToSpawn(game, three, eaten, minimum))
+	require.Equal(t, 0, foodToSpawn(game, five, eaten, minimum))
+	require.Equal(t, 3, foodToSpawn(game, &pb.GameFrame{}, nil, minimum))
+
+	// Both together replace what was eaten and top up below the minimum.
+	both := &pb.Ruleset{MinimumFood: 3}
+	require.Equal(t, 1, foodToSpawn(game, five, eaten, both))
+	require.Equal(t, 2, foodToSpawn(game, &pb.GameFrame{Food: three.Food[:1]}, nil, both))
+
+	// The cap holds for the minimum too.
+	capped := &pb.Ruleset{DisableFoodReplacement: true, MinimumFood: 3, MaxFoodSpawnPerTurn: 1}
+	require.Equal(t, 1, foodToSpawn(game, &pb.GameFrame{}, nil, capped))
+
+	// Without either no food comes back.
+	none := &pb.Ruleset{DisableFoodReplacement: true}
+	require.Equal(t, 0, foodToSpawn(game, three, eaten, none))
+}
+
+func TestMinimumFoodWithoutReplacement(t *testing.T) {
+	game := &pb.Game{
+		Width:   5,
+		Height:  5,
+		Ruleset: &pb.Ruleset{DisableFoodReplacement: true, MinimumFood: 2},
+	}
+	frame := &pb.GameFrame{
+		Food: []*pb.Point{{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 4, Y: 4}},
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 50, Body: []*pb.Point{{X: 2, Y: 2}, {X: 2, Y: 3}, {X: 2, Y: 4}}},
+		},
+	}
+	placer := NewScriptedFoodPlacer([]*pb.Point{{X: 3, Y: 3}})
+
+	// Two items are left after eating, so nothing is placed.
+	next, err := advanceFrame(context.Background(), game, frame, []*SnakeUpdate{{Snake: frame.Snakes[0], Move: "up"}}, placer)
+	require.NoError(t, err)
+	require.Len(t, next.Food, 2)
+	require.Equal(t, 1, placer.Remaining())
+
+	// Eating again drops below the minimum, one item is placed.
+	next, err = advanceFrame(context.Background(), game, next, []*SnakeUpdate{{Snake: next.Snakes[0], Move: "left"}}, placer)
+	require.NoError(t, err)
+	require.Equal(t, &pb.Point{X: 1, Y: 1}, next.Snakes[0].Head())
+	require.Equal(t, []*pb.Point{{X: 4, Y: 4}, {X: 3, Y: 3}}, next.Food)
+	require.Equal(t, 0, placer.Remaining())
+}
+
+func TestFoodSpawnStartTurnDefault(t *testing.T) {
+	require.Equal(t, RandomFoodPlacer{}, foodPlacerForTurn(StandardRuleset(), 0, 1, nil, nil))
+}
+
+func TestOccupancyRatio(t *testing.T) {
+	game := &pb.Game{Width: 5, Height: 4}
+	frame := &pb.GameFrame{
+		Food: []*pb.Point{{X: 4, Y: 3}},
+		Snakes: []*pb.Snake{
+			// Stacked segments count once.
+			{ID: "1", Body: []*pb.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 1}}},
+			{ID: "2", Body: []*pb.Point{{X: 1, Y: 2}, {X: 0, Y: 2}}},
+			{ID: "3", Body: []*pb.Point{{X: 2, Y: 2}}, Death: &pb.Death{Cause: DeathCauseStarvation}},
+		},
+	}
+	require.Equal(t, 0.2, OccupancyRatio(game, frame))
+	require.Equal(t, 0.0, OccupancyRatio(game, &pb.GameFrame{}))
+	require.Equal(t, 0.0, OccupancyRatio(&pb.Game{}, frame))
+}
+
+func TestHazardFoodPlacement(t *testing.T) {
+	hazards := []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}
+	placer := func(placement HazardFoodPlacement) FoodPlacer {
+		ruleset := &pb.Ruleset{HazardFoodPlacement: string(placement)}
+		return withHazardPlacement(RandomFoodPlacer{}, ruleset, hazards, nil)
+	}
+	snakes := []*pb.Snake{{Body: []*pb.Point{{X: 0, Y: 0}}}}
+	oneFree := []*pb.Point{{X: 0, Y: 1}}
+	noneFree := []*pb.Point{{X: 0, Y: 1}, {X: 1, Y: 1}}
+
+	// Excluded hazards are never used, even when nothing else is free.
+	require.Equal(t, &pb.Point{X: 1, Y: 1}, placer(HazardFoodExclude).PlaceFood(2, 2, oneFree, snakes))
+	require.Nil(t, placer(HazardFoodExclude).PlaceFood(2, 2, noneFree, snakes))
+
+	// Avoided hazards are only used when nothing else is free.
+	require.Equal(t, &pb.Point{X: 1, Y: 1}, placer(HazardFoodAvoid).PlaceFood(2, 2, oneFree, snakes))
+	require.Equal(t, &pb.Point{X: 1, Y: 0}, placer(HazardFoodAvoid).PlaceFood(2, 2, noneFree, snakes))
+
+	// Preferred hazards are used whenever one is free.
+	require.Equal(t, &pb.Point{X: 1, Y: 0}, placer(HazardFoodPrefer).PlaceFood(2, 2, oneFree, snakes))
+	hazardsTaken := []*pb.Point{{X: 1, Y: 0}, {X: 0, Y: 1}}
+	require.Equal(t, &pb.Point{X: 1, Y: 1}, placer(HazardFoodPrefer).PlaceFood(2, 2, hazardsTaken, snakes))
+
+	// Without a placement hazards are ignored.
+	require.Equal(t, RandomFoodPlacer{}, withHazardPlacement(RandomFoodPlacer{}, &pb.Ruleset{}, hazards, nil))
+}
+
+func TestSeededFoodPlacement(t *testing.T) {
+	frame := &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			{Health: 50, Body: []*pb.Point{{X: 1, Y: 1}, {X: 1, Y: 2}}},
+		},
+	}
+	spawn := func(seed int64) []*pb.Point {
+		placer := foodPlacerForTurn(&pb.Ruleset{}, seed, 5, nil, nil)
+		food, err := updateFood(context.Background(), 20, 20, frame, nil, 3, placer)
+		require.NoError(t, err)
+		return food
+	}
+
+	food := spawn(42)
+	require.Len(t, food, 3)
+	require.False(t, food[0].Equal(food[1]) || food[0].Equal(food[2]) || food[1].Equal(food[2]))
+	require.Equal(t, food, spawn(42))
+}
+
+func TestSeededHazardFoodPlacement(t *testing.T) {
+	ruleset := &pb.Ruleset{HazardFoodPlacement: string(HazardFoodPrefer)}
+	hazards := []*pb.Point{{X: 0, Y: 0}, {X: 3, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 3}}
+	reversed := []*pb.Point{hazards[3], hazards[2], hazards[1], hazards[0]}
+
+	// The hazards are picked from in the same order whatever order they are
+	// stored in.
+	for turn := int32(1); turn < 10; turn++ {
+		p := foodPlacerForTurn(ruleset, 42, turn, hazards, nil).PlaceFood(4, 4, nil, nil)
+		require.Equal(t, p, foodPlacerForTurn(ruleset, 42, turn, reversed, nil).PlaceFood(4, 4, nil, nil))
+	}
+}
+
+func TestObstacleFoodPlacement(t *testing.T) {
+	snakes := []*pb.Snake{{Body: []*pb.Point{{X: 0, Y: 0}}}}
+	placer := withObstacles(RandomFoodPlacer{}, []*pb.Point{{X: 1, Y: 0}, {X: 0, Y: 1}})
+	require.Equal(t, &pb.Point{X: 1, Y: 1}, placer.PlaceFood(2, 2, nil, snakes))
+	require.Nil(t, placer.PlaceFood(2, 2, []*pb.Point{{X: 1, Y: 1}}, snakes))
+
+	// Obstacles are never preferred hazards.
+	ruleset := &pb.Ruleset{HazardFoodPlacement: string(HazardFoodPrefer)}
+	placer = withObstacles(withHazardPlacement(RandomFoodPlacer{}, ruleset, []*pb.Point{{X: 1, Y: 0}}, nil), []*pb.Point{{X: 1, Y: 0}, {X: 0, Y: 1}})
+	require.Equal(t, &pb.Point{X: 1, Y: 1}, placer.PlaceFood(2, 2, nil, snakes))
+}
+
+func TestGameTickKeepsHazards(t *testing.T) {
+	game := &pb.Game{
+		Width:   2,
+		Height:  2,
+		Ruleset: &pb.Ruleset{HazardFoodPlacement: string(HazardFoodPrefer)},
+	}
+	frame := &pb.GameFrame{
+		Food:    []*pb.Point{{X: 0, Y: 1}},
+		Hazards: []*pb.Point{{X: 1, Y: 1}},
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 10, Body: []*pb.Point{{X: 0, Y: 0}, {X: 0, Y: 0}}},
+		},
+	}
+	next, err := advanceFrame(context.Background(), game, frame, []*SnakeUpdate{{Snake: frame.Snakes[0], Move: "down"}},
+		foodPlacerForTurn(gameRuleset(game), game.Seed, 1, frame.Hazards, nil))
+	require.NoError(t, err)
+	require.Equal(t, frame.Hazards, next.Hazards)
+	require.Equal(t, []*pb.Point{{X: 1, Y: 1}}, next.Food)
+}
+
+func TestGameTickWithFoodPlacer(t *testing.T) {
+	RegisterMoveRequester("bot", inProcessBot{move: "up"})
+	defer RegisterMoveRequester("bot", nil)
+	frame := func() *pb.GameFrame {
+		return &pb.GameFrame{
+			Turn: 1,
+			Food: []*pb.Point{{X: 1, Y: 0}},
+			Snakes: []*pb.Snake{
+				{ID: "1", URL: "bot://1", Health: 50, Body: []*pb.Point{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}}},
+			},
+		}
+	}
+	placer := NewScriptedFoodPlacer([]*pb.Point{{X: 4, Y: 4}, {X: 3, Y: 3}})
+
+	// The script is used for the seeded game it is passed with, a game ticked
+	// without it places food as usual.
+	next, err := GameTick(context.Background(), &pb.Game{Width: 5, Height: 5, Seed: 7}, frame(), WithFoodPlacer(placer))
+	require.NoError(t, err)
+	require.Equal(t, []*pb.Point{{X: 4, Y: 4}}, next.Food)
+	_, err = GameTick(context.Background(), &pb.Game{Width: 5, Height: 5}, frame())
+	require.NoError(t, err)
+	require.Equal(t, 1, placer.Remaining())
+}
@@ -0,0 +1,87 @@
+package rules
+
+import "github.com/battlesnakeio/engine/controller/pb"
+
+// initialHazards returns the hazards a game starts with: the requested ones
+// and, when the ruleset asks for a HazardBorder, every square within that many
+// squares of the edge of the board. The border is placed once and never
+// changes during the game.
+func initialHazards(req *pb.CreateRequest, ruleset *pb.Ruleset) []*pb.Point {
+	hazards := req.Hazards
+	thickness := ruleset.GetHazardBorder()
+	if thickness <= 0 {
+		return hazards
+	}
+	hazards = append([]*pb.Point{}, hazards...)
+	for x := int32(0); x < req.Width; x++ {
+		for y := int32(0); y < req.Height; y++ {
+			p := &pb.Point{X: x, Y: y}
+			if inBorder(p, req.Width, req.Height, thickness) && !containsPoint(hazards, p) {
+				hazards = append(hazards, p)
+			}
+		}
+	}
+	return hazards
+}
+
+func inBorder(p *pb.Point, width, height, thickness int32) bool {
+	return p.X < thickness || p.Y < thickness || p.X >= width-thickness || p.Y >= height-thickness
+}
+
+// damageOnHazards takes Ruleset.HazardDamagePerTurn off the health of every
+// alive snake with its head on a hazard, once for every hazard stacked on the
+// square. Health does not drop below 0, a snake left without health starves
+// like any other.
+func damageOnHazards(frame *pb.GameFrame, ruleset *pb.Ruleset) {
+	damage := ruleset.GetHazardDamagePerTurn()
+	if damage <= 0 || len(frame.Hazards) == 0 {
+		return
+	}
+	for _, s := range frame.AliveSnakes() {
+		stack := hazardStack(frame.Hazards, s.Head())
+		if stack == 0 {
+			continue
+		}
+		s.Health -= damage * stack
+		if s.Health < 0 {
+			s.Health = 0
+		}
+	}
+}
+
+// healOffHazards gives Ruleset.HazardHealRate health back to every alive snake
+// with its head on a square without hazards or food, the inverse of
+// damageOnHazards. Health is not raised above MaxHealth. Snakes on food are
+// left to eat, which restores their health anyway.
+func healOffHazards(frame *pb.GameFrame, ruleset *pb.Ruleset) {
+	heal := ruleset.GetHazardHealRate()
+	if heal <= 0 {
+		return
+	}
+	for _, s := range frame.AliveSnakes() {
+		head := s.Head()
+		if head == nil || hazardStack(frame.Hazards, head) > 0 || containsPoint(frame.Food, head) {
+			continue
+		}
+		if s.Health >= ruleset.MaxHealth {
+			continue
+		}
+		s.Health += heal
+		if s.Health > ruleset.MaxHealth {
+			s.Health = ruleset.MaxHealth
+		}
+	}
+}
+
+// hazardStack returns how many hazards are stacked on p. Hazards stack by
+// listing a square more than once, which keeps them a plain list of points in
+// frames and in everything frames are stored as.
+func hazardStack(hazards []*pb.Point, p *pb.Point) int32 {
+	stack := int32(0)
+	for _, h := range hazards {
+		if h.Equal(p) {
+			stack++
+		}
+	}
+	return stack
+}
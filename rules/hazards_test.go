@@ -0,0 +1,167 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateInitialGame_HazardBorder(t *testing.T) {
+	_, frames, err := CreateInitialGame(&pb.CreateRequest{
+		Width:   5,
+		Height:  5,
+		Food:    5,
+		Hazards: []*pb.Point{{X: 0, Y: 0}, {X: 2, Y: 2}},
+		Ruleset: &pb.Ruleset{HazardBorder: 1, HazardFoodPlacement: string(HazardFoodExclude)},
+		Snakes:  []*pb.SnakeOptions{{ID: "snake_1"}},
+	})
+	require.NoError(t, err)
+	// The 16 squares of the edge and the requested hazard in the middle.
+	require.Len(t, frames[0].Hazards, 17)
+	for _, p := range frames[0].Hazards {
+		require.True(t, inBorder(p, 5, 5, 1) || p.Equal(&pb.Point{X: 2, Y: 2}), "%v", p)
+	}
+	for _, f := range frames[0].Food {
+		require.False(t, containsPoint(frames[0].Hazards, f), "food under a hazard at %v", f)
+	}
+}
+
+func TestHazardBorderDamage(t *testing.T) {
+	ruleset := &pb.Ruleset{HazardBorder: 1, HazardDamagePerTurn: 14}
+	game := &pb.Game{Width: 5, Height: 5, Ruleset: ruleset, RulesetVersion: CurrentRulesetVersion}
+	frame := &pb.GameFrame{
+		Hazards: initialHazards(&pb.CreateRequest{Width: 5, Height: 5}, ruleset),
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 100, Body: []*pb.Point{{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}}},
+		},
+	}
+	moves := func(move string) []*SnakeUpdate {
+		return []*SnakeUpdate{{Snake: frame.Snakes[0], Move: move}}
+	}
+
+	// Inside the border only the usual point of health is lost.
+	next, err := advanceFrame(context.Background(), game, frame, moves("up"), noFoodPlacer{})
+	require.NoError(t, err)
+	require.Equal(t, int32(99), next.Snakes[0].Health)
+
+	// Entering the border costs the hazard damage on top.
+	frame = next
+	next, err = advanceFrame(context.Background(), game, frame, moves("left"), noFoodPlacer{})
+	require.NoError(t, err)
+	require.Equal(t, &pb.Point{X: 0, Y: 1}, next.Snakes[0].Head())
+	require.Equal(t, int32(84), next.Snakes[0].Health)
+	require.Len(t, next.Hazards, 16)
+}
+
+func TestDamageOnHazardsStopsAtZero(t *testing.T) {
+	frame := &pb.GameFrame{
+		Hazards: []*pb.Point{{X: 0, Y: 0}},
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 5, Body: []*pb.Point{{X: 0, Y: 0}}},
+			{ID: "2", Health: 5, Body: []*pb.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+		},
+	}
+	damageOnHazards(frame, &pb.Ruleset{HazardDamagePerTurn: 14})
+	require.Equal(t, int32(0), frame.Snakes[0].Health)
+	// Only the head counts.
+	require.Equal(t, int32(5), frame.Snakes[1].Health)
+
+	// Without damage configured hazards are harmless.
+	frame.Snakes[1].Body = []*pb.Point{{X: 0, Y: 0}}
+	damageOnHazards(frame, &pb.Ruleset{})
+	require.Equal(t, int32(5), frame.Snakes[1].Health)
+}
+
+func TestStackedHazardDamage(t *testing.T) {
+	game := &pb.Game{Width: 5, Height: 5, Ruleset: &pb.Ruleset{HazardDamagePerTurn: 14}, RulesetVersion: CurrentRulesetVersion}
+	frame := &pb.GameFrame{
+		// (2,1) is covered twice, (3,2) once.
+		Hazards: []*pb.Point{{X: 2, Y: 1}, {X: 3, Y: 2}, {X: 2, Y: 1}},
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 100, Body: []*pb.Point{{X: 2, Y: 2}, {X: 2, Y: 3}, {X: 2, Y: 4}}},
+			{ID: "2", Health: 100, Body: []*pb.Point{{X: 4, Y: 2}, {X: 4, Y: 3}, {X: 4, Y: 4}}},
+		},
+	}
+	moves := []*SnakeUpdate{
+		{Snake: frame.Snakes[0], Move: "up"},
+		{Snake: frame.Snakes[1], Move: "left"},
+	}
+
+	next, err := advanceFrame(context.Background(), game, frame, moves, noFoodPlacer{})
+	require.NoError(t, err)
+	require.Equal(t, int32(100-1-2*14), next.Snakes[0].Health)
+	require.Equal(t, int32(100-1-14), next.Snakes[1].Health)
+	require.Len(t, next.Hazards, 3, "stacks are carried forward")
+}
+
+func TestStackedHazardsRoundTrip(t *testing.T) {
+	frame := &pb.GameFrame{Hazards: []*pb.Point{{X: 1, Y: 1}, {X: 1, Y: 1}, {X: 0, Y: 1}}}
+
+	data, err := proto.Marshal(frame)
+	require.NoError(t, err)
+	decoded := &pb.GameFrame{}
+	require.NoError(t, proto.Unmarshal(data, decoded))
+	require.Equal(t, int32(2), hazardStack(decoded.Hazards, &pb.Point{X: 1, Y: 1}))
+
+	// Archives store frames as JSON.
+	data, err = json.Marshal(frame)
+	require.NoError(t, err)
+	decoded = &pb.GameFrame{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.Equal(t, int32(2), hazardStack(decoded.Hazards, &pb.Point{X: 1, Y: 1}))
+	require.Equal(t, int32(1), hazardStack(decoded.Hazards, &pb.Point{X: 0, Y: 1}))
+}
+
+func TestHazardHealRate(t *testing.T) {
+	ruleset := &pb.Ruleset{HazardHealRate: 4, HazardDamagePerTurn: 10}
+	game := &pb.Game{Width: 10, Height: 5, Ruleset: ruleset, RulesetVersion: CurrentRulesetVersion}
+	frame := &pb.GameFrame{
+		Hazards: []*pb.Point{{X: 2, Y: 3}},
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 90, Body: []*pb.Point{{X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 0}}},
+			{ID: "2", Health: 90, Body: []*pb.Point{{X: 1, Y: 3}, {X: 0, Y: 3}, {X: 0, Y: 2}}},
+		},
+	}
+	moves := func() []*SnakeUpdate {
+		return []*SnakeUpdate{
+			{Snake: frame.Snakes[0], Move: "right"},
+			{Snake: frame.Snakes[1], Move: "right"},
+		}
+	}
+
+	// Off hazards a snake heals on top of the point it loses every turn,
+	// on a hazard it takes the damage instead.
+	next, err := advanceFrame(context.Background(), game, frame, moves(), noFoodPlacer{})
+	require.NoError(t, err)
+	require.Equal(t, int32(90-1+4), next.Snakes[0].Health)
+	require.Equal(t, int32(90-1-10), next.Snakes[1].Health)
+
+	// Healing stops at the maximum.
+	for _, want := range []int32{96, 99, 100, 100} {
+		frame = next
+		next, err = advanceFrame(context.Background(), game, frame, moves(), noFoodPlacer{})
+		require.NoError(t, err)
+		require.Equal(t, want, next.Snakes[0].Health)
+	}
+}
+
+func TestHealOffHazardsSkipsFood(t *testing.T) {
+	frame := &pb.GameFrame{
+		Food: []*pb.Point{{X: 0, Y: 0}},
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 50, Body: []*pb.Point{{X: 0, Y: 0}}},
+			{ID: "2", Health: 50, Body: []*pb.Point{{X: 1, Y: 0}}},
+		},
+	}
+	healOffHazards(frame, &pb.Ruleset{HazardHealRate: 5, MaxHealth: 100})
+	require.Equal(t, int32(50), frame.Snakes[0].Health)
+	require.Equal(t, int32(55), frame.Snakes[1].Health)
+
+	// Without a rate nothing heals.
+	healOffHazards(frame, &pb.Ruleset{MaxHealth: 100})
+	require.Equal(t, int32(55), frame.Snakes[1].Health)
+}
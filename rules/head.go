@@ -0,0 +1,70 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+)
+
+// Move is a direction a snake moves its head in, see pb.Move.
+type Move = pb.Move
+
+// The moves a snake can make, as snakes send them in their move responses.
+const (
+	MoveUp    = pb.MoveUp
+	MoveDown  = pb.MoveDown
+	MoveLeft  = pb.MoveLeft
+	MoveRight = pb.MoveRight
+)
+
+// snakeMoves are the moves a snake can make.
+var snakeMoves = []Move{MoveUp, MoveDown, MoveLeft, MoveRight}
+
+// ParseMove returns the move s names. Case and surrounding whitespace are
+// ignored, so " Up" is MoveUp. Anything else returns ErrInvalidMove.
+func ParseMove(s string) (Move, error) {
+	move := Move(strings.ToLower(strings.TrimSpace(s)))
+	for _, m := range snakeMoves {
+		if m == move {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidMove, s)
+}
+
+// NextHead returns the point a head at head lands on when it makes move, or
+// nil for an unknown move. The origin of the board is its top left corner, so
+// "up" decreases Y and "down" increases it, the same as pb.Snake.Move. The
+// point is not wrapped, a head moving over an edge lands off the board.
+func NextHead(head *pb.Point, move string) *pb.Point {
+	if head == nil {
+		return nil
+	}
+	switch Move(move) {
+	case MoveUp:
+		return &pb.Point{X: head.X, Y: head.Y - 1}
+	case MoveDown:
+		return &pb.Point{X: head.X, Y: head.Y + 1}
+	case MoveLeft:
+		return &pb.Point{X: head.X - 1, Y: head.Y}
+	case MoveRight:
+		return &pb.Point{X: head.X + 1, Y: head.Y}
+	}
+	return nil
+}
+
+// NextHeadOnBoard returns where the head of snake lands when it makes move on
+// a board of width by height played with ruleset, and whether that point is
+// on the board. A head moving over an edge the ruleset wraps comes back on the
+// opposite side, the same as in a tick, a head moving over any other edge
+// lands off the board. Unknown moves and snakes without a body return nil and
+// false.
+func NextHeadOnBoard(snake *pb.Snake, move string, width, height int32, ruleset *pb.Ruleset) (*pb.Point, bool) {
+	next := NextHead(snake.Head(), move)
+	if next == nil {
+		return nil, false
+	}
+	wrapPoint(next, width, height, ruleset)
+	return next, !deathByOutOfBounds(next, width, height)
+}
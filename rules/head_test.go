@@ -0,0 +1,126 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextHead(t *testing.T) {
+	head := &pb.Point{X: 2, Y: 2}
+	require.Equal(t, &pb.Point{X: 2, Y: 1}, NextHead(head, "up"))
+	require.Equal(t, &pb.Point{X: 2, Y: 3}, NextHead(head, "down"))
+	require.Equal(t, &pb.Point{X: 1, Y: 2}, NextHead(head, "left"))
+	require.Equal(t, &pb.Point{X: 3, Y: 2}, NextHead(head, "right"))
+	require.Equal(t, &pb.Point{X: 2, Y: 2}, head)
+}
+
+func TestNextHeadOffBoard(t *testing.T) {
+	require.Equal(t, &pb.Point{X: 0, Y: -1}, NextHead(&pb.Point{}, "up"))
+	require.Equal(t, &pb.Point{X: -1, Y: 0}, NextHead(&pb.Point{}, "left"))
+}
+
+func TestNextHeadUnknown(t *testing.T) {
+	require.Nil(t, NextHead(&pb.Point{}, "sideways"))
+	require.Nil(t, NextHead(nil, "up"))
+}
+
+func TestNextHeadMatchesSnakeMove(t *testing.T) {
+	for _, move := range snakeMoves {
+		s := &pb.Snake{Body: []*pb.Point{{X: 4, Y: 4}}}
+		s.Move(string(move))
+		require.Equal(t, s.Head(), NextHead(&pb.Point{X: 4, Y: 4}, string(move)), move)
+	}
+}
+
+var wrapBoth = &pb.Ruleset{WrapHorizontal: true, WrapVertical: true}
+
+func TestNextHeadOnBoard(t *testing.T) {
+	snake := &pb.Snake{Body: []*pb.Point{{X: 1, Y: 1}}}
+	for move, want := range map[string]*pb.Point{
+		"up":    {X: 1, Y: 0},
+		"down":  {X: 1, Y: 2},
+		"left":  {X: 0, Y: 1},
+		"right": {X: 2, Y: 1},
+	} {
+		for _, ruleset := range []*pb.Ruleset{nil, wrapBoth} {
+			next, ok := NextHeadOnBoard(snake, move, 3, 3, ruleset)
+			require.True(t, ok, move)
+			require.Equal(t, want, next, move)
+		}
+	}
+}
+
+func TestNextHeadOnBoardEdges(t *testing.T) {
+	corner := &pb.Snake{Body: []*pb.Point{{X: 0, Y: 0}}}
+	opposite := &pb.Snake{Body: []*pb.Point{{X: 2, Y: 2}}}
+	for _, tc := range []struct {
+		snake   *pb.Snake
+		move    string
+		off     *pb.Point
+		wrapped *pb.Point
+	}{
+		{corner, "up", &pb.Point{X: 0, Y: -1}, &pb.Point{X: 0, Y: 2}},
+		{corner, "left", &pb.Point{X: -1, Y: 0}, &pb.Point{X: 2, Y: 0}},
+		{opposite, "down", &pb.Point{X: 2, Y: 3}, &pb.Point{X: 2, Y: 0}},
+		{opposite, "right", &pb.Point{X: 3, Y: 2}, &pb.Point{X: 0, Y: 2}},
+	} {
+		next, ok := NextHeadOnBoard(tc.snake, tc.move, 3, 3, nil)
+		require.False(t, ok, tc.move)
+		require.Equal(t, tc.off, next, tc.move)
+
+		next, ok = NextHeadOnBoard(tc.snake, tc.move, 3, 3, wrapBoth)
+		require.True(t, ok, tc.move)
+		require.Equal(t, tc.wrapped, next, tc.move)
+	}
+	require.Equal(t, &pb.Point{X: 0, Y: 0}, corner.Head())
+}
+
+func TestNextHeadOnBoardOneAxis(t *testing.T) {
+	corner := &pb.Snake{Body: []*pb.Point{{X: 0, Y: 0}}}
+	horizontal := &pb.Ruleset{WrapHorizontal: true}
+
+	next, ok := NextHeadOnBoard(corner, "left", 3, 3, horizontal)
+	require.True(t, ok)
+	require.Equal(t, &pb.Point{X: 2, Y: 0}, next)
+
+	next, ok = NextHeadOnBoard(corner, "up", 3, 3, horizontal)
+	require.False(t, ok)
+	require.Equal(t, &pb.Point{X: 0, Y: -1}, next)
+}
+
+func TestNextHeadOnBoardUnknown(t *testing.T) {
+	next, ok := NextHeadOnBoard(&pb.Snake{Body: []*pb.Point{{X: 1, Y: 1}}}, "sideways", 3, 3, wrapBoth)
+	require.Nil(t, next)
+	require.False(t, ok)
+
+	next, ok = NextHeadOnBoard(&pb.Snake{}, "up", 3, 3, wrapBoth)
+	require.Nil(t, next)
+	require.False(t, ok)
+}
+
+func TestParseMove(t *testing.T) {
+	for s, want := range map[string]Move{
+		"up":      MoveUp,
+		"down":    MoveDown,
+		"left":    MoveLeft,
+		"right":   MoveRight,
+		"Up":      MoveUp,
+		"DOWN":    MoveDown,
+		" left\n": MoveLeft,
+		"\tRiGhT": MoveRight,
+	} {
+		move, err := ParseMove(s)
+		require.NoError(t, err, s)
+		require.Equal(t, want, move, s)
+	}
+}
+
+func TestParseMoveInvalid(t *testing.T) {
+	for _, s := range []string{"", " ", "north", "u", "up up", "upp", "left-ish"} {
+		_, err := ParseMove(s)
+		require.True(t, errors.Is(err, ErrInvalidMove), "%q: %v", s, err)
+	}
+}
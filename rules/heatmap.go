@@ -0,0 +1,38 @@
+package rules
+
+import "github.com/battlesnakeio/engine/controller/pb"
+
+// CollisionMatrix counts how often a snake died on every square of a width by
+// height board over frames, for building heatmaps. The counts are indexed by
+// row first, matrix[y][x]. A death counts on the square its FrameEventDied
+// event points at, where the head of the snake was. Frames stored before
+// events were recorded count the heads of the snakes whose Death has the turn
+// of the frame instead. Deaths off the board, such as running into a wall, are
+// not counted. The frames are not changed.
+func CollisionMatrix(frames []*pb.GameFrame, width, height int32) [][]int {
+	matrix := make([][]int, height)
+	for y := range matrix {
+		matrix[y] = make([]int, width)
+	}
+	count := func(p *pb.Point) {
+		if p != nil && !deathByOutOfBounds(p, width, height) {
+			matrix[p.Y][p.X]++
+		}
+	}
+
+	for _, frame := range frames {
+		recorded := map[string]bool{}
+		for _, e := range frame.Events {
+			if e.Type == FrameEventDied {
+				recorded[e.SnakeID] = true
+				count(e.Point)
+			}
+		}
+		for _, s := range frame.Snakes {
+			if s.Death != nil && s.Death.Turn == frame.Turn && !recorded[s.ID] {
+				count(s.Head())
+			}
+		}
+	}
+	return matrix
+}
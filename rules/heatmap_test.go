@@ -0,0 +1,49 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollisionMatrix(t *testing.T) {
+	frames := []*pb.GameFrame{
+		{Turn: 0},
+		{
+			Turn: 1,
+			Events: []*pb.Event{
+				// A head to head, both snakes died on (1,0).
+				{Type: FrameEventDied, SnakeID: "a", Point: &pb.Point{X: 1, Y: 0}, Cause: DeathCauseHeadToHeadCollision},
+				{Type: FrameEventDied, SnakeID: "b", Point: &pb.Point{X: 1, Y: 0}, Cause: DeathCauseHeadToHeadCollision},
+				{Type: FrameEventAteFood, SnakeID: "c", Point: &pb.Point{X: 2, Y: 2}},
+			},
+		},
+		{
+			Turn: 2,
+			Events: []*pb.Event{
+				// Off the board.
+				{Type: FrameEventDied, SnakeID: "c", Point: &pb.Point{X: 2, Y: 3}, Cause: DeathCauseWallCollision},
+				{Type: FrameEventDied, SnakeID: "d", Point: &pb.Point{X: 0, Y: 2}, Cause: DeathCauseSnakeCollision},
+			},
+			Snakes: []*pb.Snake{
+				{ID: "d", Body: []*pb.Point{{X: 0, Y: 2}}, Death: &pb.Death{Turn: 2}},
+			},
+		},
+		// Stored without events, the deaths of the snakes are counted.
+		{
+			Turn: 3,
+			Snakes: []*pb.Snake{
+				{ID: "d", Body: []*pb.Point{{X: 0, Y: 2}}, Death: &pb.Death{Turn: 2}},
+				{ID: "e", Body: []*pb.Point{{X: 1, Y: 0}, {X: 1, Y: 1}}, Death: &pb.Death{Turn: 3}},
+			},
+		},
+	}
+
+	require.Equal(t, [][]int{
+		{0, 3, 0},
+		{0, 0, 0},
+		{1, 0, 0},
+	}, CollisionMatrix(frames, 3, 3))
+	require.Equal(t, [][]int{{0}}, CollisionMatrix(nil, 1, 1))
+}
@@ -17,6 +17,7 @@ type httpClient interface {
 	SetTimeout(time.Duration)
 	Get(string) (*http.Response, error)
 	Post(string, string, io.Reader) (*http.Response, error)
+	Do(*http.Request) (*http.Response, error)
 }
 
 type wrappedHTTPClient struct {
@@ -35,10 +36,28 @@ func (c *wrappedHTTPClient) Post(url, contentType string, body io.Reader) (*http
 	return c.Client.Post(url, contentType, body)
 }
 
+// snakeTransport is shared by the clients snakes are called with. Connections
+// to snake servers are kept alive and reused from one request to the next, so
+// a tick does not pay for setting up a connection to every snake. Connections
+// are pooled by host, a snake that changes its URL gets connections to its new
+// server. Snakes served over TLS are called over HTTP/2 when they support it.
+var snakeTransport = newSnakeTransport()
+
+func newSnakeTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	// Several snakes of a game are often served by the same host, keep a
+	// connection open for each of them.
+	t.MaxIdleConnsPerHost = 32
+	t.IdleConnTimeout = 90 * time.Second
+	t.ForceAttemptHTTP2 = true
+	return t
+}
+
 func getNetClient(duration time.Duration) httpClient {
 	return &wrappedHTTPClient{
 		Client: &http.Client{
-			Timeout: duration,
+			Timeout:   duration,
+			Transport: snakeTransport,
 		},
 	}
 }
@@ -2,11 +2,17 @@ package rules
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/battlesnakeio/engine/controller/pb"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,6 +36,13 @@ func (c mockHTTPClient) Post(url, contentType string, body io.Reader) (*http.Res
 	return c.resp(url), nil
 }
 
+func (c mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.resp(req.URL.String()), nil
+}
+
 type readCloser struct {
 	*bytes.Buffer
 }
@@ -89,3 +102,68 @@ func TestGetURL(t *testing.T) {
 		require.Equal(t, test.Expected, actual)
 	}
 }
+
+// countingServer is a snake server that counts the connections made to it and
+// the requests it answered.
+type countingServer struct {
+	*httptest.Server
+	conns    int32
+	requests int32
+}
+
+func newCountingServer() *countingServer {
+	cs := &countingServer{}
+	cs.Server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&cs.requests, 1)
+		w.Write([]byte(`{"move":"up"}`))
+	}))
+	cs.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&cs.conns, 1)
+		}
+	}
+	cs.Start()
+	return cs
+}
+
+func TestSnakeConnectionsReused(t *testing.T) {
+	createClient = getNetClient
+	first, second := newCountingServer(), newCountingServer()
+	defer first.Close()
+	defer second.Close()
+
+	snake := &pb.Snake{ID: "1", URL: first.URL}
+	for i := 0; i < 5; i++ {
+		_, _, err := postSnakeData(context.Background(), snake, "move", time.Second, []byte("{}"))
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(5), atomic.LoadInt32(&first.requests))
+	require.Equal(t, int32(1), atomic.LoadInt32(&first.conns), "every request after the first reuses the connection")
+
+	// The snake moved to another server mid-game.
+	snake.URL = second.URL
+	for i := 0; i < 3; i++ {
+		_, _, err := postSnakeData(context.Background(), snake, "move", time.Second, []byte("{}"))
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(3), atomic.LoadInt32(&second.requests))
+	require.Equal(t, int32(1), atomic.LoadInt32(&second.conns))
+	require.Equal(t, int32(5), atomic.LoadInt32(&first.requests))
+}
+
+func TestHTTPMoveRequesterCancelled(t *testing.T) {
+	createClient = getNetClient
+	// The snake does not answer before the test is done.
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	snake := &pb.Snake{ID: "1", URL: server.URL}
+	_, err := HTTPMoveRequester{}.RequestMove(ctx, snake, SnakeRequest{})
+	require.True(t, errors.Is(err, context.Canceled), "%v", err)
+}
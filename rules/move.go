@@ -1,10 +1,19 @@
 package rules
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/battlesnakeio/engine/controller/pb"
+	log "github.com/sirupsen/logrus"
 )
 
 // SnakeUpdate bundles together a snake with a move for processing
@@ -12,42 +21,407 @@ type SnakeUpdate struct {
 	Snake *pb.Snake
 	Move  string
 	Err   error
+	// Latency is the round trip time of the move request, 0 when the snake
+	// was not asked.
+	Latency time.Duration
 }
 
-func toSnakeUpdate(resp snakeResponse) *SnakeUpdate {
-	if resp.err == nil {
-		moveResponse := MoveResponse{}
-		err := json.Unmarshal(resp.data, &moveResponse)
-		if err != nil {
-			return &SnakeUpdate{
-				Snake: resp.snake,
-				Err:   err,
+// MoveSummary counts how the move requests of a turn went. Every update is
+// counted once, so the counts add up to the number of snakes asked.
+type MoveSummary struct {
+	Succeeded int
+	// Failed counts the requests that returned an error other than a
+	// timeout.
+	Failed int
+	// TimedOut counts the snakes that did not answer in time.
+	TimedOut int
+	// Cancelled counts the snakes that were not waited for, see
+	// ErrMoveCancelled.
+	Cancelled int
+	// MaxLatency is the round trip time of the slowest successful request,
+	// to tell how much of the timeout the snakes and the network used.
+	MaxLatency time.Duration
+}
+
+// SummarizeMoves counts the outcomes of updates.
+func SummarizeMoves(updates []*SnakeUpdate) MoveSummary {
+	summary := MoveSummary{}
+	for _, u := range updates {
+		switch {
+		case u.Err == nil:
+			summary.Succeeded++
+			if u.Latency > summary.MaxLatency {
+				summary.MaxLatency = u.Latency
 			}
+		case errors.Is(u.Err, ErrMoveCancelled):
+			summary.Cancelled++
+		case isTimeout(u.Err):
+			summary.TimedOut++
+		default:
+			summary.Failed++
 		}
-		return &SnakeUpdate{
-			Snake: resp.snake,
-			Move:  moveResponse.Move,
+	}
+	return summary
+}
+
+// isTimeout reports whether err is a request running out of time, either
+// through its context or as a network timeout.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// MoveStatus tells why a snake made its default move. It is stamped on the
+// snake as Snake.MoveStatus for every move request, so a replay viewer can
+// explain the default moves. A snake that answered with a valid move has an
+// empty status.
+type MoveStatus string
+
+const (
+	// MoveStatusOK is the status of a snake that answered with a valid move.
+	MoveStatusOK MoveStatus = ""
+	// MoveStatusTimeout is the status of a snake that did not answer in time.
+	MoveStatusTimeout MoveStatus = "timeout"
+	// MoveStatusConnectionRefused is the status of a snake whose server
+	// refused the connection.
+	MoveStatusConnectionRefused MoveStatus = "connection-refused"
+	// MoveStatusBadStatus is the status of a snake that answered with a
+	// status other than 200 OK, see ErrBadStatus.
+	MoveStatusBadStatus MoveStatus = "non-200"
+	// MoveStatusInvalidJSON is the status of a snake whose answer could not
+	// be decoded.
+	MoveStatusInvalidJSON MoveStatus = "invalid-json"
+	// MoveStatusIllegalMove is the status of a snake that answered with
+	// something that is not a move, see ParseMove.
+	MoveStatusIllegalMove MoveStatus = "illegal-move"
+	// MoveStatusCancelled is the status of a snake that was not waited for,
+	// see ErrMoveCancelled.
+	MoveStatusCancelled MoveStatus = "cancelled"
+	// MoveStatusError is the status of a snake whose request failed in any
+	// other way, e.g. because its URL is not valid.
+	MoveStatusError MoveStatus = "error"
+)
+
+// ClassifyMove returns the status of the move request behind update.
+func ClassifyMove(update *SnakeUpdate) MoveStatus {
+	err := update.Err
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case err == nil:
+		if _, err := ParseMove(update.Move); err != nil {
+			return MoveStatusIllegalMove
 		}
+		return MoveStatusOK
+	case errors.Is(err, ErrMoveCancelled):
+		return MoveStatusCancelled
+	case isTimeout(err):
+		return MoveStatusTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return MoveStatusConnectionRefused
+	case errors.Is(err, ErrBadStatus):
+		return MoveStatusBadStatus
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return MoveStatusInvalidJSON
+	default:
+		return MoveStatusError
+	}
+}
+
+// MoveRequester asks a snake for its next move. The context carries the
+// deadline for the snake's response.
+type MoveRequester interface {
+	RequestMove(ctx context.Context, snake *pb.Snake, payload SnakeRequest) (MoveResponse, error)
+}
+
+// DefaultMoveRequester is used for snakes whose URL scheme has no requester
+// registered. It calls /move over HTTP.
+var DefaultMoveRequester MoveRequester = HTTPMoveRequester{}
+
+var (
+	moveRequesters     = map[string]MoveRequester{}
+	moveRequestersLock sync.RWMutex
+)
+
+// RegisterMoveRequester routes move requests for snakes with a URL of the
+// given scheme to r. This allows in-process bots, for example snakes with a
+// bot:// URL, to play without any network calls.
+func RegisterMoveRequester(scheme string, r MoveRequester) {
+	moveRequestersLock.Lock()
+	defer moveRequestersLock.Unlock()
+	if r == nil {
+		delete(moveRequesters, scheme)
+		return
+	}
+	moveRequesters[scheme] = r
+}
+
+func moveRequesterFor(snake *pb.Snake) MoveRequester {
+	u, err := url.Parse(snake.URL)
+	if err != nil {
+		return DefaultMoveRequester
+	}
+	moveRequestersLock.RLock()
+	defer moveRequestersLock.RUnlock()
+	if r, ok := moveRequesters[u.Scheme]; ok {
+		return r
+	}
+	return DefaultMoveRequester
+}
+
+// HTTPMoveRequester requests moves by POSTing to the snake's /move endpoint.
+type HTTPMoveRequester struct{}
+
+// RequestMove POSTs the payload to /move and decodes the snake's response. The
+// request is cancelled once ctx is done.
+func (HTTPMoveRequester) RequestMove(ctx context.Context, snake *pb.Snake, payload SnakeRequest) (MoveResponse, error) {
+	if !isValidURL(snake.URL) {
+		return MoveResponse{}, fmt.Errorf("%w: %s", ErrInvalidSnakeURL, snake.URL)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return MoveResponse{}, err
+	}
+
+	responseData, code, err := postSnakeData(ctx, snake, "move", 0, data)
+	if err != nil {
+		return MoveResponse{}, err
+	}
+	if code != http.StatusOK {
+		return MoveResponse{}, &responseError{err: fmt.Errorf("%w: %d", ErrBadStatus, code), body: responseData}
 	}
 
-	return &SnakeUpdate{
-		Snake: resp.snake,
-		Err:   resp.err,
+	moveResponse := MoveResponse{}
+	if err := json.Unmarshal(responseData, &moveResponse); err != nil {
+		return MoveResponse{}, &responseError{err: err, body: responseData}
 	}
+	return moveResponse, nil
+}
+
+// responseError is returned for a snake that answered with something other
+// than a move, it keeps the body the snake sent so it can be logged, see
+// Ruleset.DebugMoveRequests.
+type responseError struct {
+	err  error
+	body []byte
 }
 
-// GatherSnakeMoves goes and queries each snake for the snake move
-func GatherSnakeMoves(timeout time.Duration, game *pb.Game, gameFrame *pb.GameFrame) []*SnakeUpdate {
-	responses := gatherAliveSnakeResponses(multiSnakeRequest{
-		url:     "move",
-		timeout: timeout,
-		game:    game,
-		frame:   gameFrame,
-	})
+func (e *responseError) Error() string { return e.err.Error() }
+func (e *responseError) Unwrap() error { return e.err }
+
+// debugBodyLimit is the number of bytes of a response body logged for a
+// failed move request, longer bodies are truncated.
+const debugBodyLimit = 1024
+
+// logFailedMove logs the payload sent for the failed move request of update
+// and, when the snake answered, the body of its response.
+func logFailedMove(update *SnakeUpdate, payload SnakeRequest) {
+	fields := log.Fields{
+		"snakeID": update.Snake.ID,
+		"url":     update.Snake.URL,
+		"status":  update.Snake.MoveStatus,
+	}
+	if data, err := json.Marshal(payload); err == nil {
+		fields["payload"] = string(data)
+	}
+	var respErr *responseError
+	if errors.As(update.Err, &respErr) {
+		body := respErr.body
+		if len(body) > debugBodyLimit {
+			body = body[:debugBodyLimit]
+		}
+		fields["response"] = string(body)
+	}
+	log.WithError(update.Err).WithFields(fields).Info("move request failed")
+}
+
+// GatherSnakeMoves goes and queries each snake for the snake move. Each snake
+// is asked through the MoveRequester registered for its URL scheme. It keeps
+// count of the requests each snake failed in a row so unresponsive snakes can
+// be eliminated. The first move does not reset the count, so a failed /start is
+// still taken into account when the first turn is checked for deaths.
+//
+// Cancelling ctx stops the wait. Snakes that did not answer by then get
+// ErrMoveCancelled, which is not counted as a failure, and make their default
+// move: they keep going in the direction they were heading.
+//
+// Ruleset.MaxConcurrentMoves limits how many requests are in flight at once,
+// the others wait for one of them to finish. Waiting counts towards the
+// timeout.
+//
+// Every snake asked gets the MoveStatus of its request, see ClassifyMove. Next
+// to the updates it returns a summary of how the requests went.
+//
+// With Ruleset.DebugMoveRequests set, every request that fails, other than by
+// being cancelled, is logged with the payload sent and the body the snake
+// answered with, if any. The payload holds the whole board and what snakes
+// answer is up to their authors, so this is off by default.
+func GatherSnakeMoves(ctx context.Context, timeout time.Duration, game *pb.Game, gameFrame *pb.GameFrame) ([]*SnakeUpdate, MoveSummary) {
+	updates := gatherSnakeMoves(ctx, timeout, game, gameFrame, nil)
+	return updates, SummarizeMoves(updates)
+}
+
+// normalizeMove returns the canonical form of a move a snake sent, so "Up"
+// is played as MoveUp. Anything that is not a move is kept as sent, the snake
+// makes its default move for it.
+func normalizeMove(s string) string {
+	if move, err := ParseMove(s); err == nil {
+		return string(move)
+	}
+	return s
+}
+
+// gatherSnakeMoves is GatherSnakeMoves, but it also stops waiting as soon as
+// decided reports that the moves received so far decide the game.
+func gatherSnakeMoves(ctx context.Context, timeout time.Duration, game *pb.Game, gameFrame *pb.GameFrame, decided func(received []*SnakeUpdate, pending int) bool) []*SnakeUpdate {
+	requestCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	snakes := gameFrame.AliveSnakes()
+	updates := make(chan *SnakeUpdate, len(snakes))
+	// slots limits the requests in flight when the ruleset asks for it. A
+	// request waits for a slot within the same timeout as the others, so a
+	// snake still gets its turn as long as there is time left.
+	var slots chan struct{}
+	if n := game.GetRuleset().GetMaxConcurrentMoves(); n > 0 {
+		slots = make(chan struct{}, n)
+	}
+	debug := game.GetRuleset().GetDebugMoveRequests()
+	payloads := map[*pb.Snake]SnakeRequest{}
+	for _, snake := range snakes {
+		// The payload is built up front, the frame may be advanced while
+		// requests we stopped waiting for are still running.
+		payload := buildSnakeRequest(game, gameFrame, snake.ID)
+		if debug {
+			payloads[snake] = payload
+		}
+		requester := moveRequesterFor(snake)
+		go func(s *pb.Snake) {
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-requestCtx.Done():
+					updates <- &SnakeUpdate{Snake: s, Err: requestCtx.Err()}
+					return
+				}
+			}
+			start := time.Now()
+			move, err := requester.RequestMove(requestCtx, s, payload)
+			updates <- &SnakeUpdate{
+				Snake:   s,
+				Move:    normalizeMove(move.Move),
+				Err:     err,
+				Latency: time.Since(start),
+			}
+		}(snake)
+	}
 
 	ret := []*SnakeUpdate{}
-	for _, resp := range responses {
-		ret = append(ret, toSnakeUpdate(resp))
+	answered := map[*pb.Snake]bool{}
+wait:
+	for len(ret) < len(snakes) {
+		select {
+		case update := <-updates:
+			trackFailures(update.Snake, update.Err, gameFrame.Turn > 0)
+			update.Snake.MoveStatus = string(ClassifyMove(update))
+			if debug && update.Err != nil && !errors.Is(update.Err, ErrMoveCancelled) {
+				logFailedMove(update, payloads[update.Snake])
+			}
+			ret = append(ret, update)
+			answered[update.Snake] = true
+			if decided != nil && len(ret) < len(snakes) && decided(ret, len(snakes)-len(ret)) {
+				break wait
+			}
+		case <-ctx.Done():
+			break wait
+		}
+	}
+	for _, s := range snakes {
+		if !answered[s] {
+			s.MoveStatus = string(MoveStatusCancelled)
+			ret = append(ret, &SnakeUpdate{Snake: s, Err: ErrMoveCancelled})
+		}
 	}
 	return ret
 }
+
+// movesDecideGame returns the check gatherSnakeMoves uses to stop waiting for
+// moves when the ruleset asks for it. The game is decided when every snake
+// that answered dies whatever the others do, by starving, running into a wall
+// or into itself, and at most one snake is left to answer: the game is over
+// after this turn either way. The snake left makes its default move, so it
+// may still die. Games that respawn snakes are never decided early.
+func movesDecideGame(game *pb.Game, gameFrame *pb.GameFrame, ruleset *pb.Ruleset) func([]*SnakeUpdate, int) bool {
+	if !ruleset.GetShortCircuitGameOver() || ruleset.GetRespawnAfterTurns() > 0 {
+		return nil
+	}
+	return func(received []*SnakeUpdate, pending int) bool {
+		if pending > 1 {
+			return false
+		}
+		for _, u := range received {
+			if !moveIsFatal(game, gameFrame, u, ruleset) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// moveIsFatal reports whether the snake of update dies on the turn after frame
+// no matter what the other snakes do. The snake itself is not changed.
+func moveIsFatal(game *pb.Game, frame *pb.GameFrame, update *SnakeUpdate, ruleset *pb.Ruleset) bool {
+	s := update.Snake
+	if deathByHealth(s.Health) || deathByNotResponding(s, frame.Turn+1, ruleset) {
+		return true
+	}
+	moved := &pb.Snake{Body: s.Body}
+	if update.Err != nil {
+		moved.DefaultMove()
+	} else {
+		moved.Move(update.Move)
+	}
+	head := moved.Head()
+	if head == nil {
+		return true
+	}
+	if ruleset.GetWrapHorizontal() {
+		head.X = wrap(head.X, game.Width)
+	}
+	if ruleset.GetWrapVertical() {
+		head.Y = wrap(head.Y, game.Height)
+	}
+	if deathByOutOfBounds(head, game.Width, game.Height) || containsPoint(game.Obstacles, head) {
+		return true
+	}
+	body := s.Body
+	grows := containsPoint(frame.Food, head) && !atMaxLength(moved, ruleset)
+	if tailsMove(game) && !grows && len(body) > 0 {
+		body = body[:len(body)-1]
+	}
+	return containsPoint(body, head)
+}
+
+// flagUnresponsive marks the snakes that made UnresponsiveAfterMoves default
+// moves in a row as unresponsive. A snake makes a default move every time its
+// move request fails, so this tells a snake whose server stopped answering
+// apart from one that is playing. The flag is cleared as soon as the snake
+// answers again. Eliminating such snakes is left to MaxConsecutiveFailures.
+func flagUnresponsive(frame *pb.GameFrame, ruleset *pb.Ruleset) {
+	after := ruleset.GetUnresponsiveAfterMoves()
+	for _, s := range frame.AliveSnakes() {
+		s.Unresponsive = after > 0 && s.ConsecutiveFailures >= after
+	}
+}
+
+func trackFailures(snake *pb.Snake, err error, reset bool) {
+	if err != nil {
+		snake.ConsecutiveFailures++
+	} else if reset {
+		snake.ConsecutiveFailures = 0
+	}
+}
@@ -1,10 +1,18 @@
 package rules
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/battlesnakeio/engine/controller/pb"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,11 +43,391 @@ func TestGatherSnakeMovesBadJSON(t *testing.T) {
 	}
 }
 
+func TestGatherSnakeMovesTracksFailures(t *testing.T) {
+	createClient = func(time.Duration) httpClient {
+		return mockHTTPClient{
+			err: errors.New("fail"),
+		}
+	}
+	snake := &pb.Snake{URL: "http://dead-server", ConsecutiveFailures: 1}
+	frame := &pb.GameFrame{Turn: 4, Snakes: []*pb.Snake{snake}}
+
+	GatherSnakeMoves(context.Background(), time.Second, &pb.Game{}, frame)
+	require.Equal(t, int32(2), snake.ConsecutiveFailures)
+
+	createClient = singleEndpointMockClient(t, "http://dead-server/move", "{\"move\":\"up\"}", 200)
+	GatherSnakeMoves(context.Background(), time.Second, &pb.Game{}, frame)
+	require.Equal(t, int32(0), snake.ConsecutiveFailures)
+}
+
+func TestGatherSnakeMovesFirstMoveKeepsStartFailure(t *testing.T) {
+	createClient = singleEndpointMockClient(t, "http://slow-start/move", "{\"move\":\"up\"}", 200)
+	snake := &pb.Snake{URL: "http://slow-start", ConsecutiveFailures: 1}
+
+	GatherSnakeMoves(context.Background(), time.Second, &pb.Game{}, &pb.GameFrame{Snakes: []*pb.Snake{snake}})
+	require.Equal(t, int32(1), snake.ConsecutiveFailures)
+}
+
+type inProcessBot struct {
+	move string
+}
+
+func (b inProcessBot) RequestMove(ctx context.Context, snake *pb.Snake, payload SnakeRequest) (MoveResponse, error) {
+	if payload.You.ID != snake.ID {
+		return MoveResponse{}, errors.New("payload is for another snake")
+	}
+	return MoveResponse{Move: b.move}, nil
+}
+
+func TestGatherSnakeMovesInProcessRequester(t *testing.T) {
+	RegisterMoveRequester("bot", inProcessBot{move: "left"})
+	defer RegisterMoveRequester("bot", nil)
+	createClient = func(time.Duration) httpClient {
+		return mockHTTPClient{
+			err: errors.New("no network for bots"),
+		}
+	}
+
+	updates, _ := GatherSnakeMoves(context.Background(), time.Second, &pb.Game{}, &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			&pb.Snake{
+				ID:  "bot-1",
+				URL: "bot://lefty",
+			},
+		},
+	})
+	require.Len(t, updates, 1)
+	require.NoError(t, updates[0].Err)
+	require.Equal(t, "left", updates[0].Move)
+}
+
+func TestGatherSnakeMovesNormalizesMoves(t *testing.T) {
+	RegisterMoveRequester("bot", inProcessBot{move: " Left"})
+	defer RegisterMoveRequester("bot", nil)
+	RegisterMoveRequester("lost", inProcessBot{move: "north"})
+	defer RegisterMoveRequester("lost", nil)
+
+	updates, _ := GatherSnakeMoves(context.Background(), time.Second, &pb.Game{}, &pb.GameFrame{
+		Snakes: []*pb.Snake{{ID: "1", URL: "bot://1"}},
+	})
+	require.Equal(t, string(MoveLeft), updates[0].Move)
+
+	// Moves that are not moves are passed on as sent.
+	updates, _ = GatherSnakeMoves(context.Background(), time.Second, &pb.Game{}, &pb.GameFrame{
+		Snakes: []*pb.Snake{{ID: "1", URL: "lost://1"}},
+	})
+	require.Equal(t, "north", updates[0].Move)
+}
+
+func TestGatherSnakeMovesSummary(t *testing.T) {
+	RegisterMoveRequester("bot", inProcessBot{move: "left"})
+	defer RegisterMoveRequester("bot", nil)
+	RegisterMoveRequester("slow", slowBot{})
+	defer RegisterMoveRequester("slow", nil)
+
+	updates, summary := GatherSnakeMoves(context.Background(), 50*time.Millisecond, &pb.Game{}, &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			{ID: "1", URL: "bot://1"},
+			{ID: "2", URL: "bot://2"},
+			{ID: "3", URL: "slow://3"},
+			{ID: "4"},
+		},
+	})
+	require.Len(t, updates, 4)
+	summary.MaxLatency = 0
+	require.Equal(t, MoveSummary{Succeeded: 2, Failed: 1, TimedOut: 1}, summary)
+}
+
+func TestGatherSnakeMovesMaxConcurrent(t *testing.T) {
+	bot := &countingBot{delay: 10 * time.Millisecond}
+	RegisterMoveRequester("count", bot)
+	defer RegisterMoveRequester("count", nil)
+
+	frame := &pb.GameFrame{}
+	for i := 0; i < 6; i++ {
+		frame.Snakes = append(frame.Snakes, &pb.Snake{ID: fmt.Sprint(i), URL: "count://bot"})
+	}
+	game := &pb.Game{Ruleset: &pb.Ruleset{MaxConcurrentMoves: 2}}
+	updates, summary := GatherSnakeMoves(context.Background(), time.Second, game, frame)
+	require.Len(t, updates, 6)
+	require.True(t, summary.MaxLatency >= bot.delay, "latency %v below the bot delay", summary.MaxLatency)
+	summary.MaxLatency = 0
+	require.Equal(t, MoveSummary{Succeeded: 6}, summary)
+	require.Equal(t, int32(2), bot.max)
+}
+
+func TestGatherSnakeMovesMaxConcurrentTimeout(t *testing.T) {
+	RegisterMoveRequester("slow", slowBot{})
+	defer RegisterMoveRequester("slow", nil)
+
+	// The slow snake holds the only slot, the other one never gets to ask.
+	game := &pb.Game{Ruleset: &pb.Ruleset{MaxConcurrentMoves: 1}}
+	_, summary := GatherSnakeMoves(context.Background(), 50*time.Millisecond, game, &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			{ID: "1", URL: "slow://1"},
+			{ID: "2", URL: "slow://2"},
+		},
+	})
+	require.Equal(t, MoveSummary{TimedOut: 2}, summary)
+}
+
+func TestSummarizeMoves(t *testing.T) {
+	summary := SummarizeMoves([]*SnakeUpdate{
+		{Move: "up", Latency: 30 * time.Millisecond},
+		{Move: "up", Latency: 80 * time.Millisecond},
+		{Err: ErrMoveCancelled},
+		{Err: fmt.Errorf("%w: slow", context.DeadlineExceeded), Latency: time.Second},
+		{Err: ErrInvalidSnakeURL},
+	})
+	require.Equal(t, MoveSummary{Succeeded: 2, Failed: 1, TimedOut: 1, Cancelled: 1, MaxLatency: 80 * time.Millisecond}, summary)
+	require.Equal(t, MoveSummary{}, SummarizeMoves(nil))
+}
+
+func TestGatherSnakeMovesInvalidURL(t *testing.T) {
+	updates, _ := GatherSnakeMoves(context.Background(), time.Second, &pb.Game{}, &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			&pb.Snake{},
+		},
+	})
+	require.Len(t, updates, 1)
+	require.Error(t, updates[0].Err)
+}
+
+// gatherMoveStatus asks a single snake at url for its move and returns the
+// status stamped on it.
+func gatherMoveStatus(t *testing.T, url string, timeout time.Duration) MoveStatus {
+	snake := &pb.Snake{ID: "1", URL: url, MoveStatus: "stale"}
+	updates, _ := GatherSnakeMoves(context.Background(), timeout, &pb.Game{}, &pb.GameFrame{
+		Turn:   1,
+		Snakes: []*pb.Snake{snake},
+	})
+	require.Len(t, updates, 1)
+	return MoveStatus(snake.MoveStatus)
+}
+
+func TestGatherSnakeMovesStatusOK(t *testing.T) {
+	createClient = singleEndpointMockClient(t, "http://snake/move", "{\"move\":\"up\"}", 200)
+	require.Equal(t, MoveStatusOK, gatherMoveStatus(t, "http://snake", time.Second))
+}
+
+func TestGatherSnakeMovesStatusTimeout(t *testing.T) {
+	RegisterMoveRequester("slow", slowBot{})
+	defer RegisterMoveRequester("slow", nil)
+	require.Equal(t, MoveStatusTimeout, gatherMoveStatus(t, "slow://1", 20*time.Millisecond))
+}
+
+func TestGatherSnakeMovesStatusConnectionRefused(t *testing.T) {
+	// Nothing listens on the address once the listener is closed.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	createClient = getNetClient
+	require.Equal(t, MoveStatusConnectionRefused, gatherMoveStatus(t, "http://"+addr, time.Second))
+}
+
+func TestGatherSnakeMovesStatusNon200(t *testing.T) {
+	createClient = singleEndpointMockClient(t, "http://snake/move", "{\"move\":\"up\"}", 500)
+	require.Equal(t, MoveStatusBadStatus, gatherMoveStatus(t, "http://snake", time.Second))
+}
+
+func TestGatherSnakeMovesStatusInvalidJSON(t *testing.T) {
+	createClient = singleEndpointMockClient(t, "http://snake/move", "{{", 200)
+	require.Equal(t, MoveStatusInvalidJSON, gatherMoveStatus(t, "http://snake", time.Second))
+
+	createClient = singleEndpointMockClient(t, "http://snake/move", "{\"move\":1}", 200)
+	require.Equal(t, MoveStatusInvalidJSON, gatherMoveStatus(t, "http://snake", time.Second))
+}
+
+func TestGatherSnakeMovesStatusIllegalMove(t *testing.T) {
+	createClient = singleEndpointMockClient(t, "http://snake/move", "{\"move\":\"north\"}", 200)
+	require.Equal(t, MoveStatusIllegalMove, gatherMoveStatus(t, "http://snake", time.Second))
+}
+
+func TestGatherSnakeMovesStatusError(t *testing.T) {
+	require.Equal(t, MoveStatusError, gatherMoveStatus(t, "", time.Second))
+}
+
+// gatherLogged asks a single snake for its move and returns what was logged.
+func gatherLogged(t *testing.T, ruleset *pb.Ruleset) string {
+	var buf bytes.Buffer
+	out := log.StandardLogger().Out
+	log.SetOutput(&buf)
+	defer log.SetOutput(out)
+
+	snake := &pb.Snake{ID: "1", URL: "http://snake"}
+	game := &pb.Game{ID: "game", Width: 3, Height: 3, Ruleset: ruleset}
+	updates, _ := GatherSnakeMoves(context.Background(), time.Second, game, &pb.GameFrame{
+		Turn:   1,
+		Snakes: []*pb.Snake{snake},
+	})
+	require.Len(t, updates, 1)
+	return buf.String()
+}
+
+func TestGatherSnakeMovesDebugMoveRequests(t *testing.T) {
+	body := "not a move " + strings.Repeat("x", 2*debugBodyLimit)
+	createClient = singleEndpointMockClient(t, "http://snake/move", body, 200)
+
+	logged := gatherLogged(t, &pb.Ruleset{DebugMoveRequests: true})
+	require.Contains(t, logged, "move request failed")
+	require.Contains(t, logged, `\"game\":{\"id\":\"game\"`)
+	require.Contains(t, logged, "not a move "+strings.Repeat("x", debugBodyLimit-len("not a move ")))
+	require.NotContains(t, logged, strings.Repeat("x", debugBodyLimit))
+
+	require.NotContains(t, gatherLogged(t, &pb.Ruleset{}), "move request failed")
+}
+
+func TestGatherSnakeMovesDebugMoveRequestsSucceeded(t *testing.T) {
+	createClient = singleEndpointMockClient(t, "http://snake/move", "{\"move\":\"up\"}", 200)
+	require.NotContains(t, gatherLogged(t, &pb.Ruleset{DebugMoveRequests: true}), "move request failed")
+}
+
+// slowBot answers only once its request is cancelled.
+type slowBot struct{}
+
+func (slowBot) RequestMove(ctx context.Context, snake *pb.Snake, payload SnakeRequest) (MoveResponse, error) {
+	<-ctx.Done()
+	return MoveResponse{}, ctx.Err()
+}
+
+// countingBot answers after delay and keeps track of the most requests it had
+// in flight at once.
+type countingBot struct {
+	delay    time.Duration
+	lock     sync.Mutex
+	inFlight int32
+	max      int32
+}
+
+func (b *countingBot) RequestMove(ctx context.Context, snake *pb.Snake, payload SnakeRequest) (MoveResponse, error) {
+	b.lock.Lock()
+	b.inFlight++
+	if b.inFlight > b.max {
+		b.max = b.inFlight
+	}
+	b.lock.Unlock()
+	defer func() {
+		b.lock.Lock()
+		b.inFlight--
+		b.lock.Unlock()
+	}()
+	time.Sleep(b.delay)
+	return MoveResponse{Move: "up"}, nil
+}
+
+// lateBot answers after delay, unless its request is cancelled first.
+type lateBot struct {
+	delay time.Duration
+}
+
+func (b lateBot) RequestMove(ctx context.Context, snake *pb.Snake, payload SnakeRequest) (MoveResponse, error) {
+	select {
+	case <-time.After(b.delay):
+		return MoveResponse{Move: "left"}, nil
+	case <-ctx.Done():
+		return MoveResponse{}, ctx.Err()
+	}
+}
+
+func TestGameTickSnakeTimeoutGrace(t *testing.T) {
+	RegisterMoveRequester("late", lateBot{delay: 60 * time.Millisecond})
+	defer RegisterMoveRequester("late", nil)
+
+	game := &pb.Game{Width: 10, Height: 10, Ruleset: &pb.Ruleset{SnakeTimeout: 20}}
+	frame := func() *pb.GameFrame {
+		return &pb.GameFrame{Snakes: []*pb.Snake{{
+			ID:     "1",
+			URL:    "late://1",
+			Health: 100,
+			Body:   []*pb.Point{{X: 5, Y: 5}, {X: 5, Y: 6}},
+		}}}
+	}
+
+	// Without a grace the move arrives too late, the snake keeps going up.
+	next, err := GameTick(context.Background(), game, frame())
+	require.NoError(t, err)
+	require.Equal(t, &pb.Point{X: 5, Y: 4}, next.Snakes[0].Head())
+	require.Equal(t, int32(1), next.Snakes[0].ConsecutiveFailures)
+
+	game.Ruleset.SnakeTimeoutGrace = 1000
+	next, err = GameTick(context.Background(), game, frame())
+	require.NoError(t, err)
+	require.Equal(t, &pb.Point{X: 4, Y: 5}, next.Snakes[0].Head())
+	require.Equal(t, int32(0), next.Snakes[0].ConsecutiveFailures)
+}
+
+func TestGatherSnakeMovesCancelled(t *testing.T) {
+	RegisterMoveRequester("bot", inProcessBot{move: "left"})
+	defer RegisterMoveRequester("bot", nil)
+	RegisterMoveRequester("slow", slowBot{})
+	defer RegisterMoveRequester("slow", nil)
+
+	fast := &pb.Snake{ID: "fast", URL: "bot://fast"}
+	slow := &pb.Snake{ID: "slow", URL: "slow://slow", ConsecutiveFailures: 1}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	updates, _ := GatherSnakeMoves(ctx, time.Minute, &pb.Game{}, &pb.GameFrame{
+		Turn:   3,
+		Snakes: []*pb.Snake{fast, slow},
+	})
+	require.Len(t, updates, 2)
+	require.Equal(t, fast, updates[0].Snake)
+	require.Equal(t, "left", updates[0].Move)
+	require.Equal(t, slow, updates[1].Snake)
+	require.True(t, errors.Is(updates[1].Err, ErrMoveCancelled))
+	require.Equal(t, int32(1), slow.ConsecutiveFailures, "a cancelled move is not a failure")
+	require.Equal(t, string(MoveStatusOK), fast.MoveStatus)
+	require.Equal(t, string(MoveStatusCancelled), slow.MoveStatus)
+}
+
+func TestGameTickShortCircuitGameOver(t *testing.T) {
+	RegisterMoveRequester("bot", inProcessBot{move: "left"})
+	defer RegisterMoveRequester("bot", nil)
+	RegisterMoveRequester("slow", slowBot{})
+	defer RegisterMoveRequester("slow", nil)
+
+	wall := &pb.Snake{
+		ID:     "wall",
+		URL:    "bot://wall",
+		Health: 100,
+		Body:   []*pb.Point{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}},
+	}
+	slow := &pb.Snake{
+		ID:     "slow",
+		URL:    "slow://slow",
+		Health: 100,
+		Body:   []*pb.Point{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}},
+	}
+	game := &pb.Game{
+		Width:        10,
+		Height:       10,
+		SnakeTimeout: 60000,
+		Ruleset:      &pb.Ruleset{ShortCircuitGameOver: true},
+	}
+
+	// The wall snake dies whatever the slow one does, so the tick does not
+	// wait for it.
+	start := time.Now()
+	frame, err := GameTick(context.Background(), game, &pb.GameFrame{Turn: 5, Snakes: []*pb.Snake{wall, slow}})
+	require.NoError(t, err)
+	require.True(t, time.Since(start) < 5*time.Second)
+	wall, slow = frame.Snakes[0], frame.Snakes[1]
+	require.NotNil(t, wall.Death)
+	require.Equal(t, DeathCauseWallCollision, wall.Death.Cause)
+	require.Nil(t, slow.Death)
+	require.Equal(t, &pb.Point{X: 6, Y: 5}, slow.Head(), "the slow snake keeps its heading")
+	require.Equal(t, int32(0), slow.ConsecutiveFailures)
+	require.True(t, CheckForGameOver(GameModeMultiPlayer, frame))
+}
+
 func gatherMoveResponses(t *testing.T, json string, updates chan<- *SnakeUpdate) {
 	createClient = singleEndpointMockClient(t, "http://not.a.snake.com/move", json, 200)
 
 	go func() {
-		u := GatherSnakeMoves(1*time.Second, &pb.Game{}, &pb.GameFrame{
+		u, _ := GatherSnakeMoves(context.Background(), 1*time.Second, &pb.Game{}, &pb.GameFrame{
 			Snakes: []*pb.Snake{
 				&pb.Snake{
 					URL: "http://not.a.snake.com",
@@ -51,3 +439,82 @@ func gatherMoveResponses(t *testing.T, json string, updates chan<- *SnakeUpdate)
 		}
 	}()
 }
+
+// flakyBot fails every move request while down is set.
+type flakyBot struct {
+	down *bool
+}
+
+func (b flakyBot) RequestMove(ctx context.Context, snake *pb.Snake, payload SnakeRequest) (MoveResponse, error) {
+	if *b.down {
+		return MoveResponse{}, errors.New("bot is down")
+	}
+	return MoveResponse{Move: "down"}, nil
+}
+
+func TestUnresponsiveSnakeFlagged(t *testing.T) {
+	down := true
+	RegisterMoveRequester("flaky", flakyBot{down: &down})
+	defer RegisterMoveRequester("flaky", nil)
+
+	snake := &pb.Snake{
+		ID:     "1",
+		URL:    "flaky://1",
+		Health: 100,
+		Body:   []*pb.Point{{X: 10, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 10}},
+	}
+	game := &pb.Game{
+		Width:   20,
+		Height:  20,
+		Ruleset: &pb.Ruleset{UnresponsiveAfterMoves: 3},
+	}
+	frame := &pb.GameFrame{Turn: 1, Snakes: []*pb.Snake{snake}}
+
+	var err error
+	for i := 1; i <= 3; i++ {
+		require.False(t, frame.Snakes[0].Unresponsive, "flagged after %d default moves", i-1)
+		frame, err = GameTick(context.Background(), game, frame)
+		require.NoError(t, err)
+	}
+	snake = frame.Snakes[0]
+	require.True(t, snake.Unresponsive)
+	require.Nil(t, snake.Death, "flagging alone does not eliminate")
+
+	// The snake is no longer flagged once it answers again.
+	down = false
+	frame, err = GameTick(context.Background(), game, frame)
+	require.NoError(t, err)
+	snake = frame.Snakes[0]
+	require.False(t, snake.Unresponsive)
+	require.Equal(t, int32(0), snake.ConsecutiveFailures)
+}
+
+func TestUnresponsiveSnakeEliminated(t *testing.T) {
+	down := true
+	RegisterMoveRequester("flaky", flakyBot{down: &down})
+	defer RegisterMoveRequester("flaky", nil)
+
+	snake := &pb.Snake{
+		ID:     "1",
+		URL:    "flaky://1",
+		Health: 100,
+		Body:   []*pb.Point{{X: 10, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 10}},
+	}
+	game := &pb.Game{
+		Width:   20,
+		Height:  20,
+		Ruleset: &pb.Ruleset{UnresponsiveAfterMoves: 3, MaxConsecutiveFailures: 3},
+	}
+	frame := &pb.GameFrame{Turn: 1, Snakes: []*pb.Snake{snake}}
+
+	var err error
+	for i := 0; i < 3; i++ {
+		frame, err = GameTick(context.Background(), game, frame)
+		require.NoError(t, err)
+	}
+	snake = frame.Snakes[0]
+	require.True(t, snake.Unresponsive)
+	require.NotNil(t, snake.Death)
+	require.Equal(t, DeathCauseNotResponding, snake.Death.Cause)
+	require.Equal(t, int32(4), snake.Death.Turn)
+}
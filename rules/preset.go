@@ -0,0 +1,74 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Board preset names.
+const (
+	// BoardPresetStandard is an 11x11 board for up to 8 snakes.
+	BoardPresetStandard = "standard"
+	// BoardPresetLarge is a 19x19 board for up to 16 snakes.
+	BoardPresetLarge = "large"
+	// BoardPresetDuel is an 11x11 board for exactly 2 snakes.
+	BoardPresetDuel = "duel"
+)
+
+type boardPreset struct {
+	width     int32
+	height    int32
+	food      int32
+	minSnakes int
+	maxSnakes int
+}
+
+var boardPresets = map[string]boardPreset{
+	BoardPresetStandard: {width: 11, height: 11, food: 5, minSnakes: 1, maxSnakes: 8},
+	BoardPresetLarge:    {width: 19, height: 19, food: 10, minSnakes: 1, maxSnakes: 16},
+	BoardPresetDuel:     {width: 11, height: 11, food: 3, minSnakes: 2, maxSnakes: 2},
+}
+
+// BoardPreset returns a create request for the named board preset. The
+// request is a copy of overrides with the size and food of the preset filled
+// in where overrides leaves them zero, so a preset can be adjusted without
+// restating all of it. When overrides has snakes, their number is checked
+// against what the preset allows.
+func BoardPreset(name string, overrides *pb.CreateRequest) (*pb.CreateRequest, error) {
+	preset, ok := boardPresets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q, expected one of %s", ErrUnknownPreset, name, boardPresetNames())
+	}
+
+	req := &pb.CreateRequest{}
+	if overrides != nil {
+		req = proto.Clone(overrides).(*pb.CreateRequest)
+	}
+	if req.Width == 0 {
+		req.Width = preset.width
+	}
+	if req.Height == 0 {
+		req.Height = preset.height
+	}
+	if req.Food == 0 {
+		req.Food = preset.food
+	}
+
+	if n := len(req.Snakes); n > 0 && (n < preset.minSnakes || n > preset.maxSnakes) {
+		return nil, fmt.Errorf("%w: preset %q needs %d to %d snakes, got %d", ErrInvalidBoard, name, preset.minSnakes, preset.maxSnakes, n)
+	}
+	return req, nil
+}
+
+func boardPresetNames() string {
+	names := []string{}
+	for name := range boardPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,69 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardPresetStandard(t *testing.T) {
+	req, err := BoardPreset(BoardPresetStandard, nil)
+	require.NoError(t, err)
+	require.Equal(t, int32(11), req.Width)
+	require.Equal(t, int32(11), req.Height)
+	require.Equal(t, int32(5), req.Food)
+}
+
+func TestBoardPresetLarge(t *testing.T) {
+	req, err := BoardPreset(BoardPresetLarge, nil)
+	require.NoError(t, err)
+	require.Equal(t, int32(19), req.Width)
+	require.Equal(t, int32(19), req.Height)
+}
+
+func TestBoardPresetOverrides(t *testing.T) {
+	overrides := &pb.CreateRequest{
+		Food:    1,
+		Snakes:  []*pb.SnakeOptions{{ID: "a"}, {ID: "b"}},
+		Ruleset: &pb.Ruleset{MaxHealth: 50},
+	}
+	req, err := BoardPreset(BoardPresetDuel, overrides)
+	require.NoError(t, err)
+	require.Equal(t, int32(11), req.Width)
+	require.Equal(t, int32(1), req.Food)
+	require.Equal(t, overrides.Snakes, req.Snakes)
+	require.Equal(t, overrides.Ruleset, req.Ruleset)
+
+	_, _, err = CreateInitialGame(req)
+	require.NoError(t, err)
+}
+
+func TestBoardPresetKeepsOtherFields(t *testing.T) {
+	overrides := &pb.CreateRequest{
+		Seed:      7,
+		Name:      "finals",
+		Obstacles: []*pb.Point{{X: 5, Y: 5}},
+	}
+	req, err := BoardPreset(BoardPresetStandard, overrides)
+	require.NoError(t, err)
+	require.Equal(t, int32(11), req.Width)
+	require.Equal(t, int64(7), req.Seed)
+	require.Equal(t, "finals", req.Name)
+	require.Equal(t, overrides.Obstacles, req.Obstacles)
+	require.Zero(t, overrides.Width, "overrides is not changed")
+}
+
+func TestBoardPresetSnakeCount(t *testing.T) {
+	_, err := BoardPreset(BoardPresetDuel, &pb.CreateRequest{
+		Snakes: []*pb.SnakeOptions{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+	})
+	require.True(t, errors.Is(err, ErrInvalidBoard))
+}
+
+func TestBoardPresetUnknown(t *testing.T) {
+	_, err := BoardPreset("tiny", nil)
+	require.True(t, errors.Is(err, ErrUnknownPreset))
+	require.EqualError(t, err, `rules: unknown board preset "tiny", expected one of duel, large, standard`)
+}
@@ -0,0 +1,126 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+// VerifyReplay re-runs a stored game and checks that every frame follows from
+// the one before it. The moves each snake made are read from how their heads
+// moved between frames, food is placed where the stored frames have new food
+// and snakes respawn where the stored frames have them respawn, so an engine
+// that is deterministic reproduces the stored frames exactly. The game is
+// replayed with the ruleset version it was created with.
+// The frame an aborted game ends on is not replayed, see AbortFrame.
+// The first frame that differs is reported as an error.
+func VerifyReplay(game *pb.Game, frames []*pb.GameFrame) error {
+	if err := ValidateRulesetVersion(game); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(frames); i++ {
+		last, next := frames[i], frames[i+1]
+		if IsAbortFrame(next) {
+			// The game was aborted, no moves were played on its last frame.
+			break
+		}
+		if next.Turn != last.Turn+1 {
+			return fmt.Errorf("%w: turn %d is followed by turn %d", ErrReplayMismatch, last.Turn, next.Turn)
+		}
+
+		// The frame is replayed on a copy, advancing it updates snakes in place.
+		replayed := proto.Clone(last).(*pb.GameFrame)
+		moves, err := recordedMoves(game, replayed, next)
+		if err != nil {
+			return err
+		}
+		placer := &ScriptedFoodPlacer{
+			points:   newFood(last, next),
+			fallback: noFoodPlacer{},
+		}
+
+		respawner := &ScriptedFoodPlacer{
+			points:   respawnPoints(next),
+			fallback: noFoodPlacer{},
+		}
+
+		replayedNext, err := advanceFrameRespawning(context.Background(), game, replayed, moves, placer, respawner)
+		if err != nil {
+			return fmt.Errorf("%w: turn %d: %v", ErrReplayMismatch, next.Turn, err)
+		}
+		if !replayedNext.Equal(next) {
+			return fmt.Errorf("%w: turn %d differs from the stored frame", ErrReplayMismatch, next.Turn)
+		}
+	}
+	return nil
+}
+
+// recordedMoves returns the move every alive snake in last made to get to its
+// position in next. The failure counts and move statuses stored in next are
+// copied over, since they are the recorded outcome of asking the snakes for
+// their moves.
+func recordedMoves(game *pb.Game, last, next *pb.GameFrame) ([]*SnakeUpdate, error) {
+	ruleset := gameRuleset(game)
+	moves := []*SnakeUpdate{}
+	for _, s := range last.AliveSnakes() {
+		n := findSnake(next, s.ID)
+		if n == nil {
+			return nil, fmt.Errorf("%w: snake %s missing from turn %d", ErrReplayMismatch, s.ID, next.Turn)
+		}
+		move, err := moveBetween(s.Head(), unwrapPoint(s.Head(), n.Head(), game.Width, game.Height, ruleset))
+		if err != nil {
+			return nil, fmt.Errorf("%w: snake %s on turn %d: %v", ErrReplayMismatch, s.ID, next.Turn, err)
+		}
+		s.ConsecutiveFailures = n.ConsecutiveFailures
+		s.MoveStatus = n.MoveStatus
+		moves = append(moves, &SnakeUpdate{Snake: s, Move: move})
+	}
+	return moves, nil
+}
+
+func moveBetween(from, to *pb.Point) (string, error) {
+	if from == nil || to == nil {
+		return "", errors.New("snake has no head")
+	}
+	for _, move := range snakeMoves {
+		if NextHead(from, string(move)).Equal(to) {
+			return string(move), nil
+		}
+	}
+	return "", fmt.Errorf("head moved from %v to %v", from, to)
+}
+
+func findSnake(frame *pb.GameFrame, id string) *pb.Snake {
+	for _, s := range frame.Snakes {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// respawnPoints returns the squares snakes respawned on in frame, in the
+// order they respawned.
+func respawnPoints(frame *pb.GameFrame) []*pb.Point {
+	points := []*pb.Point{}
+	for _, e := range frame.Events {
+		if e.Type == FrameEventRespawned && e.Point != nil {
+			points = append(points, e.Point)
+		}
+	}
+	return points
+}
+
+// newFood returns the food in next that was not in last.
+func newFood(last, next *pb.GameFrame) []*pb.Point {
+	food := []*pb.Point{}
+	for _, f := range next.Food {
+		if !containsPoint(last.Food, f) {
+			food = append(food, f)
+		}
+	}
+	return food
+}
@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+// playGame plays a few turns with in-process snakes and returns every frame.
+func playGame(t *testing.T) (*pb.Game, []*pb.GameFrame) {
+	RegisterMoveRequester("bot", inProcessBot{move: "down"})
+	defer RegisterMoveRequester("bot", nil)
+
+	game := &pb.Game{ID: "replay", Width: 11, Height: 11, SnakeTimeout: 100}
+	frame := &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			{
+				ID:     "1",
+				URL:    "bot://1",
+				Health: 100,
+				Body:   []*pb.Point{{X: 2, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 2}},
+			},
+			{
+				ID:     "2",
+				URL:    "bot://2",
+				Health: 100,
+				Body:   []*pb.Point{{X: 6, Y: 2}, {X: 6, Y: 2}, {X: 6, Y: 2}},
+			},
+		},
+		Food: []*pb.Point{{X: 2, Y: 4}, {X: 6, Y: 5}},
+	}
+
+	frames := []*pb.GameFrame{proto.Clone(frame).(*pb.GameFrame)}
+	for i := 0; i < 10; i++ {
+		var err error
+		frame, err = GameTick(context.Background(), game, frame)
+		require.NoError(t, err)
+		// Frames share snakes with the next tick, store copies like the
+		// controller does.
+		frames = append(frames, proto.Clone(frame).(*pb.GameFrame))
+	}
+	return game, frames
+}
+
+func TestVerifyReplay(t *testing.T) {
+	game, frames := playGame(t)
+	require.NoError(t, VerifyReplay(game, frames))
+}
+
+func TestVerifyReplayRespawn(t *testing.T) {
+	// The game has no seed, so the snake respawns on a square drawn from the
+	// global source, which the replay can only follow from the stored events.
+	game := &pb.Game{ID: "respawn", Width: 5, Height: 5, Ruleset: &pb.Ruleset{RespawnAfterTurns: 2}}
+	frame := &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 100, Body: []*pb.Point{{X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}}},
+		},
+	}
+
+	frames := []*pb.GameFrame{proto.Clone(frame).(*pb.GameFrame)}
+	for i := 0; i < 4; i++ {
+		var err error
+		// No URL, so the snake default moves up and dies in the wall.
+		frame, err = GameTick(context.Background(), game, frame)
+		require.NoError(t, err)
+		frames = append(frames, proto.Clone(frame).(*pb.GameFrame))
+	}
+	require.Nil(t, frames[3].Snakes[0].Death)
+	require.Len(t, respawnPoints(frames[3]), 1)
+
+	require.NoError(t, VerifyReplay(game, frames))
+
+	frames[3].Events[len(frames[3].Events)-1].Point = &pb.Point{X: 9, Y: 9}
+	require.True(t, errors.Is(VerifyReplay(game, frames), ErrReplayMismatch))
+}
+
+func TestVerifyReplayDivergence(t *testing.T) {
+	game, frames := playGame(t)
+	frames[4].Snakes[0].Health++
+
+	err := VerifyReplay(game, frames)
+	require.True(t, errors.Is(err, ErrReplayMismatch))
+	require.EqualError(t, err, "rules: replay does not match: turn 4 differs from the stored frame")
+}
+
+func TestVerifyReplayImpossibleMove(t *testing.T) {
+	game, frames := playGame(t)
+	frames[3].Snakes[1].Body[0].X += 2
+
+	require.True(t, errors.Is(VerifyReplay(game, frames), ErrReplayMismatch))
+}
+
+func TestVerifyReplayPileupBeforeRulesetVersion5(t *testing.T) {
+	// C, the shortest, moves into A and B, which meet head to head. Before
+	// RulesetVersion5 C is eliminated by the first of them in the frame,
+	// which keeps its order up to RulesetVersion3, so C stays first.
+	game := &pb.Game{ID: "pileup", Width: 11, Height: 11, RulesetVersion: RulesetVersion3}
+	frame := &pb.GameFrame{
+		Turn: 2,
+		Snakes: []*pb.Snake{
+			{ID: "C", Health: 90, Body: []*pb.Point{{X: 5, Y: 6}, {X: 5, Y: 7}}},
+			{ID: "B", Health: 90, Body: []*pb.Point{{X: 6, Y: 5}, {X: 7, Y: 5}, {X: 8, Y: 5}}},
+			{ID: "A", Health: 90, Body: []*pb.Point{{X: 4, Y: 5}, {X: 3, Y: 5}, {X: 2, Y: 5}}},
+		},
+	}
+	advance := func() *pb.GameFrame {
+		last := proto.Clone(frame).(*pb.GameFrame)
+		moves := []*SnakeUpdate{
+			{Snake: last.Snakes[0], Move: "up"},
+			{Snake: last.Snakes[1], Move: "left"},
+			{Snake: last.Snakes[2], Move: "right"},
+		}
+		next, err := advanceFrame(context.Background(), game, last, moves, noFoodPlacer{})
+		require.NoError(t, err)
+		return next
+	}
+	next := advance()
+	require.Equal(t, "B", next.Snakes[0].Death.EliminatedBy)
+
+	require.NoError(t, VerifyReplay(game, []*pb.GameFrame{frame, next}))
+
+	// The same frames do not replay with resolved collisions, where the
+	// lowest ID of the longest snakes eliminates C.
+	game.RulesetVersion = RulesetVersion5
+	require.True(t, errors.Is(VerifyReplay(game, []*pb.GameFrame{frame, next}), ErrReplayMismatch))
+	require.Equal(t, "A", advance().Snakes[0].Death.EliminatedBy)
+}
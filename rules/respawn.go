@@ -0,0 +1,63 @@
+package rules
+
+import (
+	"github.com/battlesnakeio/engine/controller/pb"
+	log "github.com/sirupsen/logrus"
+)
+
+// respawnSnakes brings back snakes that have been dead for the ruleset's
+// RespawnAfterTurns. They are placed on an open square with full health and a
+// fresh stacked body facing the ruleset's StartingDirection, the same way
+// snakes start a game. The body is RespawnLength long when the ruleset sets
+// it, e.g. to bring snakes back shorter. Snakes that find no open square stay
+// dead and are retried on the next turn. The square is picked by placer, or at
+// random when it is nil, drawing from the seed of the game the way food does.
+func respawnSnakes(game *pb.Game, frame *pb.GameFrame, ruleset *pb.Ruleset, placer FoodPlacer) {
+	if ruleset.RespawnAfterTurns <= 0 {
+		return
+	}
+	if placer == nil {
+		placer = RandomFoodPlacer{Rand: seededRand(game.Seed, frame.Turn)}
+	}
+	for _, s := range frame.DeadSnakes() {
+		if frame.Turn-s.Death.Turn < ruleset.RespawnAfterTurns {
+			continue
+		}
+		occupied := append(append([]*pb.Point{}, frame.Food...), game.Obstacles...)
+		p := placer.PlaceFood(game.Width, game.Height, occupied, frame.AliveSnakes())
+		if p == nil {
+			continue
+		}
+		log.WithFields(log.Fields{
+			"GameID":  game.ID,
+			"SnakeID": s.ID,
+			"Turn":    frame.Turn,
+		}).Info("respawn snake")
+		s.Body = stackedBody(p, respawnLength(ruleset))
+		s.Facing = startingFacing(StartingDirection(ruleset.StartingDirection), p, game.Width, game.Height)
+		s.Health = ruleset.StartingHealth
+		s.Death = nil
+		s.ConsecutiveFailures = 0
+		s.Unresponsive = false
+		frame.Events = append(frame.Events, &pb.Event{
+			Type:    FrameEventRespawned,
+			SnakeID: s.ID,
+			Point:   p.Clone(),
+		})
+	}
+}
+
+// respawnLength returns the length of a respawned snake.
+func respawnLength(ruleset *pb.Ruleset) int {
+	if ruleset.RespawnLength > 0 {
+		return int(ruleset.RespawnLength)
+	}
+	return startingLength
+}
+
+// RespawnPending reports whether the game has dead snakes that will respawn.
+// Games with respawning enabled keep running while this is the case, rather
+// than ending when too few snakes are alive.
+func RespawnPending(game *pb.Game, frame *pb.GameFrame) bool {
+	return gameRuleset(game).RespawnAfterTurns > 0 && len(frame.DeadSnakes()) > 0
+}
@@ -0,0 +1,100 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespawnAfterTurns(t *testing.T) {
+	snake := &pb.Snake{
+		ID:     "1",
+		Health: 50,
+		Body:   []*pb.Point{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+	}
+	game := &pb.Game{
+		Width:   5,
+		Height:  5,
+		Ruleset: &pb.Ruleset{RespawnAfterTurns: 3},
+	}
+	frame := &pb.GameFrame{Snakes: []*pb.Snake{snake}}
+
+	// No URL, so the snake default moves up into the wall.
+	frame, err := GameTick(context.Background(), game, frame)
+	require.NoError(t, err)
+	snake = frame.Snakes[0]
+	require.NotNil(t, snake.Death)
+	require.Equal(t, int32(1), snake.Death.Turn)
+	require.True(t, RespawnPending(game, frame))
+
+	for turn := int32(2); turn < 4; turn++ {
+		frame, err = GameTick(context.Background(), game, frame)
+		require.NoError(t, err)
+		snake = frame.Snakes[0]
+		require.NotNil(t, snake.Death, "snake respawned early on turn %d", turn)
+	}
+
+	frame, err = GameTick(context.Background(), game, frame)
+	require.NoError(t, err)
+	require.Equal(t, int32(4), frame.Turn)
+	snake = frame.Snakes[0]
+	require.Nil(t, snake.Death)
+	require.Equal(t, int32(DefaultMaxHealth), snake.Health)
+	require.Len(t, snake.Body, 3)
+	require.True(t, snake.Body[0].Equal(snake.Body[1]))
+	require.True(t, snake.Body[0].Equal(snake.Body[2]))
+	require.False(t, deathByOutOfBounds(snake.Head(), game.Width, game.Height))
+	require.False(t, RespawnPending(game, frame))
+	require.Contains(t, frame.Events, &pb.Event{Type: FrameEventRespawned, SnakeID: "1", Point: snake.Head()})
+}
+
+func TestRespawnLength(t *testing.T) {
+	game := &pb.Game{Width: 3, Height: 1}
+	ruleset := &pb.Ruleset{RespawnAfterTurns: 3, RespawnLength: 1, StartingHealth: 80}
+	dead := &pb.Snake{ID: "dead", Body: []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}, Death: &pb.Death{Turn: 5}}
+	other := &pb.Snake{ID: "other", Health: 50, Body: []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}}
+	frame := &pb.GameFrame{Turn: 8, Snakes: []*pb.Snake{dead, other}}
+
+	// The board is full, the snake waits for space.
+	respawnSnakes(game, frame, ruleset, nil)
+	require.NotNil(t, dead.Death)
+
+	other.Body = other.Body[:2]
+	frame.Turn = 7
+	respawnSnakes(game, frame, ruleset, nil)
+	require.NotNil(t, dead.Death, "respawned before its delay passed")
+
+	frame.Turn = 8
+	respawnSnakes(game, frame, ruleset, nil)
+	require.Nil(t, dead.Death)
+	require.Equal(t, []*pb.Point{{X: 2, Y: 0}}, dead.Body)
+	require.Equal(t, int32(80), dead.Health)
+	require.Equal(t, string(pb.MoveLeft), dead.Facing)
+}
+
+func TestRespawnSeeded(t *testing.T) {
+	game := &pb.Game{Width: 11, Height: 11, Seed: 42}
+	ruleset := &pb.Ruleset{RespawnAfterTurns: 1}
+	respawned := func() []*pb.Point {
+		frame := &pb.GameFrame{Turn: 5, Snakes: []*pb.Snake{{ID: "1", Death: &pb.Death{Turn: 1}}}}
+		respawnSnakes(game, frame, ruleset, nil)
+		require.Nil(t, frame.Snakes[0].Death)
+		return frame.Snakes[0].Body
+	}
+	require.Equal(t, respawned(), respawned())
+}
+
+func TestRespawnDisabled(t *testing.T) {
+	game := &pb.Game{Width: 5, Height: 5}
+	frame := &pb.GameFrame{
+		Turn: 50,
+		Snakes: []*pb.Snake{
+			{Death: &pb.Death{Turn: 1}},
+		},
+	}
+	respawnSnakes(game, frame, gameRuleset(game), nil)
+	require.NotNil(t, frame.Snakes[0].Death)
+	require.False(t, RespawnPending(game, frame))
+}
@@ -0,0 +1,176 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+// RulesetStandard is the name of the standard battlesnake ruleset.
+const RulesetStandard = "standard"
+
+// DefaultMaxHealth is the health a snake has in a standard game when it starts
+// and after it eats.
+const DefaultMaxHealth = 100
+
+// Versions of the rules. A game records the version it was created with, so
+// games and replays keep being played with the rules that were in effect when
+// they started, even after the rules change. The name of the ruleset is not
+// part of the version, it is stored with the rest of the ruleset as
+// Ruleset.Name.
+//
+//	RulesetVersion1  every head-to-head between snakes of equal length kills
+//	                 both snakes, Ruleset.EqualHeadToHead is ignored.
+//	RulesetVersion2  the outcome of a head-to-head between snakes of equal
+//	                 length is taken from Ruleset.EqualHeadToHead.
+//	RulesetVersion3  the tail of a snake that does not eat moves out of the
+//	                 way, so snakes may move onto the square a tail leaves.
+//	                 Before, every tail blocked its square for the turn.
+//	RulesetVersion4  snakes are processed in order of their ID during a tick,
+//	                 so the events of a frame are listed in that order.
+//	                 Before, they followed the order of the snakes in the
+//	                 frame.
+//	RulesetVersion5  collisions are resolved once every head has moved, in
+//	                 an order that does not depend on the order of the
+//	                 snakes, and a snake dies by a single collision. Before,
+//	                 the first collision found in the order of the snakes
+//	                 in the frame killed it.
+//
+// Games stored before versions were recorded are played with RulesetVersion1.
+//
+// A snake eats a single item of a stack of food in every version, older
+// engines removed the whole stack from the square. Replays of games played
+// by those engines differ from the stored frames once a snake eats from a
+// stack.
+const (
+	RulesetVersion1 = "1"
+	RulesetVersion2 = "2"
+	RulesetVersion3 = "3"
+	RulesetVersion4 = "4"
+	RulesetVersion5 = "5"
+
+	// CurrentRulesetVersion is the version new games are created with.
+	CurrentRulesetVersion = RulesetVersion5
+)
+
+// HeadToHeadOutcome decides what happens when two snakes of equal length move
+// their heads onto the same square.
+type HeadToHeadOutcome string
+
+const (
+	// HeadToHeadBothDie kills both snakes, the longer snake wins any other
+	// head-to-head. This is the standard outcome.
+	HeadToHeadBothDie HeadToHeadOutcome = "both-die"
+	// HeadToHeadBothSurvive keeps both snakes alive in every head-to-head,
+	// regardless of length.
+	HeadToHeadBothSurvive HeadToHeadOutcome = "both-survive"
+	// HeadToHeadLongerOrDraw lets the longer snake win, while snakes of equal
+	// length draw and both survive.
+	HeadToHeadLongerOrDraw HeadToHeadOutcome = "longer-or-draw"
+)
+
+// StandardRuleset returns the ruleset used for a standard game.
+func StandardRuleset() *pb.Ruleset {
+	return &pb.Ruleset{
+		Name:            RulesetStandard,
+		MaxHealth:       DefaultMaxHealth,
+		StartingHealth:  DefaultMaxHealth,
+		EqualHeadToHead: string(HeadToHeadBothDie),
+
+		StartingDirection: string(StartingDirectionTowardCenter),
+	}
+}
+
+// newRuleset returns the ruleset a game should be created with. Anything not
+// set on the requested ruleset is taken from the standard ruleset.
+func newRuleset(requested *pb.Ruleset) *pb.Ruleset {
+	if requested == nil {
+		return StandardRuleset()
+	}
+	ruleset := proto.Clone(requested).(*pb.Ruleset)
+	if ruleset.Name == "" {
+		ruleset.Name = RulesetStandard
+	}
+	if ruleset.MaxHealth <= 0 {
+		ruleset.MaxHealth = DefaultMaxHealth
+	}
+	if ruleset.StartingHealth <= 0 {
+		ruleset.StartingHealth = ruleset.MaxHealth
+	}
+	if ruleset.EqualHeadToHead == "" {
+		ruleset.EqualHeadToHead = string(HeadToHeadBothDie)
+	}
+	if ruleset.StartingDirection == "" {
+		ruleset.StartingDirection = string(StartingDirectionTowardCenter)
+	}
+	return ruleset
+}
+
+// gameRuleset returns the ruleset of a game. Games stored before rulesets were
+// introduced have none and are run with the standard ruleset. Games stored
+// before the mode and snake timeout moved onto the ruleset keep them on the
+// game. Options that did not exist yet in the ruleset version of the game are
+// reset to how that version behaved.
+func gameRuleset(game *pb.Game) *pb.Ruleset {
+	ruleset := newRuleset(game.GetRuleset())
+	if ruleset.Mode == "" {
+		ruleset.Mode = game.GetMode()
+	}
+	if ruleset.SnakeTimeout == 0 {
+		ruleset.SnakeTimeout = game.GetSnakeTimeout()
+	}
+	if gameRulesetVersion(game) == RulesetVersion1 {
+		ruleset.EqualHeadToHead = string(HeadToHeadBothDie)
+	}
+	return ruleset
+}
+
+// tailsMove reports whether tails of snakes that do not eat leave their square
+// before collisions are checked, see RulesetVersion3.
+func tailsMove(game *pb.Game) bool {
+	switch gameRulesetVersion(game) {
+	case RulesetVersion1, RulesetVersion2:
+		return false
+	}
+	return true
+}
+
+// snakesByID reports whether a tick processes snakes in order of their ID,
+// see RulesetVersion4.
+func snakesByID(game *pb.Game) bool {
+	switch gameRulesetVersion(game) {
+	case RulesetVersion1, RulesetVersion2, RulesetVersion3:
+		return false
+	}
+	return true
+}
+
+// collisionsResolved reports whether collisions are resolved independent of
+// the order of the snakes, see RulesetVersion5.
+func collisionsResolved(game *pb.Game) bool {
+	switch gameRulesetVersion(game) {
+	case RulesetVersion1, RulesetVersion2, RulesetVersion3, RulesetVersion4:
+		return false
+	}
+	return true
+}
+
+// gameRulesetVersion returns the ruleset version a game is played with.
+func gameRulesetVersion(game *pb.Game) string {
+	if game.GetRulesetVersion() == "" {
+		return RulesetVersion1
+	}
+	return game.GetRulesetVersion()
+}
+
+// ValidateRulesetVersion checks that the engine knows the ruleset version of
+// the game. A game with a newer version than the engine knows about can not
+// be played or replayed correctly.
+func ValidateRulesetVersion(game *pb.Game) error {
+	switch gameRulesetVersion(game) {
+	case RulesetVersion1, RulesetVersion2, RulesetVersion3, RulesetVersion4, RulesetVersion5:
+		return nil
+	}
+	return fmt.Errorf("%w %q", ErrUnknownRulesetVersion, game.GetRulesetVersion())
+}
@@ -0,0 +1,69 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRulesetDefaultsToStandard(t *testing.T) {
+	require.Equal(t, StandardRuleset(), newRuleset(nil))
+	require.Equal(t, StandardRuleset(), newRuleset(&pb.Ruleset{}))
+}
+
+func TestNewRulesetKeepsRequestedValues(t *testing.T) {
+	requested := &pb.Ruleset{
+		Name:            "custom",
+		MaxHealth:       50,
+		StartingHealth:  25,
+		EqualHeadToHead: string(HeadToHeadLongerOrDraw),
+
+		StartingDirection: string(StartingDirectionAwayFromWall),
+	}
+	ruleset := newRuleset(requested)
+	require.Equal(t, requested, ruleset)
+
+	// The requested ruleset is not shared with the game.
+	ruleset.MaxHealth = 10
+	require.Equal(t, int32(50), requested.MaxHealth)
+}
+
+func TestNewRulesetStartingHealthDefaultsToMaxHealth(t *testing.T) {
+	ruleset := newRuleset(&pb.Ruleset{MaxHealth: 50})
+	require.Equal(t, int32(50), ruleset.StartingHealth)
+}
+
+func TestGameRulesetWithoutRuleset(t *testing.T) {
+	require.Equal(t, StandardRuleset(), gameRuleset(&pb.Game{}))
+}
+
+func TestGameRulesetModeAndTimeoutFromGame(t *testing.T) {
+	// Games stored before the fields moved onto the ruleset keep them on the
+	// game.
+	game := &pb.Game{Mode: string(GameModeSinglePlayer), SnakeTimeout: 250}
+	ruleset := gameRuleset(game)
+	require.Equal(t, string(GameModeSinglePlayer), ruleset.Mode)
+	require.Equal(t, int32(250), ruleset.SnakeTimeout)
+
+	game.Ruleset = &pb.Ruleset{Mode: string(GameModeMultiPlayer), SnakeTimeout: 500}
+	require.Equal(t, GameModeMultiPlayer, GameModeOf(game))
+	require.Equal(t, int32(500), gameRuleset(game).SnakeTimeout)
+}
+
+func TestGameRulesetVersion1IgnoresEqualHeadToHead(t *testing.T) {
+	game := &pb.Game{Ruleset: &pb.Ruleset{EqualHeadToHead: string(HeadToHeadBothSurvive)}}
+	require.Equal(t, string(HeadToHeadBothDie), gameRuleset(game).EqualHeadToHead)
+
+	game.RulesetVersion = RulesetVersion2
+	require.Equal(t, string(HeadToHeadBothSurvive), gameRuleset(game).EqualHeadToHead)
+}
+
+func TestValidateRulesetVersion(t *testing.T) {
+	require.NoError(t, ValidateRulesetVersion(&pb.Game{}))
+	require.NoError(t, ValidateRulesetVersion(&pb.Game{RulesetVersion: CurrentRulesetVersion}))
+
+	err := ValidateRulesetVersion(&pb.Game{RulesetVersion: "99"})
+	require.True(t, errors.Is(err, ErrUnknownRulesetVersion))
+}
@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"context"
+	"errors"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+// MoveScript holds the moves of a simulated game by snake ID. The move at
+// index i is made on the turn i+1 after the start frame. A snake without a move
+// for a turn makes its default move.
+type MoveScript map[string][]string
+
+// RuleConfig is a set of rules to simulate a game with. An empty
+// RulesetVersion keeps the version of the game.
+type RuleConfig struct {
+	Ruleset        *pb.Ruleset
+	RulesetVersion string
+}
+
+var errNoScriptedMove = errors.New("rules: no scripted move")
+
+// RunTwice plays the same moves from startFrame under two rule configs and
+// returns the frames of both runs, starting with startFrame, together with the
+// first turn where they diverge or -1 when they are the same. See
+// CompareGames for how runs of different length compare.
+//
+// Food is placed from the seed of the game as in GameTick, a game without a
+// seed is simulated with seed 1 so both runs get the same food. A run ends
+// when the game is over, when the script has no moves left for any alive
+// snake or when a turn can not be advanced.
+func RunTwice(game *pb.Game, startFrame *pb.GameFrame, moves MoveScript, cfgA, cfgB RuleConfig) (framesA, framesB []*pb.GameFrame, divergeTurn int) {
+	framesA = simulate(game, startFrame, moves, cfgA)
+	framesB = simulate(game, startFrame, moves, cfgB)
+	divergeTurn, _ = CompareGames(framesA, framesB)
+	return framesA, framesB, divergeTurn
+}
+
+// simulate plays moves from startFrame under cfg without asking any snakes.
+// Neither game nor startFrame are changed.
+func simulate(game *pb.Game, startFrame *pb.GameFrame, moves MoveScript, cfg RuleConfig) []*pb.GameFrame {
+	// The mode follows from the snakes of the game, not from the rules.
+	mode := GameModeOf(game)
+	game = proto.Clone(game).(*pb.Game)
+	game.Ruleset = cfg.Ruleset
+	if cfg.RulesetVersion != "" {
+		game.RulesetVersion = cfg.RulesetVersion
+	}
+	if game.Seed == 0 {
+		game.Seed = 1
+	}
+	ruleset := gameRuleset(game)
+
+	frame := proto.Clone(startFrame).(*pb.GameFrame)
+	frames := []*pb.GameFrame{proto.Clone(frame).(*pb.GameFrame)}
+	for i := 0; !CheckForGameOver(mode, frame); i++ {
+		updates, ok := scriptedMoves(frame, moves, i)
+		if !ok {
+			break
+		}
+		placer := withObstacles(foodPlacerForTurn(ruleset, game.Seed, frame.Turn+1, frame.Hazards, nil), game.Obstacles)
+		next, err := advanceFrame(context.Background(), game, frame, updates, placer)
+		if err != nil {
+			break
+		}
+		// Later turns update the snakes in place, the stored frame is a copy.
+		frames = append(frames, proto.Clone(next).(*pb.GameFrame))
+		frame = next
+	}
+	return frames
+}
+
+// scriptedMoves returns the moves of the alive snakes for the i-th turn of the
+// script, and false when the script has no moves left for any of them.
+func scriptedMoves(frame *pb.GameFrame, moves MoveScript, i int) ([]*SnakeUpdate, bool) {
+	updates := []*SnakeUpdate{}
+	scripted := false
+	for _, s := range frame.AliveSnakes() {
+		if i < len(moves[s.ID]) {
+			updates = append(updates, &SnakeUpdate{Snake: s, Move: moves[s.ID][i]})
+			scripted = true
+			continue
+		}
+		updates = append(updates, &SnakeUpdate{Snake: s, Err: errNoScriptedMove})
+	}
+	return updates, scripted
+}
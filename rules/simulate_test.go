@@ -0,0 +1,75 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func simulateStart() (*pb.Game, *pb.GameFrame) {
+	game := &pb.Game{Width: 6, Height: 6, RulesetVersion: RulesetVersion3}
+	frame := &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 100, Body: []*pb.Point{{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}}},
+			{ID: "2", Health: 100, Body: []*pb.Point{{X: 4, Y: 5}, {X: 4, Y: 5}, {X: 4, Y: 5}}},
+		},
+	}
+	return game, frame
+}
+
+func TestRunTwiceDiverges(t *testing.T) {
+	game, frame := simulateStart()
+	start := proto.Clone(frame).(*pb.GameFrame)
+	moves := MoveScript{
+		"1": {"left", "left", "left"},
+		"2": {"up", "up", "up"},
+	}
+
+	framesA, framesB, turn := RunTwice(game, frame, moves, RuleConfig{}, RuleConfig{Ruleset: &pb.Ruleset{WrapHorizontal: true}})
+	// Snake 1 leaves the board on turn 2, which ends the game without
+	// wrapping.
+	require.Equal(t, 2, turn)
+	require.Len(t, framesA, 3)
+	require.Len(t, framesB, 4)
+	require.Equal(t, DeathCauseWallCollision, framesA[2].Snakes[0].Death.Cause)
+	require.Nil(t, framesB[2].Snakes[0].Death)
+	require.Equal(t, &pb.Point{X: 5, Y: 2}, framesB[2].Snakes[0].Head())
+	require.True(t, framesA[1].Equal(framesB[1]))
+
+	// The inputs are left alone.
+	require.True(t, start.Equal(frame))
+	require.Equal(t, "", game.Ruleset.GetEqualHeadToHead())
+}
+
+func TestRunTwiceSame(t *testing.T) {
+	game, frame := simulateStart()
+	moves := MoveScript{
+		"1": {"up", "up"},
+		"2": {"left"},
+	}
+
+	cfg := RuleConfig{Ruleset: &pb.Ruleset{SquadWipe: true}}
+	framesA, framesB, turn := RunTwice(game, frame, moves, RuleConfig{}, cfg)
+	require.Equal(t, -1, turn)
+	// The run ends when the script does, snake 2 made its default move on
+	// turn 2.
+	require.Len(t, framesA, 3)
+	require.Len(t, framesB, 3)
+	require.Equal(t, &pb.Point{X: 2, Y: 5}, framesA[2].Snakes[1].Head())
+}
+
+func TestRunTwiceRulesetVersion(t *testing.T) {
+	game, frame := simulateStart()
+	// Snake 1 chases its own tail, which is only allowed once tails move
+	// before collisions are checked.
+	frame.Snakes[0].Body = []*pb.Point{{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 1}, {X: 1, Y: 1}}
+	moves := MoveScript{
+		"1": {"up"},
+		"2": {"up"},
+	}
+
+	_, _, turn := RunTwice(game, frame, moves, RuleConfig{}, RuleConfig{RulesetVersion: RulesetVersion2})
+	require.Equal(t, 1, turn)
+}
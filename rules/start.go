@@ -45,7 +45,8 @@ func getEffectiveColor(meta SnakeMetadata) string {
 }
 
 // NotifyGameStart calls /start on every snake and then adds metadata from the
-// response to the pb.Snake object.
+// response to the pb.Snake object. Snakes that could not be reached have the
+// failure counted towards their consecutive failures.
 func NotifyGameStart(game *pb.Game, startState *pb.GameFrame) {
 	// Be nice and give snake servers a long time to respond to /start in case
 	// it's a sleeping heroku dyno or something like that.
@@ -54,6 +55,7 @@ func NotifyGameStart(game *pb.Game, startState *pb.GameFrame) {
 
 	for _, resp := range responses {
 		resp.Snake.Color = getEffectiveColor(resp)
+		trackFailures(resp.Snake, resp.Err, false)
 	}
 }
 
@@ -35,6 +35,7 @@ func TestStartSnakesMissingServer(t *testing.T) {
 	snake := getSnakeAfterMissingServer(t)
 	require.Equal(t, "red", snake.Color)
 	require.Nil(t, snake.Death, "Snake should not be dead")
+	require.Equal(t, int32(1), snake.ConsecutiveFailures)
 }
 
 func getSnakeAfterStart(t *testing.T, json string, statusCode int) *pb.Snake {
@@ -12,4 +12,37 @@ var (
 	GameStatusError GameStatus = "error"
 	// GameStatusComplete represents a game that is done
 	GameStatusComplete GameStatus = "complete"
+	// GameStatusPaused represents a running game that workers should leave
+	// alone until it is resumed
+	GameStatusPaused GameStatus = "paused"
 )
+
+// statusTransitions lists the statuses a game may move to from each status.
+// Complete and errored games are final.
+var statusTransitions = map[GameStatus][]GameStatus{
+	GameStatusStopped:  {GameStatusRunning, GameStatusComplete, GameStatusError},
+	GameStatusRunning:  {GameStatusPaused, GameStatusStopped, GameStatusComplete, GameStatusError},
+	GameStatusPaused:   {GameStatusRunning, GameStatusStopped, GameStatusComplete, GameStatusError},
+	GameStatusComplete: {},
+	GameStatusError:    {},
+}
+
+// CanTransition reports whether a game may move from one status to another.
+// Setting a game to the status it already has is always allowed, and so is
+// moving away from a status that is not known, such as the empty status of a
+// game that has never been given one.
+func CanTransition(from, to GameStatus) bool {
+	if from == to {
+		return true
+	}
+	allowed, ok := statusTransitions[from]
+	if !ok {
+		return true
+	}
+	for _, s := range allowed {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		From     GameStatus
+		To       GameStatus
+		Expected bool
+	}{
+		{From: GameStatusStopped, To: GameStatusRunning, Expected: true},
+		{From: GameStatusStopped, To: GameStatusPaused, Expected: false},
+		{From: GameStatusRunning, To: GameStatusPaused, Expected: true},
+		{From: GameStatusRunning, To: GameStatusComplete, Expected: true},
+		{From: GameStatusPaused, To: GameStatusRunning, Expected: true},
+		{From: GameStatusPaused, To: GameStatusPaused, Expected: true},
+		{From: GameStatusComplete, To: GameStatusRunning, Expected: false},
+		{From: GameStatusComplete, To: GameStatusPaused, Expected: false},
+		{From: GameStatusError, To: GameStatusRunning, Expected: false},
+		{From: "", To: GameStatusRunning, Expected: true},
+	}
+
+	for _, test := range tests {
+		actual := CanTransition(test.From, test.To)
+		require.Equal(t, test.Expected, actual, "%s -> %s", test.From, test.To)
+	}
+}
@@ -1,35 +1,159 @@
 package rules
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
 	log "github.com/sirupsen/logrus"
 )
 
-// GameTick runs the game one tick and updates the state
-func GameTick(game *pb.Game, lastFrame *pb.GameFrame) (*pb.GameFrame, error) {
+// MaxTickProcessing is how long a tick may spend on rule processing on top of
+// the snake timeout before GameTick gives up on it.
+var MaxTickProcessing = 5 * time.Second
+
+// TickOption configures a single call to GameTick.
+type TickOption func(*tickOptions)
+
+type tickOptions struct {
+	foodPlacer FoodPlacer
+}
+
+// WithFoodPlacer makes the tick place new food with placer instead of at
+// random, e.g. with a ScriptedFoodPlacer to reproduce a game. It only applies
+// to the tick it is passed to, pass the same placer to every tick of a game to
+// script all of its food. The ruleset still decides when and how much food
+// spawns, and where it goes relative to hazards and obstacles.
+func WithFoodPlacer(placer FoodPlacer) TickOption {
+	return func(o *tickOptions) {
+		o.foodPlacer = placer
+	}
+}
+
+type tickResult struct {
+	frame *pb.GameFrame
+	err   error
+}
+
+// GameTick runs the game one tick and returns the next frame, game and
+// lastFrame are not changed. The tick must finish within the snake timeout
+// plus its grace and MaxTickProcessing, or before the deadline of ctx
+// if that is sooner, otherwise ErrTickTimeout is returned so a runaway tick
+// does not block the worker forever. The tick checks its context between
+// phases and stops as soon as it can once it is given up on. Games with a
+// ruleset version the engine does not know are not run.
+func GameTick(ctx context.Context, game *pb.Game, lastFrame *pb.GameFrame, opts ...TickOption) (*pb.GameFrame, error) {
 	if lastFrame == nil {
-		return nil, fmt.Errorf("rules: invalid state, previous frame is nil")
+		return nil, ErrNilFrame
 	}
-	nextFrame := &pb.GameFrame{
-		Turn:   lastFrame.Turn + 1,
-		Snakes: lastFrame.Snakes,
-		Food:   lastFrame.Food,
+	if err := ValidateRulesetVersion(game); err != nil {
+		return nil, err
+	}
+	o := &tickOptions{}
+	for _, opt := range opts {
+		opt(o)
 	}
-	duration := time.Duration(game.SnakeTimeout) * time.Millisecond
+	timeout := moveTimeout(gameRuleset(game)) + MaxTickProcessing
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The tick runs on copies, a tick that is given up on may still be running
+	// and must not touch the game or frame of the caller.
+	game = proto.Clone(game).(*pb.Game)
+	lastFrame = proto.Clone(lastFrame).(*pb.GameFrame)
+	done := make(chan tickResult, 1)
+	go func() {
+		frame, err := gameTick(ctx, game, lastFrame, o)
+		done <- tickResult{frame: frame, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.frame, r.err
+	case <-ctx.Done():
+		return nil, tickTimeout(lastFrame.Turn+1, ctx.Err())
+	}
+}
+
+// moveTimeout returns how long a tick waits for the moves of the snakes. The
+// grace on top of the snake timeout makes up for the time requests spend in
+// transit, so a snake that answers just after its timeout because of network
+// jitter still gets its move in.
+func moveTimeout(ruleset *pb.Ruleset) time.Duration {
+	return time.Duration(ruleset.SnakeTimeout+ruleset.SnakeTimeoutGrace) * time.Millisecond
+}
+
+// tickTimeout returns the error of a tick for turn that was given up on
+// because of err, the error of its context.
+func tickTimeout(turn int32, err error) error {
+	return fmt.Errorf("%w: turn %d: %v", ErrTickTimeout, turn, err)
+}
+
+func gameTick(ctx context.Context, game *pb.Game, lastFrame *pb.GameFrame, o *tickOptions) (*pb.GameFrame, error) {
+	ruleset := gameRuleset(game)
+	duration := moveTimeout(ruleset)
 	log.WithFields(log.Fields{
 		"GameID":  game.ID,
-		"Turn":    nextFrame.Turn,
+		"Turn":    lastFrame.Turn + 1,
 		"Timeout": duration,
 	}).Info("GatherSnakeMoves")
-	moves := GatherSnakeMoves(duration, game, lastFrame)
+	moves := gatherSnakeMoves(ctx, duration, game, lastFrame, movesDecideGame(game, lastFrame, ruleset))
+	summary := SummarizeMoves(moves)
+	log.WithFields(log.Fields{
+		"GameID":    game.ID,
+		"Turn":      lastFrame.Turn + 1,
+		"Succeeded": summary.Succeeded,
+		"Failed":    summary.Failed,
+		"TimedOut":  summary.TimedOut,
+		"Cancelled": summary.Cancelled,
+		"MaxRTT":    summary.MaxLatency,
+	}).Info("gathered snake moves")
+
+	placer := withObstacles(foodPlacerForTurn(ruleset, game.Seed, lastFrame.Turn+1, lastFrame.Hazards, o.foodPlacer), game.Obstacles)
+	return advanceFrame(ctx, game, lastFrame, moves, placer)
+}
+
+// advanceFrame applies the snake moves to lastFrame and returns the next frame.
+// The snakes of lastFrame are updated in place. Food off the board, which only
+// malformed imported frames have, is dropped from lastFrame with a warning
+// before anything else, so it is not counted as food left on the board. Moves arrive in the order the
+// snakes answered, they are applied by snake ID, and the snakes are processed
+// in the order of tickOrder, so the same input always gives the same frame.
+// ctx is checked between the phases of the tick and before every food item
+// is placed, a tick whose context is done stops with ErrTickTimeout.
+func advanceFrame(ctx context.Context, game *pb.Game, lastFrame *pb.GameFrame, moves []*SnakeUpdate, placer FoodPlacer) (*pb.GameFrame, error) {
+	return advanceFrameRespawning(ctx, game, lastFrame, moves, placer, nil)
+}
+
+// advanceFrameRespawning is advanceFrame with the squares snakes respawn on
+// picked by respawner, see respawnSnakes.
+func advanceFrameRespawning(ctx context.Context, game *pb.Game, lastFrame *pb.GameFrame, moves []*SnakeUpdate, placer, respawner FoodPlacer) (*pb.GameFrame, error) {
+	ruleset := gameRuleset(game)
+	lastFrame.Food = foodOnBoard(lastFrame, game.Width, game.Height)
+	nextFrame := &pb.GameFrame{
+		Turn:    lastFrame.Turn + 1,
+		Snakes:  tickOrder(game, lastFrame.Snakes),
+		Food:    lastFrame.Food,
+		Hazards: lastFrame.Hazards,
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		return moves[i].Snake.ID < moves[j].Snake.ID
+	})
 
 	// we have all the snake moves now
 	// 1. update snake coords
 	updateSnakes(game, nextFrame, moves)
+	// Heads are wrapped before checking for death, so snakes meeting across a
+	// wrapping edge collide like anywhere else on the board.
+	wrapSnakes(nextFrame, game.Width, game.Height, ruleset)
+	flagUnresponsive(nextFrame, ruleset)
+	if err := ctx.Err(); err != nil {
+		return nil, tickTimeout(nextFrame.Turn, err)
+	}
 	// 2. check for death
 	// 	  a - starvation
 	//    b - wall collision
@@ -38,12 +162,30 @@ func GameTick(game *pb.Game, lastFrame *pb.GameFrame) (*pb.GameFrame, error) {
 		"GameID": game.ID,
 		"Turn":   nextFrame.Turn,
 	}).Info("check for death")
-	deathUpdates := checkForDeath(game.Width, game.Height, nextFrame)
+	// Whether a snake eats decides if its tail leaves its square, so it is
+	// known before the collisions are checked. The food is eaten after.
+	var ate map[string]bool
+	if tailsMove(game) {
+		ate = snakesEating(nextFrame, ruleset)
+	}
+	deathUpdates := checkForDeath(game.Width, game.Height, game.Obstacles, nextFrame, ruleset, ate, collisionsResolved(game))
 	for _, du := range deathUpdates {
 		if du.Snake.Death == nil {
 			du.Snake.Death = du.Death
+			event := &pb.Event{
+				Type:    FrameEventDied,
+				SnakeID: du.Snake.ID,
+				Cause:   du.Death.Cause,
+			}
+			if head := du.Snake.Head(); head != nil {
+				event.Point = head.Clone()
+			}
+			nextFrame.Events = append(nextFrame.Events, event)
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, tickTimeout(nextFrame.Turn, err)
+	}
 	// 3. game update
 	//    a - turn incr -- done above when the next tick is created
 	//    b - reduce health points
@@ -55,30 +197,110 @@ func GameTick(game *pb.Game, lastFrame *pb.GameFrame) (*pb.GameFrame, error) {
 		"GameID": game.ID,
 		"Turn":   nextFrame.Turn,
 	}).Info("reduce snake health")
-	for _, s := range nextFrame.AliveSnakes() {
-		s.Health = s.Health - 1
+	if nextFrame.Turn >= ruleset.StarvationStartTurn {
+		for _, s := range nextFrame.AliveSnakes() {
+			s.Health = s.Health - 1
+		}
 	}
+	damageOnHazards(nextFrame, ruleset)
+	healOffHazards(nextFrame, ruleset)
 
 	log.WithFields(log.Fields{
 		"GameID": game.ID,
 		"Turn":   nextFrame.Turn,
 	}).Info("handle food")
 
-	foodToRemove := checkForSnakesEating(nextFrame)
-	nextFood, err := updateFood(game.Width, game.Height, lastFrame, foodToRemove)
+	foodToRemove := checkForSnakesEating(nextFrame, ruleset)
+	spawn := foodToSpawn(game, lastFrame, foodToRemove, ruleset)
+	nextFood, err := updateFood(ctx, game.Width, game.Height, lastFrame, foodToRemove, spawn, placer)
 	if err != nil {
 		return nil, err
 	}
 	nextFrame.Food = nextFood
+	for _, f := range newFood(lastFrame, nextFrame) {
+		nextFrame.Events = append(nextFrame.Events, &pb.Event{
+			Type:  FrameEventFoodSpawned,
+			Point: f.Clone(),
+		})
+	}
+
+	respawnSnakes(game, nextFrame, ruleset, respawner)
+	// The snakes are stored in the order of the frame they came from.
+	nextFrame.Snakes = lastFrame.Snakes
 	return nextFrame, nil
 }
 
-func updateFood(width, height int32, gameFrame *pb.GameFrame, foodToRemove []*pb.Point) ([]*pb.Point, error) {
+// tickOrder returns the snakes in the order a tick processes them: sorted by
+// ID from RulesetVersion4 on, in the order of the frame before.
+func tickOrder(game *pb.Game, snakes []*pb.Snake) []*pb.Snake {
+	if !snakesByID(game) {
+		return snakes
+	}
+	return snakesInIDOrder(snakes)
+}
+
+// foodToSpawn returns how many food items to place this turn. Without a cap
+// every eaten item is replaced. With MaxFoodSpawnPerTurn set the board is
+// refilled towards the food the game started with instead, at most that many
+// items a turn, so food eaten in a burst comes back over several turns.
+// DisableFoodReplacement turns both off. Separately, the board is topped up
+// to MinimumFood whenever fewer items are left, within the same cap.
+func foodToSpawn(game *pb.Game, lastFrame *pb.GameFrame, foodToRemove []*pb.Point, ruleset *pb.Ruleset) int {
+	if ruleset.DisableFood {
+		return 0
+	}
+	left := len(lastFrame.Food) - len(foodToRemove)
+	max := int(ruleset.MaxFoodSpawnPerTurn)
+	spawn := 0
+	if !ruleset.DisableFoodReplacement {
+		spawn = len(foodToRemove)
+		if max > 0 && game.FoodTarget > 0 {
+			spawn = int(game.FoodTarget) - left
+		}
+	}
+	if min := int(ruleset.MinimumFood); left+spawn < min {
+		spawn = min - left
+	}
+	if max > 0 && spawn > max {
+		spawn = max
+	}
+	if spawn < 0 {
+		spawn = 0
+	}
+	return spawn
+}
+
+// foodOnBoard returns the food of frame that is on a board of width by
+// height, food off the board is logged and left out.
+func foodOnBoard(frame *pb.GameFrame, width, height int32) []*pb.Point {
+	food := make([]*pb.Point, 0, len(frame.Food))
+	for _, f := range frame.Food {
+		if deathByOutOfBounds(f, width, height) {
+			log.WithFields(log.Fields{
+				"Turn": frame.Turn,
+				"X":    f.X,
+				"Y":    f.Y,
+			}).Warn("dropping food off the board")
+			continue
+		}
+		food = append(food, f)
+	}
+	return food
+}
+
+// updateFood returns the food of the next frame: the food of gameFrame
+// without the eaten items, plus spawn new items from placer. Placing stops
+// with ErrTickTimeout once ctx is done.
+func updateFood(ctx context.Context, width, height int32, gameFrame *pb.GameFrame, foodToRemove []*pb.Point, spawn int, placer FoodPlacer) ([]*pb.Point, error) {
+	// Only one item is removed for every eaten square, when food is stacked on
+	// a square the other items stay on the board.
 	food := []*pb.Point{}
+	removed := make([]bool, len(foodToRemove))
 	for _, foodPos := range gameFrame.Food {
 		found := false
-		for _, r := range foodToRemove {
-			if foodPos.Equal(r) {
+		for i, r := range foodToRemove {
+			if !removed[i] && foodPos.Equal(r) {
+				removed[i] = true
 				found = true
 				break
 			}
@@ -89,8 +311,13 @@ func updateFood(width, height int32, gameFrame *pb.GameFrame, foodToRemove []*pb
 		}
 	}
 
-	for range foodToRemove {
-		p := getUnoccupiedPoint(width, height, gameFrame.Food, gameFrame.AliveSnakes())
+	// Food placed this turn is passed on as occupied, so several items spawned
+	// in one turn never share a square.
+	for i := 0; i < spawn; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, tickTimeout(gameFrame.Turn+1, err)
+		}
+		p := placer.PlaceFood(width, height, food, gameFrame.AliveSnakes())
 		if p != nil {
 			food = append(food, p)
 		}
@@ -99,18 +326,58 @@ func updateFood(width, height int32, gameFrame *pb.GameFrame, foodToRemove []*pb
 	return food, nil
 }
 
-func getUnoccupiedPoint(width, height int32, food []*pb.Point, snakes []*pb.Snake) *pb.Point {
+// getUnoccupiedPoint returns a random unoccupied point, drawn from r or from
+// the global source when r is nil. The points are drawn from in a fixed order,
+// so the same r gives the same point on the same board.
+func getUnoccupiedPoint(r *rand.Rand, width, height int32, food []*pb.Point, snakes []*pb.Snake) *pb.Point {
 	openPoints := getUnoccupiedPoints(width, height, food, snakes)
 
 	if len(openPoints) == 0 {
 		return nil
 	}
 
-	randIndex := rand.Intn(len(openPoints))
+	randIndex := randIntn(r, len(openPoints))
 
 	return openPoints[randIndex]
 }
 
+func getUnoccupiedPointIn(r *rand.Rand, candidates []*pb.Point, width, height int32, food []*pb.Point, snakes []*pb.Snake) *pb.Point {
+	occupiedPoints := getUniqOccupiedPoints(food, snakes)
+	openPoints := []*pb.Point{}
+	for _, p := range candidates {
+		if p.X < 0 || p.X >= width || p.Y < 0 || p.Y >= height {
+			continue
+		}
+		if !containsPoint(occupiedPoints, p) && !containsPoint(openPoints, p) {
+			openPoints = append(openPoints, p)
+		}
+	}
+
+	if len(openPoints) == 0 {
+		return nil
+	}
+	sortPoints(openPoints)
+	return openPoints[randIntn(r, len(openPoints))].Clone()
+}
+
+// sortPoints orders points by X, then Y, the order getUnoccupiedPoints lists
+// the board in.
+func sortPoints(points []*pb.Point) {
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].X != points[j].X {
+			return points[i].X < points[j].X
+		}
+		return points[i].Y < points[j].Y
+	})
+}
+
+func randIntn(r *rand.Rand, n int) int {
+	if r == nil {
+		return rand.Intn(n)
+	}
+	return r.Intn(n)
+}
+
 func getUnoccupiedPoints(width, height int32, food []*pb.Point, snakes []*pb.Snake) []*pb.Point {
 	occupiedPoints := getUniqOccupiedPoints(food, snakes)
 
@@ -195,23 +462,86 @@ func updateSnakes(game *pb.Game, frame *pb.GameFrame, moves []*SnakeUpdate) {
 	}
 }
 
-func checkForSnakesEating(frame *pb.GameFrame) []*pb.Point {
+// snakesEating returns the IDs of the alive snakes that grow this turn: their
+// head is on food they can eat, see edibleFood, and they are shorter than
+// Ruleset.MaxSnakeLength. The tails of these snakes stay on their square, the
+// tail of a snake that eats at the cap moves like any other.
+func snakesEating(frame *pb.GameFrame, ruleset *pb.Ruleset) map[string]bool {
+	ate := map[string]bool{}
+	food := edibleFood(frame, ruleset)
+	for _, snake := range frame.AliveSnakes() {
+		if eatenFood(snake, food) != nil && !atMaxLength(snake, ruleset) {
+			ate[snake.ID] = true
+		}
+	}
+	return ate
+}
+
+// edibleFood returns the food of frame snakes can eat, none when the ruleset
+// disables food.
+func edibleFood(frame *pb.GameFrame, ruleset *pb.Ruleset) []*pb.Point {
+	if ruleset.DisableFood {
+		return nil
+	}
+	return frame.Food
+}
+
+// atMaxLength reports whether snake, which has moved its head but not yet lost
+// its tail, would grow past Ruleset.MaxSnakeLength by keeping its tail.
+func atMaxLength(snake *pb.Snake, ruleset *pb.Ruleset) bool {
+	max := ruleset.GetMaxSnakeLength()
+	return max > 0 && int32(len(snake.Body)) > max
+}
+
+// eatenFood returns the food under the head of snake, or nil. A head eats a
+// single item, even when food is stacked.
+func eatenFood(snake *pb.Snake, food []*pb.Point) *pb.Point {
+	head := snake.Head()
+	if head == nil {
+		return nil
+	}
+	for _, foodPos := range food {
+		if head.Equal(foodPos) {
+			return foodPos
+		}
+	}
+	return nil
+}
+
+// checkForSnakesEating restores the health of snakes that ate and shrinks the
+// ones that didn't. Snakes are handled in the order of AliveSnakes, so the
+// updates are applied in the same sequence every time a frame is replayed.
+// When snakes share a food square they all eat, but the food is only removed
+// once. A snake eats at most one item per tick, when several items are stacked
+// on its square one is eaten and the rest are left for later turns. Every snake
+// that ate is recorded as an event on the frame.
+//
+// Snakes do not grow past Ruleset.MaxSnakeLength: a snake that eats at the cap
+// regains its health but loses its tail like a snake that did not eat.
+func checkForSnakesEating(frame *pb.GameFrame, ruleset *pb.Ruleset) []*pb.Point {
 	foodToRemove := []*pb.Point{}
+	food := edibleFood(frame, ruleset)
 	for _, snake := range frame.AliveSnakes() {
-		ate := false
-		for _, foodPos := range frame.Food {
-			if snake.Head().Equal(foodPos) {
-				snake.Health = 100
-				ate = true
-				foodToRemove = append(foodToRemove, foodPos)
-			}
+		if snake.Head() == nil {
+			continue
 		}
-		if !ate {
-			if len(snake.Body) == 0 {
-				continue
-			}
+		foodPos := eatenFood(snake, food)
+		if foodPos == nil {
 			snake.Body = snake.Body[:len(snake.Body)-1]
+			continue
+		}
+		snake.Health = ruleset.MaxHealth
+		if atMaxLength(snake, ruleset) {
+			snake.Body = snake.Body[:len(snake.Body)-1]
+		}
+		if !containsPoint(foodToRemove, foodPos) {
+			foodToRemove = append(foodToRemove, foodPos)
 		}
+		frame.Events = append(frame.Events, &pb.Event{
+			Type:    FrameEventAteFood,
+			SnakeID: snake.ID,
+			Point:   snake.Head().Clone(),
+		})
 	}
 	return foodToRemove
 }
@@ -1,15 +1,20 @@
 package rules
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 )
 
 func TestUpdateFood(t *testing.T) {
-	updated, err := updateFood(20, 20, &pb.GameFrame{
+	updated, err := updateFood(context.Background(), 20, 20, &pb.GameFrame{
 		Food: []*pb.Point{
 			{X: 1, Y: 1},
 			{X: 1, Y: 2},
@@ -25,7 +30,7 @@ func TestUpdateFood(t *testing.T) {
 		},
 	}, []*pb.Point{
 		{X: 1, Y: 2},
-	})
+	}, 1, RandomFoodPlacer{})
 	require.NoError(t, err)
 	require.Len(t, updated, 2)
 	require.True(t, updated[0].Equal(&pb.Point{X: 1, Y: 1}))
@@ -35,8 +40,33 @@ func TestUpdateFood(t *testing.T) {
 	require.False(t, updated[1].Equal(&pb.Point{X: 1, Y: 1}))
 }
 
+func TestAdvanceFrameDropsFoodOffBoard(t *testing.T) {
+	game := &pb.Game{Width: 5, Height: 5, Ruleset: &pb.Ruleset{DisableFood: true}}
+	snake := &pb.Snake{ID: "1", Health: 100, Body: []*pb.Point{{X: 2, Y: 2}, {X: 2, Y: 3}}}
+	frame := &pb.GameFrame{
+		Food:   []*pb.Point{{X: 1, Y: 1}, {X: 5, Y: 2}, {X: -1, Y: 0}, {X: 4, Y: 4}},
+		Snakes: []*pb.Snake{snake},
+	}
+	next, err := advanceFrame(context.Background(), game, frame, []*SnakeUpdate{{Snake: snake, Move: "up"}}, noFoodPlacer{})
+	require.NoError(t, err)
+	require.Equal(t, []*pb.Point{{X: 1, Y: 1}, {X: 4, Y: 4}}, next.Food)
+}
+
+func TestAdvanceFrameOffBoardFoodIsNotCountedForMinimumFood(t *testing.T) {
+	game := &pb.Game{Width: 5, Height: 5, Ruleset: &pb.Ruleset{MinimumFood: 2, DisableFoodReplacement: true}}
+	snake := &pb.Snake{ID: "1", Health: 100, Body: []*pb.Point{{X: 2, Y: 2}, {X: 2, Y: 3}}}
+	frame := &pb.GameFrame{
+		Food:   []*pb.Point{{X: 1, Y: 1}, {X: 5, Y: 2}, {X: -1, Y: 0}},
+		Snakes: []*pb.Snake{snake},
+	}
+	placer := NewScriptedFoodPlacer([]*pb.Point{{X: 4, Y: 4}})
+	next, err := advanceFrame(context.Background(), game, frame, []*SnakeUpdate{{Snake: snake, Move: "up"}}, placer)
+	require.NoError(t, err)
+	require.Equal(t, []*pb.Point{{X: 1, Y: 1}, {X: 4, Y: 4}}, next.Food)
+}
+
 func TestUpdateFoodWithFullBoard(t *testing.T) {
-	updated, err := updateFood(2, 2, &pb.GameFrame{
+	updated, err := updateFood(context.Background(), 2, 2, &pb.GameFrame{
 		Food: []*pb.Point{
 			{X: 0, Y: 0},
 		},
@@ -52,13 +82,13 @@ func TestUpdateFoodWithFullBoard(t *testing.T) {
 		},
 	}, []*pb.Point{
 		{X: 0, Y: 0},
-	})
+	}, 1, RandomFoodPlacer{})
 	require.NoError(t, err)
 	require.Len(t, updated, 0)
 }
 
 func TestGetUnoccupiedPointWithFullBoard(t *testing.T) {
-	unoccupiedPoint := getUnoccupiedPoint(2, 2,
+	unoccupiedPoint := getUnoccupiedPoint(nil, 2, 2,
 		[]*pb.Point{{X: 0, Y: 0}},
 		[]*pb.Snake{
 			{
@@ -108,7 +138,7 @@ func TestGetUniqOccupiedPoints(t *testing.T) {
 }
 
 func TestGameTickUpdatesTurnCounter(t *testing.T) {
-	gt, err := GameTick(commonGame, &pb.GameFrame{Turn: 5})
+	gt, err := GameTick(context.Background(), commonGame, &pb.GameFrame{Turn: 5})
 	require.NoError(t, err)
 	require.Equal(t, int32(6), gt.Turn)
 }
@@ -126,7 +156,7 @@ func TestGameTickUpdatesSnake(t *testing.T) {
 		Width:  20,
 		Height: 20,
 	}
-	gt, err := GameTick(game, &pb.GameFrame{
+	gt, err := GameTick(context.Background(), game, &pb.GameFrame{
 		Turn: 5,
 		Snakes: []*pb.Snake{
 			snake,
@@ -166,7 +196,7 @@ func TestGameFrameSnakeEats(t *testing.T) {
 
 	lastFrame.Snakes = []*pb.Snake{snake}
 
-	gt, err := GameTick(commonGame, lastFrame)
+	gt, err := GameTick(context.Background(), commonGame, lastFrame)
 	require.NoError(t, err)
 	require.Len(t, gt.Snakes, 1)
 	snake = gt.Snakes[0]
@@ -174,6 +204,30 @@ func TestGameFrameSnakeEats(t *testing.T) {
 	require.Len(t, snake.Body, 4)
 }
 
+func TestGameFrameSnakeEatsRestoresRulesetMaxHealth(t *testing.T) {
+	snake := &pb.Snake{
+		Health: 20,
+		Body: []*pb.Point{
+			{X: 1, Y: 1},
+			{X: 1, Y: 2},
+			{X: 1, Y: 3},
+		},
+	}
+	game := &pb.Game{
+		Width:   20,
+		Height:  20,
+		Ruleset: &pb.Ruleset{MaxHealth: 50},
+	}
+
+	gt, err := GameTick(context.Background(), game, &pb.GameFrame{
+		Turn:   5,
+		Snakes: []*pb.Snake{snake},
+		Food:   []*pb.Point{{X: 1, Y: 0}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(50), gt.Snakes[0].Health)
+}
+
 func TestGameTickDeadSnakeDoNotUpdate(t *testing.T) {
 	snake := &pb.Snake{
 		Health: 87,
@@ -190,7 +244,7 @@ func TestGameTickDeadSnakeDoNotUpdate(t *testing.T) {
 
 	lastFrame.Snakes = []*pb.Snake{snake}
 
-	gt, err := GameTick(commonGame, lastFrame)
+	gt, err := GameTick(context.Background(), commonGame, lastFrame)
 	require.NoError(t, err)
 	require.Len(t, gt.Snakes, 1)
 	snake = gt.Snakes[0]
@@ -213,11 +267,32 @@ func TestGameTickUpdatesDeath(t *testing.T) {
 
 	lastFrame.Snakes = []*pb.Snake{snake}
 
-	gt, err := GameTick(commonGame, lastFrame)
+	gt, err := GameTick(context.Background(), commonGame, lastFrame)
 	require.NoError(t, err)
 	require.NotNil(t, gt.Snakes[0].Death)
 }
 
+func TestGameTickEmptyBody(t *testing.T) {
+	empty := &pb.Snake{ID: "empty", Health: 50}
+	other := &pb.Snake{
+		ID:     "other",
+		Health: 50,
+		Body: []*pb.Point{
+			{X: 1, Y: 1},
+			{X: 1, Y: 2},
+			{X: 1, Y: 3},
+		},
+	}
+
+	lastFrame.Snakes = []*pb.Snake{empty, other}
+
+	gt, err := GameTick(context.Background(), commonGame, lastFrame)
+	require.NoError(t, err)
+	require.NotNil(t, gt.Snakes[0].Death)
+	require.Equal(t, DeathCauseNoBody, gt.Snakes[0].Death.Cause)
+	require.Nil(t, gt.Snakes[1].Death)
+}
+
 func TestUpdateSnakes(t *testing.T) {
 	snake := &pb.Snake{
 		Body: []*pb.Point{
@@ -246,3 +321,473 @@ func TestUpdateSnakes(t *testing.T) {
 	}, moves)
 	require.Equal(t, &pb.Point{X: 0, Y: 0}, snake.Head(), "snake did not move left")
 }
+
+type slowFoodPlacer struct {
+	delay time.Duration
+}
+
+func (p slowFoodPlacer) PlaceFood(width, height int32, food []*pb.Point, snakes []*pb.Snake) *pb.Point {
+	time.Sleep(p.delay)
+	return nil
+}
+
+func TestGameTickDeadlineExceeded(t *testing.T) {
+	snake := &pb.Snake{
+		Health: 20,
+		Body: []*pb.Point{
+			{X: 1, Y: 1},
+			{X: 1, Y: 2},
+			{X: 1, Y: 3},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := GameTick(ctx, &pb.Game{Width: 20, Height: 20}, &pb.GameFrame{
+		Turn:   5,
+		Snakes: []*pb.Snake{snake},
+		Food:   []*pb.Point{{X: 1, Y: 0}},
+	}, WithFoodPlacer(slowFoodPlacer{delay: time.Second}))
+	require.True(t, errors.Is(err, ErrTickTimeout))
+	require.True(t, time.Since(start) < time.Second, "tick should not wait for the placer")
+}
+
+func TestGameTickLeavesLastFrame(t *testing.T) {
+	RegisterMoveRequester("bot", inProcessBot{move: "up"})
+	defer RegisterMoveRequester("bot", nil)
+	game := &pb.Game{Width: 5, Height: 5}
+	frame := &pb.GameFrame{
+		Turn: 1,
+		Food: []*pb.Point{{X: 1, Y: 0}},
+		Snakes: []*pb.Snake{
+			{ID: "1", URL: "bot://1", Health: 50, Body: []*pb.Point{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}}},
+		},
+	}
+	before := proto.Clone(frame).(*pb.GameFrame)
+
+	next, err := GameTick(context.Background(), game, frame)
+	require.NoError(t, err)
+	require.Equal(t, &pb.Point{X: 1, Y: 0}, next.Snakes[0].Head())
+	require.Equal(t, before, frame)
+}
+
+func TestAdvanceFrameCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	snake := &pb.Snake{ID: "1", Health: 50, Body: []*pb.Point{{X: 1, Y: 1}, {X: 1, Y: 2}}}
+	_, err := advanceFrame(ctx, &pb.Game{Width: 5, Height: 5}, &pb.GameFrame{Snakes: []*pb.Snake{snake}}, []*SnakeUpdate{{Snake: snake, Move: "up"}}, noFoodPlacer{})
+	require.True(t, errors.Is(err, ErrTickTimeout))
+
+	_, err = updateFood(ctx, 5, 5, &pb.GameFrame{}, nil, 1, RandomFoodPlacer{})
+	require.True(t, errors.Is(err, ErrTickTimeout))
+}
+
+func sharedFoodFrame() *pb.GameFrame {
+	return &pb.GameFrame{
+		Turn: 3,
+		Snakes: []*pb.Snake{
+			{
+				ID:     "1",
+				Health: 10,
+				Body: []*pb.Point{
+					{X: 5, Y: 5},
+					{X: 4, Y: 5},
+					{X: 3, Y: 5},
+				},
+			},
+			{
+				ID:     "2",
+				Health: 20,
+				Body: []*pb.Point{
+					{X: 5, Y: 5},
+					{X: 6, Y: 5},
+					{X: 7, Y: 5},
+				},
+			},
+		},
+		Food: []*pb.Point{{X: 5, Y: 5}, {X: 9, Y: 9}},
+	}
+}
+
+func TestCheckForSnakesEatingSharedFood(t *testing.T) {
+	var first *pb.GameFrame
+	for i := 0; i < 10; i++ {
+		frame := sharedFoodFrame()
+		foodToRemove := checkForSnakesEating(frame, &pb.Ruleset{MaxHealth: 100})
+		require.Equal(t, []*pb.Point{{X: 5, Y: 5}}, foodToRemove)
+
+		food, err := updateFood(context.Background(), 20, 20, frame, foodToRemove, len(foodToRemove), NewScriptedFoodPlacer([]*pb.Point{{X: 0, Y: 0}}))
+		require.NoError(t, err)
+		frame.Food = food
+
+		if first == nil {
+			first = frame
+			continue
+		}
+		require.True(t, first.Equal(frame), "frame differs on run %d", i)
+	}
+
+	for _, s := range first.Snakes {
+		require.Equal(t, int32(100), s.Health)
+		require.Len(t, s.Body, 3)
+	}
+	require.Equal(t, []*pb.Point{{X: 9, Y: 9}, {X: 0, Y: 0}}, first.Food)
+}
+
+func TestCheckForSnakesEatingStackedFood(t *testing.T) {
+	snake := &pb.Snake{
+		ID:     "1",
+		Health: 50,
+		Body:   []*pb.Point{{X: 5, Y: 5}, {X: 5, Y: 6}, {X: 5, Y: 7}},
+	}
+	frame := &pb.GameFrame{
+		Snakes: []*pb.Snake{snake},
+		Food:   []*pb.Point{{X: 5, Y: 5}, {X: 9, Y: 9}, {X: 5, Y: 5}},
+	}
+
+	foodToRemove := checkForSnakesEating(frame, &pb.Ruleset{MaxHealth: 100})
+	require.Equal(t, []*pb.Point{{X: 5, Y: 5}}, foodToRemove)
+	require.Len(t, snake.Body, 3, "the snake grows once")
+	require.Len(t, frame.Events, 1)
+
+	// One item is eaten and replaced, the other stays on its square.
+	food, err := updateFood(context.Background(), 20, 20, frame, foodToRemove, len(foodToRemove), NewScriptedFoodPlacer([]*pb.Point{{X: 0, Y: 0}}))
+	require.NoError(t, err)
+	require.Equal(t, []*pb.Point{{X: 9, Y: 9}, {X: 5, Y: 5}, {X: 0, Y: 0}}, food)
+}
+
+func TestCheckForSnakesEatingMaxSnakeLength(t *testing.T) {
+	// Both snakes have moved onto food, A was 3 long and B 2.
+	a := &pb.Snake{
+		ID:     "A",
+		Health: 50,
+		Body:   []*pb.Point{{X: 5, Y: 5}, {X: 5, Y: 6}, {X: 5, Y: 7}, {X: 5, Y: 8}},
+	}
+	b := &pb.Snake{
+		ID:     "B",
+		Health: 50,
+		Body:   []*pb.Point{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}},
+	}
+	frame := &pb.GameFrame{
+		Snakes: []*pb.Snake{a, b},
+		Food:   []*pb.Point{{X: 5, Y: 5}, {X: 1, Y: 1}},
+	}
+
+	foodToRemove := checkForSnakesEating(frame, &pb.Ruleset{MaxHealth: 100, MaxSnakeLength: 3})
+	require.Equal(t, []*pb.Point{{X: 5, Y: 5}, {X: 1, Y: 1}}, foodToRemove)
+	require.Equal(t, []*pb.Point{{X: 5, Y: 5}, {X: 5, Y: 6}, {X: 5, Y: 7}}, a.Body, "the snake at the cap does not grow")
+	require.Equal(t, int32(100), a.Health)
+	require.Len(t, b.Body, 3, "the snake below the cap grows")
+	require.Len(t, frame.Events, 2)
+}
+
+func TestStarvationStartTurn(t *testing.T) {
+	RegisterMoveRequester("bot", inProcessBot{move: "down"})
+	defer RegisterMoveRequester("bot", nil)
+
+	snake := &pb.Snake{
+		ID:     "1",
+		URL:    "bot://1",
+		Health: 100,
+		Body:   []*pb.Point{{X: 1, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 0}},
+	}
+	game := &pb.Game{
+		Width:   20,
+		Height:  20,
+		Ruleset: &pb.Ruleset{StarvationStartTurn: 10},
+	}
+	frame := &pb.GameFrame{Snakes: []*pb.Snake{snake}}
+
+	var err error
+	for turn := int32(1); turn < 10; turn++ {
+		frame, err = GameTick(context.Background(), game, frame)
+		require.NoError(t, err)
+		require.Equal(t, int32(100), frame.Snakes[0].Health, "health dropped on turn %d", turn)
+	}
+	frame, err = GameTick(context.Background(), game, frame)
+	require.NoError(t, err)
+	require.Equal(t, int32(10), frame.Turn)
+	require.Equal(t, int32(99), frame.Snakes[0].Health)
+}
+
+func TestAdvanceFrameEvents(t *testing.T) {
+	eater := &pb.Snake{
+		ID:     "eater",
+		Health: 50,
+		Body:   []*pb.Point{{X: 5, Y: 5}, {X: 5, Y: 6}, {X: 5, Y: 7}},
+	}
+	walled := &pb.Snake{
+		ID:     "walled",
+		Health: 50,
+		Body:   []*pb.Point{{X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+	}
+	frame := &pb.GameFrame{
+		Turn:   3,
+		Snakes: []*pb.Snake{eater, walled},
+		Food:   []*pb.Point{{X: 5, Y: 4}},
+	}
+	moves := []*SnakeUpdate{
+		{Snake: eater, Move: "up"},
+		{Snake: walled, Move: "left"},
+	}
+
+	next, err := advanceFrame(context.Background(), &pb.Game{Width: 10, Height: 10}, frame, moves, NewScriptedFoodPlacer([]*pb.Point{{X: 8, Y: 8}}))
+	require.NoError(t, err)
+	require.Equal(t, []*pb.Event{
+		{Type: FrameEventDied, SnakeID: "walled", Point: &pb.Point{X: -1, Y: 2}, Cause: DeathCauseWallCollision},
+		{Type: FrameEventAteFood, SnakeID: "eater", Point: &pb.Point{X: 5, Y: 4}},
+		{Type: FrameEventFoodSpawned, Point: &pb.Point{X: 8, Y: 8}},
+	}, next.Events)
+}
+
+func TestAdvanceFrameFollowingTail(t *testing.T) {
+	followTail := func(food []*pb.Point) (*pb.Snake, *pb.Snake) {
+		a := &pb.Snake{
+			ID:     "a",
+			Health: 50,
+			Body:   []*pb.Point{{X: 5, Y: 5}, {X: 6, Y: 5}, {X: 7, Y: 5}},
+		}
+		b := &pb.Snake{
+			ID:     "b",
+			Health: 50,
+			Body:   []*pb.Point{{X: 7, Y: 6}, {X: 8, Y: 6}, {X: 9, Y: 6}},
+		}
+		frame := &pb.GameFrame{Turn: 3, Snakes: []*pb.Snake{a, b}, Food: food}
+		moves := []*SnakeUpdate{
+			{Snake: a, Move: "up"},
+			{Snake: b, Move: "up"},
+		}
+		game := &pb.Game{Width: 10, Height: 10, RulesetVersion: RulesetVersion3}
+		_, err := advanceFrame(context.Background(), game, frame, moves, noFoodPlacer{})
+		require.NoError(t, err)
+		return a, b
+	}
+
+	// The tail of a moves out of the way of b.
+	a, b := followTail(nil)
+	require.Nil(t, b.Death)
+	require.Len(t, a.Body, 3)
+
+	// a eats and keeps its tail, b runs into it.
+	a, b = followTail([]*pb.Point{{X: 5, Y: 4}})
+	require.NotNil(t, b.Death)
+	require.Equal(t, DeathCauseSnakeCollision, b.Death.Cause)
+	require.Equal(t, "a", b.Death.EliminatedBy)
+	require.Len(t, a.Body, 4)
+}
+
+// advanceShuffled advances a copy of frame with moves handed over in an order
+// that depends on run, the way snakes answer in a different order every turn.
+func advanceShuffled(t *testing.T, game *pb.Game, frame *pb.GameFrame, moves map[string]string, run int) *pb.GameFrame {
+	frame = proto.Clone(frame).(*pb.GameFrame)
+	updates := []*SnakeUpdate{}
+	for i := range frame.Snakes {
+		s := frame.Snakes[(i+run)%len(frame.Snakes)]
+		updates = append(updates, &SnakeUpdate{Snake: s, Move: moves[s.ID]})
+	}
+	next, err := advanceFrame(context.Background(), game, frame, updates, foodPlacerForTurn(game.Ruleset, game.Seed, frame.Turn+1, nil, nil))
+	require.NoError(t, err)
+	return next
+}
+
+func TestAdvanceFrameDeterministicOrder(t *testing.T) {
+	game := &pb.Game{Width: 7, Height: 7, Seed: 42, RulesetVersion: RulesetVersion4}
+	frame := &pb.GameFrame{
+		Turn: 5,
+		Food: []*pb.Point{{X: 5, Y: 4}},
+		// Listed out of ID order on purpose.
+		Snakes: []*pb.Snake{
+			{ID: "c", Health: 50, Body: []*pb.Point{{X: 0, Y: 3}, {X: 1, Y: 3}, {X: 2, Y: 3}}},
+			{ID: "a", Health: 50, Body: []*pb.Point{{X: 3, Y: 0}, {X: 3, Y: 1}, {X: 3, Y: 2}}},
+			{ID: "b", Health: 50, Body: []*pb.Point{{X: 5, Y: 5}, {X: 5, Y: 6}, {X: 6, Y: 6}}},
+		},
+	}
+	moves := map[string]string{"a": "up", "b": "up", "c": "left"}
+
+	first := advanceShuffled(t, game, frame, moves, 0)
+	for run := 1; run < 10; run++ {
+		require.Equal(t, first, advanceShuffled(t, game, frame, moves, run), "run %d", run)
+	}
+
+	// Snakes keep their place in the frame, events follow their IDs.
+	require.Equal(t, []string{"c", "a", "b"}, []string{first.Snakes[0].ID, first.Snakes[1].ID, first.Snakes[2].ID})
+	require.Len(t, first.Events, 4)
+	require.Equal(t, &pb.Event{Type: FrameEventDied, SnakeID: "a", Point: &pb.Point{X: 3, Y: -1}, Cause: DeathCauseWallCollision}, first.Events[0])
+	require.Equal(t, &pb.Event{Type: FrameEventDied, SnakeID: "c", Point: &pb.Point{X: -1, Y: 3}, Cause: DeathCauseWallCollision}, first.Events[1])
+	require.Equal(t, &pb.Event{Type: FrameEventAteFood, SnakeID: "b", Point: &pb.Point{X: 5, Y: 4}}, first.Events[2])
+	require.Equal(t, FrameEventFoodSpawned, first.Events[3].Type)
+
+	// Before RulesetVersion4 the events follow the frame.
+	game.RulesetVersion = RulesetVersion3
+	older := advanceShuffled(t, game, frame, moves, 1)
+	require.Equal(t, "c", older.Events[0].SnakeID)
+	require.Equal(t, "a", older.Events[1].SnakeID)
+}
+
+func TestGameTickObstacles(t *testing.T) {
+	game := &pb.Game{
+		Width:     5,
+		Height:    5,
+		Obstacles: []*pb.Point{{X: 2, Y: 1}},
+		Ruleset:   &pb.Ruleset{MaxHealth: 100},
+	}
+	frame := &pb.GameFrame{
+		Snakes: []*pb.Snake{
+			{ID: "1", Health: 50, Body: []*pb.Point{{X: 2, Y: 2}, {X: 2, Y: 3}}},
+		},
+	}
+	next, err := advanceFrame(context.Background(), game, frame, []*SnakeUpdate{{Snake: frame.Snakes[0], Move: "up"}}, noFoodPlacer{})
+	require.NoError(t, err)
+	require.NotNil(t, next.Snakes[0].Death)
+	require.Equal(t, DeathCauseObstacleCollision, next.Snakes[0].Death.Cause)
+	require.True(t, moveIsFatal(game, frame, &SnakeUpdate{Snake: &pb.Snake{Health: 50, Body: []*pb.Point{{X: 2, Y: 2}}}, Move: "up"}, StandardRuleset()))
+}
+
+// harnessBot answers with the move set for the snake in moves.
+type harnessBot struct {
+	moves map[string]string
+}
+
+func (b harnessBot) RequestMove(ctx context.Context, snake *pb.Snake, payload SnakeRequest) (MoveResponse, error) {
+	return MoveResponse{Move: b.moves[snake.ID]}, nil
+}
+
+// harnessFrames returns every frame of a 5x5 board with snake A, 3 long, in
+// the middle and snake B, 2 long, anywhere next to it. Food lies on the
+// squares A can move to, so snakes meet on food, and B reaches the tail of A.
+func harnessFrames() []*pb.GameFrame {
+	a := []*pb.Point{{X: 2, Y: 2}, {X: 2, Y: 3}, {X: 2, Y: 4}}
+	food := []*pb.Point{{X: 1, Y: 2}, {X: 3, Y: 2}, {X: 2, Y: 1}}
+	frames := []*pb.GameFrame{}
+	for y := int32(0); y < 5; y++ {
+		for x := int32(0); x < 5; x++ {
+			head := &pb.Point{X: x, Y: y}
+			for _, neck := range []*pb.Point{{X: x - 1, Y: y}, {X: x + 1, Y: y}, {X: x, Y: y - 1}, {X: x, Y: y + 1}} {
+				b := []*pb.Point{head, neck}
+				if deathByOutOfBounds(neck, 5, 5) || containsPoint(a, head) || containsPoint(a, neck) {
+					continue
+				}
+				frames = append(frames, &pb.GameFrame{
+					Turn: 1,
+					Food: food,
+					Snakes: []*pb.Snake{
+						{ID: "A", URL: "bot://A", Health: 50, Body: a},
+						{ID: "B", URL: "bot://B", Health: 50, Body: b},
+					},
+				})
+			}
+		}
+	}
+	return frames
+}
+
+// TestGameTickInvariants plays every move of both snakes from every frame of
+// harnessFrames and checks the frame GameTick returns.
+func TestGameTickInvariants(t *testing.T) {
+	level := log.GetLevel()
+	log.SetLevel(log.WarnLevel)
+	defer log.SetLevel(level)
+	moves := []string{"up", "down", "left", "right"}
+
+	for name, ruleset := range map[string]*pb.Ruleset{
+		"standard":   StandardRuleset(),
+		"max length": func() *pb.Ruleset { r := StandardRuleset(); r.MaxSnakeLength = 3; return r }(),
+	} {
+		for _, frame := range harnessFrames() {
+			for _, moveA := range moves {
+				for _, moveB := range moves {
+					bot := harnessBot{moves: map[string]string{"A": moveA, "B": moveB}}
+					RegisterMoveRequester("bot", bot)
+					game := &pb.Game{ID: "harness", Width: 5, Height: 5, SnakeTimeout: 200, Ruleset: ruleset, RulesetVersion: CurrentRulesetVersion}
+					last := proto.Clone(frame).(*pb.GameFrame)
+					next, err := GameTick(context.Background(), game, proto.Clone(frame).(*pb.GameFrame))
+					require.NoError(t, err)
+					checkTickInvariants(t, fmt.Sprintf("%s: A %s, B %s from\n%s", name, moveA, moveB, RenderFrame(game, last)), game, last, next)
+				}
+			}
+		}
+	}
+	RegisterMoveRequester("bot", nil)
+}
+
+// checkTickInvariants checks next, the frame played from last:
+//   - alive snakes are on the board, and no two alive snakes share a head
+//   - a snake that moves onto food eats it, a single item is removed, and
+//     food is not removed anywhere else
+//   - health goes down by one every turn, except for snakes that ate, which
+//     regain all of it, and snakes grow only when they eat
+//   - a snake dies by collision only where a body or head is left
+func checkTickInvariants(t *testing.T, scenario string, game *pb.Game, last, next *pb.GameFrame) {
+	ruleset := gameRuleset(game)
+	before := map[string]*pb.Snake{}
+	for _, s := range last.Snakes {
+		before[s.ID] = s
+	}
+	heads := map[pb.Point]int{}
+	for _, s := range next.AliveSnakes() {
+		for _, p := range s.Body {
+			require.False(t, deathByOutOfBounds(p, game.Width, game.Height), "%s alive off the board in %s", s.ID, scenario)
+		}
+		heads[*s.Head()]++
+		require.Equal(t, 1, heads[*s.Head()], "%s shares its head in %s", s.ID, scenario)
+	}
+
+	count := func(food []*pb.Point, p *pb.Point) int {
+		n := 0
+		for _, f := range food {
+			if f.Equal(p) {
+				n++
+			}
+		}
+		return n
+	}
+	for _, s := range next.AliveSnakes() {
+		old := before[s.ID]
+		ate := count(last.Food, s.Head()) > 0
+		if ate {
+			require.Equal(t, count(last.Food, s.Head())-1, count(next.Food, s.Head()), "food eaten by %s left in %s", s.ID, scenario)
+			require.Equal(t, ruleset.MaxHealth, s.Health, "%s ate in %s", s.ID, scenario)
+		} else {
+			require.Equal(t, old.Health-1, s.Health, "%s did not eat in %s", s.ID, scenario)
+		}
+		grows := ate && !(ruleset.MaxSnakeLength > 0 && int32(len(old.Body)) >= ruleset.MaxSnakeLength)
+		if grows {
+			require.Len(t, s.Body, len(old.Body)+1, "%s ate in %s", s.ID, scenario)
+		} else {
+			require.Len(t, s.Body, len(old.Body), "%s did not grow in %s", s.ID, scenario)
+		}
+	}
+	for _, f := range last.Food {
+		eaten := false
+		for _, s := range next.AliveSnakes() {
+			eaten = eaten || s.Head().Equal(f)
+		}
+		if !eaten {
+			require.True(t, count(next.Food, f) >= count(last.Food, f), "food removed from %v in %s", f, scenario)
+		}
+	}
+
+	for _, s := range next.Snakes {
+		if s.Death == nil || s.Death.Turn != next.Turn {
+			continue
+		}
+		switch s.Death.Cause {
+		case DeathCauseSnakeCollision, DeathCauseSnakeSelfCollision, DeathCauseHeadToHeadCollision:
+		default:
+			continue
+		}
+		hit := false
+		for _, other := range next.Snakes {
+			if other.Death != nil && other.Death.Turn < next.Turn {
+				continue
+			}
+			for i, p := range other.Body {
+				if (other != s || i > 0) && p.Equal(s.Head()) {
+					hit = true
+				}
+			}
+		}
+		require.True(t, hit, "%s died by %s on an empty square in %s", s.ID, s.Death.Cause, scenario)
+	}
+}
@@ -0,0 +1,62 @@
+package rules
+
+import "github.com/battlesnakeio/engine/controller/pb"
+
+// wrapSnakes moves the heads of snakes that left the board over a wrapping
+// edge back onto the board on the opposite side. Each axis wraps on its own,
+// so a board can wrap left to right while keeping solid top and bottom walls.
+// Heads that leave over a solid edge are left off the board and die against
+// the wall.
+func wrapSnakes(frame *pb.GameFrame, width, height int32, ruleset *pb.Ruleset) {
+	if !ruleset.GetWrapHorizontal() && !ruleset.GetWrapVertical() {
+		return
+	}
+	for _, s := range frame.AliveSnakes() {
+		if head := s.Head(); head != nil {
+			wrapPoint(head, width, height, ruleset)
+		}
+	}
+}
+
+// wrapPoint moves p back onto the board over the edges the ruleset wraps.
+func wrapPoint(p *pb.Point, width, height int32, ruleset *pb.Ruleset) {
+	if ruleset.GetWrapHorizontal() {
+		p.X = wrap(p.X, width)
+	}
+	if ruleset.GetWrapVertical() {
+		p.Y = wrap(p.Y, height)
+	}
+}
+
+func wrap(v, size int32) int32 {
+	if size <= 0 {
+		return v
+	}
+	return (v%size + size) % size
+}
+
+// unwrapPoint returns to as seen from from on an unwrapped board, so a head
+// that crossed a wrapping edge is one step away from where it came from.
+func unwrapPoint(from, to *pb.Point, width, height int32, ruleset *pb.Ruleset) *pb.Point {
+	if from == nil || to == nil {
+		return to
+	}
+	p := to.Clone()
+	if ruleset.GetWrapHorizontal() {
+		p.X = unwrap(from.X, to.X, width)
+	}
+	if ruleset.GetWrapVertical() {
+		p.Y = unwrap(from.Y, to.Y, height)
+	}
+	return p
+}
+
+func unwrap(from, to, size int32) int32 {
+	switch {
+	case to-from == size-1:
+		return to - size
+	case from-to == size-1:
+		return to + size
+	}
+	return to
+}
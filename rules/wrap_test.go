@@ -0,0 +1,163 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/battlesnakeio/engine/controller/pb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapHorizontalOnly(t *testing.T) {
+	game := &pb.Game{
+		Width:   5,
+		Height:  5,
+		Ruleset: &pb.Ruleset{WrapHorizontal: true},
+	}
+	left := &pb.Snake{
+		ID:     "left",
+		Health: 100,
+		Body:   []*pb.Point{{X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+	}
+	up := &pb.Snake{
+		ID:     "up",
+		Health: 100,
+		Body:   []*pb.Point{{X: 3, Y: 0}, {X: 3, Y: 1}, {X: 3, Y: 2}},
+	}
+	frame := &pb.GameFrame{Snakes: []*pb.Snake{left, up}}
+	moves := []*SnakeUpdate{
+		{Snake: left, Move: "left"},
+		{Snake: up, Move: "up"},
+	}
+
+	next, err := advanceFrame(context.Background(), game, frame, moves, noFoodPlacer{})
+	require.NoError(t, err)
+	require.Nil(t, left.Death)
+	require.Equal(t, &pb.Point{X: 4, Y: 2}, left.Head())
+	require.NotNil(t, up.Death)
+	require.Equal(t, DeathCauseWallCollision, up.Death.Cause)
+	require.Equal(t, int32(1), next.Turn)
+}
+
+func TestWrapHeadToHeadAcrossEdge(t *testing.T) {
+	game := &pb.Game{
+		Width:   5,
+		Height:  5,
+		Ruleset: &pb.Ruleset{WrapHorizontal: true},
+	}
+	long := &pb.Snake{
+		ID:     "long",
+		Health: 100,
+		Body:   []*pb.Point{{X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 3}},
+	}
+	short := &pb.Snake{
+		ID:     "short",
+		Health: 100,
+		Body:   []*pb.Point{{X: 3, Y: 2}, {X: 3, Y: 3}, {X: 3, Y: 4}},
+	}
+	frame := &pb.GameFrame{Snakes: []*pb.Snake{long, short}}
+	moves := []*SnakeUpdate{
+		{Snake: long, Move: "left"},
+		{Snake: short, Move: "right"},
+	}
+
+	// The long snake crosses the left edge and meets the short one on the
+	// right edge.
+	_, err := advanceFrame(context.Background(), game, frame, moves, noFoodPlacer{})
+	require.NoError(t, err)
+	require.Equal(t, &pb.Point{X: 4, Y: 2}, long.Head())
+	require.Nil(t, long.Death)
+	require.NotNil(t, short.Death)
+	require.Equal(t, DeathCauseHeadToHeadCollision, short.Death.Cause)
+	require.Equal(t, "long", short.Death.EliminatedBy)
+}
+
+func TestWrapVertical(t *testing.T) {
+	game := &pb.Game{
+		Width:   5,
+		Height:  5,
+		Ruleset: &pb.Ruleset{WrapVertical: true},
+	}
+	snake := &pb.Snake{
+		ID:     "1",
+		Health: 100,
+		Body:   []*pb.Point{{X: 3, Y: 4}, {X: 3, Y: 3}, {X: 3, Y: 2}},
+	}
+	frame := &pb.GameFrame{Snakes: []*pb.Snake{snake}}
+
+	_, err := advanceFrame(context.Background(), game, frame, []*SnakeUpdate{{Snake: snake, Move: "down"}}, noFoodPlacer{})
+	require.NoError(t, err)
+	require.Nil(t, snake.Death)
+	require.Equal(t, &pb.Point{X: 3, Y: 0}, snake.Head())
+}
+
+func TestWrapTorus(t *testing.T) {
+	game := &pb.Game{
+		Width:   5,
+		Height:  5,
+		Ruleset: &pb.Ruleset{WrapHorizontal: true, WrapVertical: true},
+	}
+	left := &pb.Snake{
+		ID:     "left",
+		Health: 100,
+		Body:   []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}},
+	}
+	up := &pb.Snake{
+		ID:     "up",
+		Health: 100,
+		Body:   []*pb.Point{{X: 3, Y: 1}, {X: 3, Y: 2}, {X: 3, Y: 3}},
+	}
+	frame := &pb.GameFrame{Snakes: []*pb.Snake{left, up}}
+	moves := []*SnakeUpdate{
+		{Snake: left, Move: "left"},
+		{Snake: up, Move: "up"},
+	}
+
+	_, err := advanceFrame(context.Background(), game, frame, moves, noFoodPlacer{})
+	require.NoError(t, err)
+	require.Nil(t, left.Death)
+	require.Equal(t, &pb.Point{X: 4, Y: 0}, left.Head())
+
+	// Both edges of the corner wrap, there are no walls left on a torus.
+	moves = []*SnakeUpdate{
+		{Snake: left, Move: "up"},
+		{Snake: up, Move: "up"},
+	}
+	_, err = advanceFrame(context.Background(), game, frame, moves, noFoodPlacer{})
+	require.NoError(t, err)
+	require.Nil(t, left.Death)
+	require.Equal(t, &pb.Point{X: 4, Y: 4}, left.Head())
+	require.Nil(t, up.Death)
+}
+
+func TestVerifyReplayWrapped(t *testing.T) {
+	RegisterMoveRequester("bot", inProcessBot{move: "left"})
+	defer RegisterMoveRequester("bot", nil)
+
+	game := &pb.Game{
+		Width:        5,
+		Height:       5,
+		SnakeTimeout: 100,
+		Ruleset:      &pb.Ruleset{WrapHorizontal: true},
+	}
+	frame := &pb.GameFrame{
+		Snakes: []*pb.Snake{{
+			ID:     "1",
+			URL:    "bot://1",
+			Health: 100,
+			Body:   []*pb.Point{{X: 1, Y: 2}, {X: 1, Y: 2}, {X: 1, Y: 2}},
+		}},
+	}
+	frames := []*pb.GameFrame{proto.Clone(frame).(*pb.GameFrame)}
+	for i := 0; i < 4; i++ {
+		var err error
+		frame, err = GameTick(context.Background(), game, frame)
+		require.NoError(t, err)
+		frames = append(frames, proto.Clone(frame).(*pb.GameFrame))
+	}
+	require.Nil(t, frame.Snakes[0].Death)
+	require.Equal(t, &pb.Point{X: 2, Y: 2}, frame.Snakes[0].Head())
+
+	require.NoError(t, VerifyReplay(game, frames))
+}
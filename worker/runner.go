@@ -6,6 +6,8 @@ import (
 	"github.com/battlesnakeio/engine/controller/pb"
 	"github.com/battlesnakeio/engine/rules"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Runner will run an invidual game to completion. It takes a game id and a
@@ -21,7 +23,7 @@ func Runner(ctx context.Context, client pb.ControllerClient, id string) error {
 		if lastFrame != nil && lastFrame.Turn == 0 {
 			rules.NotifyGameStart(resp.Game, lastFrame)
 		}
-		nextFrame, err := rules.GameTick(resp.Game, lastFrame)
+		nextFrame, err := rules.GameTick(ctx, resp.Game, lastFrame)
 		if err != nil {
 			// This is a GameFrame error, we can assume that this is a fatal
 			// error and no more game processing can take place at this point.
@@ -39,18 +41,38 @@ func Runner(ctx context.Context, client pb.ControllerClient, id string) error {
 		log.WithField("GameID", id).
 			WithField("Turn", nextFrame.Turn).
 			Info("adding game frame")
-		_, err = client.AddGameFrame(ctx, &pb.AddGameFrameRequest{
+		added, err := client.AddGameFrame(ctx, &pb.AddGameFrameRequest{
 			ID:        resp.Game.ID,
 			GameFrame: nextFrame,
 		})
+		if status.Code(err) == codes.FailedPrecondition {
+			// The game was paused or aborted while we were processing it, the
+			// controller released our lock so a paused game is picked up
+			// again once resumed.
+			return nil
+		}
 		if err != nil {
 			// This is likely a lock error, not to worry here, we can exit.
 			return err
 		}
+		rules.EmitFrameEvents(resp.Game, nextFrame)
+		if added.GetGame().GetStatus() != string(rules.GameStatusRunning) {
+			// The game was stopped while we were processing it.
+			return nil
+		}
 
-		if rules.CheckForGameOver(rules.GameMode(resp.Game.Mode), nextFrame) {
+		mode := rules.GameModeOf(resp.Game)
+		if rules.CheckForGameOver(mode, nextFrame) &&
+			!rules.RespawnPending(resp.Game, nextFrame) {
+			result := rules.GetGameResult(mode, nextFrame)
 			log.WithField("GameID", id).
 				WithField("Turn", nextFrame.Turn).
+				WithFields(log.Fields{
+					"Solo":   result.Solo,
+					"Score":  result.Score,
+					"Winner": result.WinnerID,
+					"Draw":   result.Draw,
+				}).
 				Info("ending game")
 			rules.NotifyGameEnd(resp.Game, nextFrame)
 			_, err := client.EndGame(ctx, &pb.EndGameRequest{ID: resp.Game.ID})
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -55,8 +56,7 @@ func TestWorker_RunnerErrors(t *testing.T) {
 		require.NoError(t, err)
 
 		err = w.run(ctx, 1)
-		require.NotNil(t, err)
-		require.Equal(t, "rules: invalid state, previous frame is nil", err.Error())
+		require.True(t, errors.Is(err, rules.ErrNilFrame))
 	})
 
 	t.Run("GameFrameLocked", func(t *testing.T) {
@@ -5,6 +5,8 @@ package worker
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/battlesnakeio/engine/controller/pb"
@@ -42,7 +44,7 @@ func (w *Worker) Run(ctx context.Context, workerID int) {
 
 func (w *Worker) run(ctx context.Context, workerID int) error {
 	// Pop an item of work.
-	pop, err := w.ControllerClient.Pop(ctx, &pb.PopRequest{})
+	pop, err := w.ControllerClient.Pop(ctx, &pb.PopRequest{WorkerID: workerName(workerID)})
 	if err != nil {
 		return err
 	}
@@ -61,3 +63,13 @@ func (w *Worker) run(ctx context.Context, workerID int) error {
 	// a valid lock for the key.
 	return w.RunGame(ctx, w.ControllerClient, pop.ID)
 }
+
+// workerName identifies a worker across hosts, it is recorded in the locks the
+// worker holds.
+func workerName(workerID int) string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, workerID)
+}
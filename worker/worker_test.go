@@ -95,6 +95,58 @@ func TestWorker_Run(t *testing.T) {
 		require.Nil(t, err)
 	})
 
+	t.Run("PauseResume", func(t *testing.T) {
+		gameID := setup()
+		w.RunGame = func(c context.Context, cl pb.ControllerClient, id string) error {
+			_, err := cl.AddGameFrame(c, &pb.AddGameFrameRequest{
+				ID:        id,
+				GameFrame: &pb.GameFrame{Turn: 1},
+			})
+			require.NoError(t, err)
+			_, err = cl.PauseGame(c, &pb.PauseGameRequest{ID: id})
+			require.NoError(t, err)
+			_, err = cl.AddGameFrame(c, &pb.AddGameFrameRequest{
+				ID:        id,
+				GameFrame: &pb.GameFrame{Turn: 2},
+			})
+			return err
+		}
+		err := w.run(ctx, 1)
+		require.Equal(t,
+			"rpc error: code = FailedPrecondition desc = controller: game is paused",
+			err.Error(),
+		)
+
+		// The paused game is released but no worker picks it up.
+		err = w.run(ctx, 1)
+		require.Equal(t,
+			"rpc error: code = NotFound desc = controller: game not found",
+			err.Error(),
+		)
+		frames, err := store.ListGameFrames(ctx, gameID, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, frames, 2)
+
+		_, err = client.ResumeGame(ctx, &pb.ResumeGameRequest{ID: gameID})
+		require.NoError(t, err)
+		w.RunGame = func(c context.Context, cl pb.ControllerClient, id string) error {
+			_, err := cl.AddGameFrame(c, &pb.AddGameFrameRequest{
+				ID:        id,
+				GameFrame: &pb.GameFrame{Turn: 2},
+			})
+			if err != nil {
+				return err
+			}
+			_, err = cl.EndGame(c, &pb.EndGameRequest{ID: id})
+			return err
+		}
+		err = w.run(ctx, 1)
+		require.NoError(t, err)
+		frames, err = store.ListGameFrames(ctx, gameID, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, frames, 3)
+	})
+
 	t.Run("PushGameFrameTimeout", func(t *testing.T) {
 		gameID := setup()
 		w.RunGame = func(c context.Context, cl pb.ControllerClient, id string) error {